@@ -1,4 +1,4 @@
-import { RequestRow, ApprovePayload, ReleasePayload, ReleaseResponse } from '../types/authority';
+import { RequestRow, ApprovePayload, ReleasePayload, ReleaseResponse, AssignmentReport, Assignment, SweepReport, Reassignment, WorkloadReport, RemunerationReport, RemunerationStatement } from '../types/authority';
 
 const API_BASE_URL = 'http://127.0.0.1:8443/api/v1';
 
@@ -30,7 +30,7 @@ export const fetchRequestHistory = async (): Promise<RequestRow[]> => {
   return response.json();
 };
 
-export const approveRequest = async (id: number, payload: ApprovePayload): Promise<{ assigned: number }> => {
+export const approveRequest = async (id: number, payload: ApprovePayload): Promise<{ assigned: string[]; report: AssignmentReport }> => {
   const response = await fetch(`${API_BASE_URL}/authority/requests/${id}/approve`, {
     method: 'POST',
     headers: getAuthHeaders(),
@@ -64,4 +64,128 @@ export const releaseResults = async (payload: ReleasePayload): Promise<ReleaseRe
     throw new Error(`Failed to release results: ${errorText}`);
   }
   return response.json();
-};
\ No newline at end of file
+};
+
+export const fetchAssignments = async (params: { status?: string; course_id?: string; overdue?: boolean }): Promise<Assignment[]> => {
+  const q = new URLSearchParams();
+  if (params.status) q.set('status', params.status);
+  if (params.course_id) q.set('course_id', params.course_id);
+  if (params.overdue) q.set('overdue', 'true');
+  const response = await fetch(`${API_BASE_URL}/authority/assignments?${q.toString()}`, {
+    headers: getAuthHeaders(),
+  });
+  if (!response.ok) {
+    throw new Error('Failed to fetch assignments');
+  }
+  return response.json();
+};
+
+export const fetchAssignmentPool = async (): Promise<Assignment[]> => {
+  const response = await fetch(`${API_BASE_URL}/authority/assignments/pool`, {
+    headers: getAuthHeaders(),
+  });
+  if (!response.ok) {
+    throw new Error('Failed to fetch revoked scripts');
+  }
+  return response.json();
+};
+
+export const runAssignmentSweep = async (): Promise<SweepReport> => {
+  const response = await fetch(`${API_BASE_URL}/authority/assignments/sweep`, {
+    method: 'POST',
+    headers: getAuthHeaders(),
+  });
+  if (!response.ok) {
+    throw new Error(`Sweep failed: ${await response.text()}`);
+  }
+  return response.json();
+};
+
+export const extendAssignment = async (id: number, dueAt: string, authorityId: string): Promise<void> => {
+  const response = await fetch(`${API_BASE_URL}/authority/assignments/${id}/extend`, {
+    method: 'POST',
+    headers: getAuthHeaders(),
+    body: JSON.stringify({ due_at: dueAt, authority_id: authorityId }),
+  });
+  if (!response.ok) {
+    throw new Error(`Failed to extend deadline: ${await response.text()}`);
+  }
+};
+
+export const revokeAssignment = async (id: number, reason: string, authorityId: string): Promise<{ status: string; reassigned: Reassignment | null }> => {
+  const response = await fetch(`${API_BASE_URL}/authority/assignments/${id}/revoke`, {
+    method: 'POST',
+    headers: getAuthHeaders(),
+    body: JSON.stringify({ reason, authority_id: authorityId }),
+  });
+  if (!response.ok) {
+    throw new Error(`Failed to revoke assignment: ${await response.text()}`);
+  }
+  return response.json();
+};
+
+export const fetchWorkload = async (params: { semester?: string; academic_year?: string; course_id?: string; window_days?: number }): Promise<WorkloadReport> => {
+  const q = new URLSearchParams();
+  if (params.semester) q.set('semester', params.semester);
+  if (params.academic_year) q.set('academic_year', params.academic_year);
+  if (params.course_id) q.set('course_id', params.course_id);
+  if (params.window_days) q.set('window_days', String(params.window_days));
+  const response = await fetch(`${API_BASE_URL}/authority/workload?${q.toString()}`, {
+    headers: getAuthHeaders(),
+  });
+  if (!response.ok) {
+    throw new Error(`Failed to fetch workload: ${await response.text()}`);
+  }
+  return response.json();
+};
+
+export const computeRemuneration = async (semester: string, academicYear: string): Promise<RemunerationReport> => {
+  const response = await fetch(`${API_BASE_URL}/authority/remuneration/compute`, {
+    method: 'POST',
+    headers: getAuthHeaders(),
+    body: JSON.stringify({ semester, academic_year: academicYear }),
+  });
+  if (!response.ok) {
+    throw new Error(`Failed to compute remuneration: ${await response.text()}`);
+  }
+  return response.json();
+};
+
+export const fetchRemunerationStatements = async (params: { semester?: string; academic_year?: string; evaluator_id?: string; status?: string }): Promise<RemunerationStatement[]> => {
+  const q = new URLSearchParams();
+  if (params.semester) q.set('semester', params.semester);
+  if (params.academic_year) q.set('academic_year', params.academic_year);
+  if (params.evaluator_id) q.set('evaluator_id', params.evaluator_id);
+  if (params.status) q.set('status', params.status);
+  const response = await fetch(`${API_BASE_URL}/authority/remuneration/statements?${q.toString()}`, {
+    headers: getAuthHeaders(),
+  });
+  if (!response.ok) {
+    throw new Error('Failed to fetch remuneration statements');
+  }
+  return response.json();
+};
+
+export const approveRemunerationStatement = async (id: number, authorityId: string): Promise<RemunerationStatement> => {
+  const response = await fetch(`${API_BASE_URL}/authority/remuneration/statements/${id}/approve`, {
+    method: 'POST',
+    headers: getAuthHeaders(),
+    body: JSON.stringify({ authority_id: authorityId }),
+  });
+  if (!response.ok) {
+    throw new Error(`Failed to approve statement: ${await response.text()}`);
+  }
+  return response.json();
+};
+
+export const recordRemunerationPayout = async (id: number, payoutReference: string, authorityId: string): Promise<RemunerationStatement> => {
+  const response = await fetch(`${API_BASE_URL}/authority/remuneration/statements/${id}/payout`, {
+    method: 'POST',
+    headers: getAuthHeaders(),
+    body: JSON.stringify({ payout_reference: payoutReference, authority_id: authorityId }),
+  });
+  if (!response.ok) {
+    throw new Error(`Failed to record payout: ${await response.text()}`);
+  }
+  return response.json();
+};
@@ -6,6 +6,7 @@ import {
   ScriptMetadata,
   EvaluationSubmit,
   SubmitResponse,
+  AnnotatedUploadResponse,
 } from "../types/evaluator";
 
 export async function createEvaluationRequest(data: EvaluationRequestCreate): Promise<{ request_id: number }> {
@@ -33,10 +34,12 @@ export async function submitEvaluation(data: EvaluationSubmit): Promise<SubmitRe
   return response.data;
 }
 
-export async function uploadEvaluatedScript(file: File): Promise<{ cid: string; pdf_path: string; status: string }> {
+export async function uploadEvaluatedScript(file: File, scriptId: string, evaluatorId: string): Promise<AnnotatedUploadResponse> {
   const formData = new FormData();
   formData.append("file", file);
-  const response = await api.post<{ cid: string; pdf_path: string; status: string }>("/evaluator/upload", formData, {
+  formData.append("script_id", scriptId);
+  formData.append("evaluator_id", evaluatorId);
+  const response = await api.post<AnnotatedUploadResponse>("/evaluator/upload", formData, {
     headers: {
       "Content-Type": "multipart/form-data",
     },
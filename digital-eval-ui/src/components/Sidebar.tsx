@@ -23,6 +23,7 @@ const Sidebar: React.FC = () => {
     authority: [
       { label: "Pending Requests", to: "/dashboard/authority/requests" },
       { label: "Approve Requests", to: "/dashboard/authority/approve" },
+      { label: "Assignment Deadlines", to: "/dashboard/authority/assignments" },
       { label: "Release Results", to: "/dashboard/authority/release" },
     ],
     student: [
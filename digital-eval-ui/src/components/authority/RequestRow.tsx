@@ -4,18 +4,19 @@ import Card from '../Card';
 
 interface Props {
   request: RequestRowType;
-  onApprove: (id: number, assignNum: number) => Promise<void>;
+  onApprove: (id: number, assignNum: number, dueDays: number) => Promise<void>;
   onReject: (id: number) => Promise<void>;
 }
 
 const RequestRow: React.FC<Props> = ({ request, onApprove, onReject }) => {
   const [assignNum, setAssignNum] = useState(5);
+  const [dueDays, setDueDays] = useState(7);
   const [loading, setLoading] = useState(false);
 
   const handleApprove = async () => {
     setLoading(true);
     try {
-      await onApprove(request.id, assignNum);
+      await onApprove(request.id, assignNum, dueDays);
     } finally {
       setLoading(false);
     }
@@ -52,6 +53,18 @@ const RequestRow: React.FC<Props> = ({ request, onApprove, onReject }) => {
           <p className="text-sm text-gray-600">
             <span className="font-medium text-gray-700">Description:</span> {request.description}
           </p>
+          {request.eligibility && (
+            <p className={`text-sm ${request.eligibility.eligible ? 'text-green-700' : 'text-red-600'}`}>
+              <span className="font-medium">{request.eligibility.eligible ? 'Eligible' : 'Not eligible'}:</span>{' '}
+              {request.eligibility.reason}
+              {request.eligibility.eligible && request.eligibility.entry && (
+                <>
+                  {' '}({request.eligibility.entry.experience_years} yrs
+                  {request.eligibility.entry.valid_until ? `, until ${request.eligibility.entry.valid_until}` : ''})
+                </>
+              )}
+            </p>
+          )}
         </div>
 
         <div className="flex flex-col gap-3 md:items-end">
@@ -67,6 +80,17 @@ const RequestRow: React.FC<Props> = ({ request, onApprove, onReject }) => {
               onChange={(e) => setAssignNum(parseInt(e.target.value) || 0)}
               className="w-20 rounded-md border border-gray-300 px-2 py-1 text-sm focus:border-indigo-500 focus:outline-none focus:ring-1 focus:ring-indigo-500"
             />
+            <label htmlFor={`due-${request.id}`} className="text-sm font-medium text-gray-700">
+              Due in days:
+            </label>
+            <input
+              id={`due-${request.id}`}
+              type="number"
+              min="1"
+              value={dueDays}
+              onChange={(e) => setDueDays(parseInt(e.target.value) || 0)}
+              className="w-20 rounded-md border border-gray-300 px-2 py-1 text-sm focus:border-indigo-500 focus:outline-none focus:ring-1 focus:ring-indigo-500"
+            />
           </div>
           <div className="flex gap-2">
             <button
@@ -78,7 +102,7 @@ const RequestRow: React.FC<Props> = ({ request, onApprove, onReject }) => {
             </button>
             <button
               onClick={handleApprove}
-              disabled={loading}
+              disabled={loading || request.eligibility?.eligible === false}
               className="rounded-md bg-indigo-600 px-4 py-2 text-sm font-medium text-white shadow-sm hover:bg-indigo-700 disabled:opacity-50"
             >
               {loading ? 'Processing...' : 'Approve'}
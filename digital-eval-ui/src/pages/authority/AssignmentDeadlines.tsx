@@ -0,0 +1,250 @@
+import React, { useEffect, useState } from 'react';
+import {
+  fetchAssignments,
+  fetchAssignmentPool,
+  runAssignmentSweep,
+  extendAssignment,
+  revokeAssignment,
+} from '../../api/authority';
+import { Assignment, SweepReport } from '../../types/authority';
+import { useAuth } from '../../hooks/useAuth';
+import Card from '../../components/Card';
+
+const STATUSES = ['', 'assigned', 'in_progress', 'evaluated', 'revoked'];
+
+const statusBadge = (a: Assignment) => {
+  if (a.overdue) return 'bg-red-100 text-red-700';
+  switch (a.status) {
+    case 'in_progress':
+      return 'bg-amber-100 text-amber-700';
+    case 'evaluated':
+      return 'bg-green-100 text-green-700';
+    case 'revoked':
+      return 'bg-gray-200 text-gray-600';
+    default:
+      return 'bg-indigo-100 text-indigo-700';
+  }
+};
+
+const formatDue = (a: Assignment) => {
+  if (!a.due_at) return 'No deadline';
+  const due = new Date(a.due_at);
+  const hours = Math.round((due.getTime() - Date.now()) / 3600000);
+  const open = a.status === 'assigned' || a.status === 'in_progress';
+  if (!open) return due.toLocaleString();
+  return hours >= 0 ? `${due.toLocaleString()} (${hours}h left)` : `${due.toLocaleString()} (${-hours}h overdue)`;
+};
+
+const AssignmentDeadlines: React.FC = () => {
+  const { user } = useAuth();
+  const [assignments, setAssignments] = useState<Assignment[]>([]);
+  const [pool, setPool] = useState<Assignment[]>([]);
+  const [status, setStatus] = useState('');
+  const [courseId, setCourseId] = useState('');
+  const [overdueOnly, setOverdueOnly] = useState(false);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [report, setReport] = useState<SweepReport | null>(null);
+
+  const load = async () => {
+    setLoading(true);
+    setError(null);
+    try {
+      const [rows, waiting] = await Promise.all([
+        fetchAssignments({ status, course_id: courseId, overdue: overdueOnly }),
+        fetchAssignmentPool(),
+      ]);
+      setAssignments(rows || []);
+      setPool(waiting || []);
+    } catch (err: any) {
+      setError(err.message || 'Failed to load assignments');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    load();
+  }, [status, overdueOnly]);
+
+  const handleSweep = async () => {
+    try {
+      setReport(await runAssignmentSweep());
+      await load();
+    } catch (err: any) {
+      alert(err.message);
+    }
+  };
+
+  const handleExtend = async (a: Assignment) => {
+    const days = prompt('Extend the deadline by how many days?', '3');
+    if (!days) return;
+    const base = a.due_at && new Date(a.due_at).getTime() > Date.now() ? new Date(a.due_at) : new Date();
+    base.setDate(base.getDate() + (parseInt(days) || 0));
+    try {
+      await extendAssignment(a.id, base.toISOString(), user?.user_id ?? '');
+      await load();
+    } catch (err: any) {
+      alert(err.message);
+    }
+  };
+
+  const handleRevoke = async (a: Assignment) => {
+    const reason = prompt(`Revoke script ${a.script_id} from ${a.evaluator_id}? Reason:`);
+    if (!reason) return;
+    try {
+      const res = await revokeAssignment(a.id, reason, user?.user_id ?? '');
+      alert(res.reassigned ? `Reassigned to ${res.reassigned.to_evaluator}` : 'Revoked; the script is waiting in the pool.');
+      await load();
+    } catch (err: any) {
+      alert(err.message);
+    }
+  };
+
+  return (
+    <div className="min-h-screen bg-gray-50 p-6">
+      <div className="mx-auto max-w-6xl">
+        <header className="mb-8 flex items-end justify-between">
+          <div>
+            <h1 className="text-3xl font-bold text-gray-900">Assignment Deadlines</h1>
+            <p className="mt-2 text-gray-600">
+              Track due dates, extend or revoke assignments, and reassign overdue scripts.
+            </p>
+          </div>
+          <button
+            onClick={handleSweep}
+            className="rounded-md bg-indigo-600 px-4 py-2 text-sm font-medium text-white shadow-sm hover:bg-indigo-700"
+          >
+            Run Sweep Now
+          </button>
+        </header>
+
+        {report && (
+          <div className="mb-6 rounded-md border border-indigo-200 bg-indigo-50 p-4 text-sm text-indigo-800">
+            Sweep: {report.reminders} reminders, {report.revoked?.length ?? 0} revoked,{' '}
+            {report.reassigned?.length ?? 0} reassigned, {report.waiting_pool?.length ?? 0} waiting for an evaluator.
+          </div>
+        )}
+
+        {error && (
+          <div className="mb-6 rounded-md bg-red-50 p-4 text-red-700 border border-red-200">
+            {error}
+          </div>
+        )}
+
+        <Card className="mb-6 p-4">
+          <div className="flex flex-wrap items-center gap-4">
+            <select
+              value={status}
+              onChange={(e) => setStatus(e.target.value)}
+              className="rounded-md border border-gray-300 px-3 py-2 text-sm"
+            >
+              {STATUSES.map((s) => (
+                <option key={s} value={s}>
+                  {s === '' ? 'All statuses' : s}
+                </option>
+              ))}
+            </select>
+            <input
+              type="text"
+              placeholder="Course code"
+              value={courseId}
+              onChange={(e) => setCourseId(e.target.value)}
+              onBlur={load}
+              className="rounded-md border border-gray-300 px-3 py-2 text-sm"
+            />
+            <label className="flex items-center gap-2 text-sm text-gray-700">
+              <input type="checkbox" checked={overdueOnly} onChange={(e) => setOverdueOnly(e.target.checked)} />
+              Overdue only
+            </label>
+          </div>
+        </Card>
+
+        {pool.length > 0 && (
+          <Card className="mb-6 border-l-4 border-l-amber-500 p-4">
+            <h2 className="mb-2 text-lg font-semibold text-gray-800">Waiting for reassignment ({pool.length})</h2>
+            <p className="mb-2 text-sm text-gray-600">
+              Revoked scripts with no eligible evaluator yet. Approve more evaluator requests for these courses.
+            </p>
+            <ul className="space-y-1 text-sm text-gray-700">
+              {pool.map((a) => (
+                <li key={a.id}>
+                  {a.script_id} — {a.course_id} (round {a.valuation_round}), revoked from {a.evaluator_id}: {a.revoke_reason}
+                </li>
+              ))}
+            </ul>
+          </Card>
+        )}
+
+        {loading ? (
+          <div className="flex items-center justify-center py-12">
+            <div className="h-8 w-8 animate-spin rounded-full border-4 border-indigo-500 border-t-transparent"></div>
+          </div>
+        ) : assignments.length === 0 ? (
+          <div className="rounded-lg border border-dashed border-gray-300 bg-white p-12 text-center">
+            <p className="text-lg text-gray-500">No assignments found.</p>
+          </div>
+        ) : (
+          <div className="overflow-x-auto rounded-lg border border-gray-200 bg-white">
+            <table className="min-w-full divide-y divide-gray-200 text-sm">
+              <thead className="bg-gray-50 text-left text-xs font-semibold uppercase text-gray-500">
+                <tr>
+                  <th className="px-4 py-3">Script</th>
+                  <th className="px-4 py-3">Evaluator</th>
+                  <th className="px-4 py-3">Course</th>
+                  <th className="px-4 py-3">Status</th>
+                  <th className="px-4 py-3">Due</th>
+                  <th className="px-4 py-3"></th>
+                </tr>
+              </thead>
+              <tbody className="divide-y divide-gray-100">
+                {assignments.map((a) => {
+                  const open = a.status === 'assigned' || a.status === 'in_progress';
+                  return (
+                    <tr key={a.id}>
+                      <td className="px-4 py-3 font-mono text-xs">
+                        {a.script_id}
+                        {a.reassigned_from && <span className="ml-2 text-gray-400">(reassigned)</span>}
+                      </td>
+                      <td className="px-4 py-3">{a.evaluator_id}</td>
+                      <td className="px-4 py-3">
+                        {a.course_id} / {a.semester}
+                      </td>
+                      <td className="px-4 py-3">
+                        <span className={`rounded-full px-2 py-1 text-xs font-semibold ${statusBadge(a)}`}>
+                          {a.overdue ? 'overdue' : a.status}
+                        </span>
+                        {a.revoke_reason && <div className="mt-1 text-xs text-gray-500">{a.revoke_reason}</div>}
+                      </td>
+                      <td className="px-4 py-3">{formatDue(a)}</td>
+                      <td className="px-4 py-3 text-right">
+                        {open && (
+                          <div className="flex justify-end gap-2">
+                            <button
+                              onClick={() => handleExtend(a)}
+                              className="rounded-md bg-indigo-50 px-3 py-1 text-xs font-medium text-indigo-700 hover:bg-indigo-100"
+                            >
+                              Extend
+                            </button>
+                            <button
+                              onClick={() => handleRevoke(a)}
+                              className="rounded-md bg-red-50 px-3 py-1 text-xs font-medium text-red-600 hover:bg-red-100"
+                            >
+                              Revoke
+                            </button>
+                          </div>
+                        )}
+                      </td>
+                    </tr>
+                  );
+                })}
+              </tbody>
+            </table>
+          </div>
+        )}
+      </div>
+    </div>
+  );
+};
+
+export default AssignmentDeadlines;
@@ -25,9 +25,12 @@ const PendingRequests: React.FC = () => {
     loadRequests();
   }, []);
 
-  const handleApprove = async (id: number, assignNum: number) => {
+  const handleApprove = async (id: number, assignNum: number, dueDays: number) => {
     try {
-      await approveRequest(id, { assign_num: assignNum });
+      const res = await approveRequest(id, { assign_num: assignNum, due_days: dueDays });
+      if (res.report?.shortfall > 0) {
+        alert(`Approved with a shortfall: ${res.report.reason}`);
+      }
       // Remove from list
       setRequests((prev) => prev.filter((r) => r.id !== id));
     } catch (err: any) {
@@ -153,7 +153,7 @@ const EvaluateScript: React.FC = () => {
     <div className="h-[calc(100vh-100px)] flex flex-col">
       <div className="flex justify-between items-center mb-4 px-4">
         <h1 className="text-xl font-bold text-gray-800">
-          Evaluating: {metadata.CourseID} ({metadata.USN})
+          Evaluating: {metadata.CourseID} ({metadata.dummy_number || metadata.USN})
         </h1>
         <div className="space-x-2">
           <button
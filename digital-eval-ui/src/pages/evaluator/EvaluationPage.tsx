@@ -86,8 +86,10 @@ const EvaluationPage: React.FC = () => {
 
     try {
       // 1. Upload Annotated Script (if provided)
+      let annotatedCid: string | undefined;
       if (annotatedFile) {
-        await uploadEvaluatedScript(annotatedFile);
+        const uploaded = await uploadEvaluatedScript(annotatedFile, scriptId, user.user_id);
+        annotatedCid = uploaded.cid;
       }
 
       // 2. Submit Marks
@@ -106,6 +108,7 @@ const EvaluationPage: React.FC = () => {
         semester: metadata.semester || metadata.Semester || "1",
         academic_year: "2025-2026",
         course_credits: courseCredits,
+        annotated_cid: annotatedCid,
         additional_metadata: {},
       });
 
@@ -141,7 +144,7 @@ const EvaluationPage: React.FC = () => {
         <div className="bg-white px-6 py-3 border-b border-slate-200 flex justify-between items-center shadow-sm z-10">
           <h2 className="font-semibold text-slate-700 flex items-center gap-2">
             <span className="bg-slate-100 px-2 py-1 rounded text-xs font-mono text-slate-500">SCRIPT</span>
-            {scriptId}
+            {metadata.dummy_number || scriptId}
           </h2>
           <span className="text-xs text-slate-400">PDF Viewer</span>
         </div>
@@ -154,7 +157,7 @@ const EvaluationPage: React.FC = () => {
             />
           ) : (
             <div className="flex items-center justify-center h-full text-slate-400">
-              PDF not available
+              {metadata.pdf_error || "PDF not available"}
             </div>
           )}
         </div>
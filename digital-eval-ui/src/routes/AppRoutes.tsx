@@ -17,6 +17,7 @@ import AdminDashboard from "@/pages/dashboard/AdminDashboard";
 import PendingRequests from "../pages/authority/PendingRequests";
 import ApproveRequest from "../pages/authority/ApproveRequest";
 import ReleaseResults from "../pages/authority/ReleaseResults";
+import AssignmentDeadlines from "../pages/authority/AssignmentDeadlines";
 
 import UploadScripts from "../pages/examiner/UploadScripts";
 import UploadHistory from "../pages/examiner/UploadHistory";
@@ -67,6 +68,7 @@ const AppRoutes = () => {
                     <Route path="requests" element={<PendingRequests />} />
                     <Route path="approve" element={<ApproveRequest />} />
                     <Route path="release" element={<ReleaseResults />} />
+                    <Route path="assignments" element={<AssignmentDeadlines />} />
                 </Route>
 
                 {/* EXAMINER */}
@@ -7,10 +7,60 @@ export interface RequestRow {
 	description: string;
 	status: string;
 	created_at: string;
+	eligibility?: EligibilityDecision; // pending requests only
+}
+
+export interface EligibilityEntry {
+	id: number;
+	evaluator_id: string;
+	course_id?: string;
+	department?: string;
+	experience_level: 'junior' | 'senior' | 'chief';
+	experience_years: number;
+	valid_from: string;
+	valid_until?: string;
+	note?: string;
+	created_by?: string;
+}
+
+export interface EligibilityDecision {
+	evaluator_id: string;
+	course_id: string;
+	eligible: boolean;
+	reason: string;
+	entry?: EligibilityEntry;
 }
 
 export interface ApprovePayload {
 	assign_num: number;
+	due_days?: number;
+}
+
+export interface AssignmentReport {
+	evaluator_id: string;
+	course_id: string;
+	semester: string;
+	requested: number;
+	assigned: string[];
+	shortfall: number;
+	reason?: string;
+	open_before: number;
+	pool: {
+		uploaded: number;
+		evaluated: number;
+		fully_assigned: number;
+		held_by_evaluator: number;
+		conflicted: number;
+		available: number;
+	};
+	excluded?: AssignmentExclusion[];
+}
+
+export interface AssignmentExclusion {
+	evaluator_id: string;
+	script_id: string;
+	rule_id: number;
+	reason: string;
 }
 
 export interface ReleasePayload {
@@ -22,3 +72,120 @@ export interface ReleasePayload {
 export interface ReleaseResponse {
 	block_hash: string;
 }
+
+export interface Assignment {
+	id: number;
+	script_id: string;
+	evaluator_id: string;
+	course_id: string;
+	semester: string;
+	academic_year: string;
+	valuation_round: number;
+	status: string;
+	assigned_at: string;
+	due_at?: string;
+	overdue: boolean;
+	revoked_at?: string;
+	revoke_reason?: string;
+	reassigned_from?: number;
+}
+
+export interface Reassignment {
+	script_id: string;
+	valuation_round: number;
+	from_evaluator: string;
+	to_evaluator: string;
+	assignment_id: number;
+}
+
+export interface SweepReport {
+	reminders: number;
+	revoked: number[] | null;
+	reassigned: Reassignment[] | null;
+	waiting_pool: string[] | null;
+}
+
+export interface EvaluatorWorkload {
+	evaluator_id: string;
+	courses: string[];
+	assigned: number;
+	in_progress: number;
+	evaluated: number;
+	revoked: number;
+	overdue: number;
+	avg_hours_per_script?: number;
+	evaluated_in_window: number;
+	per_day: number;
+	last_evaluated_at?: string;
+	projected_clear_at?: string;
+}
+
+export interface CourseWorkload {
+	course_id: string;
+	semester: string;
+	uploaded: number;
+	finalized: number;
+	third_pending: number;
+	completion_percent: number;
+	rounds: number;
+	rounds_total: number;
+	rounds_open: number;
+	rounds_evaluated: number;
+	unassigned: number;
+	evaluators: number;
+	avg_hours_per_script?: number;
+	evaluated_in_window: number;
+	per_day: number;
+	projected_completion_at?: string;
+	projection?: string;
+}
+
+export interface WorkloadReport {
+	generated_at: string;
+	semester?: string;
+	academic_year?: string;
+	course_id?: string;
+	window_days: number;
+	evaluators: EvaluatorWorkload[];
+	courses: CourseWorkload[];
+}
+
+export interface RemunerationLine {
+	course_id: string;
+	course_type: string;
+	valuation_type: 'single' | 'double' | 'third' | 'revaluation';
+	scripts: number;
+	rated: boolean;
+	rate_per_script: number;
+	amount: number;
+}
+
+export interface RemunerationStatement {
+	id: number;
+	evaluator_id: string;
+	semester: string;
+	academic_year: string;
+	scripts: number;
+	amount: number;
+	lines: RemunerationLine[];
+	unrated: number;
+	status: 'draft' | 'approved' | 'paid';
+	computed_at: string;
+	approved_by?: string;
+	approved_at?: string;
+	payout_reference?: string;
+	paid_by?: string;
+	paid_at?: string;
+}
+
+export interface RemunerationReport {
+	semester: string;
+	academic_year: string;
+	evaluators: number;
+	scripts: number;
+	amount: number;
+	drafts: number;
+	frozen?: string[];
+	diverged?: string[];
+	missing_rates?: { course_type: string; valuation_type: string; scripts: number }[];
+}
@@ -45,9 +45,35 @@ export interface EvaluationSubmit {
     semester: string;
     academic_year: string;
     course_credits: number;
+    annotated_cid?: string;
+    remarks?: QuestionRemark[];
+    annotations?: Annotation[];
     additional_metadata: Record<string, any>;
 }
 
+export interface QuestionRemark {
+    question: number; // 1-based over the exam pattern's marked questions
+    label?: string;
+    remark: string;
+}
+
+export interface Annotation {
+    page: number; // 1-based
+    x: number; // fraction of the page width from the left edge
+    y: number; // fraction of the page height from the top edge
+    kind: "tick" | "cross" | "comment";
+    text?: string;
+    question?: number;
+}
+
+export interface AnnotatedUploadResponse {
+    cid: string;
+    sha256: string;
+    script_id: string;
+    assignment_id: number;
+    status: string;
+}
+
 export interface SubmitResponse {
     block_hash: string;
 }
@@ -0,0 +1,36 @@
+-- V007__grading_schemes.sql
+-- Grading policies per regulation / academic year
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS grading_schemes (
+    id serial PRIMARY KEY,
+    regulation text NOT NULL,
+    academic_year text NOT NULL DEFAULT '',      -- '' = every academic year under the regulation
+    pass_percentage numeric(5,2) NOT NULL DEFAULT 36,
+    absent_code text NOT NULL DEFAULT 'AB',
+    withheld_code text NOT NULL DEFAULT 'WH',
+    rounding text NOT NULL DEFAULT 'half_up',    -- half_up / floor / ceil
+    decimals integer NOT NULL DEFAULT 2,
+    grades jsonb NOT NULL,                       -- [{"letter":"O","min_percentage":90,"grade_point":10}, ...]
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT uq_grading_scheme UNIQUE (regulation, academic_year),
+    CONSTRAINT chk_grading_rounding CHECK (rounding IN ('half_up', 'floor', 'ceil')),
+    CONSTRAINT chk_grading_pass CHECK (pass_percentage >= 0 AND pass_percentage <= 100)
+);
+
+-- Seed the scheme that was previously hard-coded in the Go node.
+INSERT INTO grading_schemes (regulation, academic_year, pass_percentage, absent_code, withheld_code, rounding, decimals, grades)
+VALUES ('DEFAULT', '', 36, 'AB', 'WH', 'half_up', 2, '[
+    {"letter": "O",  "min_percentage": 90, "grade_point": 10},
+    {"letter": "A+", "min_percentage": 80, "grade_point": 9},
+    {"letter": "A",  "min_percentage": 70, "grade_point": 8},
+    {"letter": "B+", "min_percentage": 60, "grade_point": 7},
+    {"letter": "B",  "min_percentage": 50, "grade_point": 6},
+    {"letter": "C",  "min_percentage": 40, "grade_point": 5},
+    {"letter": "F",  "min_percentage": 0,  "grade_point": 0}
+]')
+ON CONFLICT (regulation, academic_year) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,25 @@
+-- V008__courses.sql
+-- Authoritative course catalog (names, credits, regulation, exam pattern)
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS courses (
+    id serial PRIMARY KEY,
+    course_code text NOT NULL,
+    course_name text NOT NULL,
+    credits integer NOT NULL DEFAULT 0,
+    semester text NOT NULL,
+    department text NOT NULL DEFAULT '',
+    regulation text NOT NULL DEFAULT '',
+    exam_pattern text NOT NULL DEFAULT '',       -- exam pattern code used for marks validation
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT uq_course_code UNIQUE (course_code),
+    CONSTRAINT chk_course_code_not_empty CHECK (length(trim(course_code)) > 0),
+    CONSTRAINT chk_course_credits CHECK (credits >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_courses_semester ON courses(semester);
+CREATE INDEX IF NOT EXISTS idx_courses_department ON courses(department);
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+-- Links a student login (users.user_id) to the USN whose results it may see.
+CREATE TABLE IF NOT EXISTS student_accounts (
+    user_id text PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    usn text UNIQUE NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT chk_student_accounts_usn_not_empty CHECK (length(trim(usn)) > 0)
+);
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Evaluator leniency / anomaly reports produced by the detection job.
+CREATE TABLE IF NOT EXISTS anomaly_reports (
+    id serial PRIMARY KEY,
+    semester text NOT NULL,
+    academic_year text NOT NULL,
+    generated_by text NOT NULL,
+    generated_at timestamptz NOT NULL DEFAULT now(),
+    flag_count integer NOT NULL DEFAULT 0,
+    report jsonb NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_anomaly_reports_sem_year
+    ON anomaly_reports(semester, academic_year, generated_at DESC);
+
+COMMIT;
@@ -0,0 +1,61 @@
+-- V011__double_valuation.sql
+-- Double valuation: a script may be assigned to several evaluators (one per
+-- valuation round); every valuation is kept, and a policy decides how many
+-- rounds a course needs and how the final marks are derived.
+
+BEGIN;
+
+ALTER TABLE assigned_scripts DROP CONSTRAINT IF EXISTS uq_assigned_script;
+
+ALTER TABLE assigned_scripts
+    ADD COLUMN IF NOT EXISTS valuation_round integer NOT NULL DEFAULT 1;
+
+-- an evaluator values a script at most once, and each round has one live assignment
+ALTER TABLE assigned_scripts
+    ADD CONSTRAINT uq_assigned_script_evaluator UNIQUE (script_id, evaluator_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_assigned_script_round
+    ON assigned_scripts(script_id, valuation_round) WHERE status <> 'revoked';
+
+CREATE INDEX IF NOT EXISTS idx_assigned_scripts_script_id
+    ON assigned_scripts(script_id);
+
+-- Valuation policy per course, per semester or global (both empty).
+CREATE TABLE IF NOT EXISTS valuation_policies (
+    id serial PRIMARY KEY,
+    course_id text NOT NULL DEFAULT '',
+    semester text NOT NULL DEFAULT '',
+    mode text NOT NULL DEFAULT 'single',             -- single / double
+    aggregation text NOT NULL DEFAULT 'average',     -- average / max
+    threshold_percent numeric(5,2) NOT NULL DEFAULT 15, -- discrepancy (% of total marks) that triggers a third valuation
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT uq_valuation_policy_scope UNIQUE (course_id, semester),
+    CONSTRAINT chk_valuation_mode CHECK (mode IN ('single', 'double')),
+    CONSTRAINT chk_valuation_aggregation CHECK (aggregation IN ('average', 'max')),
+    CONSTRAINT chk_valuation_threshold CHECK (threshold_percent >= 0 AND threshold_percent <= 100)
+);
+
+-- One row per individual valuation of a script (each also recorded on-chain).
+CREATE TABLE IF NOT EXISTS valuations (
+    id serial PRIMARY KEY,
+    script_id text NOT NULL,
+    assignment_id integer REFERENCES assigned_scripts(id) ON DELETE SET NULL,
+    evaluator_id text NOT NULL,
+    valuation_round integer NOT NULL,
+    course_id text NOT NULL,
+    semester text NOT NULL,
+    academic_year text NOT NULL,
+    marks jsonb NOT NULL,
+    score integer NOT NULL,
+    total_marks integer NOT NULL,
+    block_hash text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT uq_valuation_round UNIQUE (script_id, valuation_round),
+    CONSTRAINT uq_valuation_evaluator UNIQUE (script_id, evaluator_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_valuations_course_sem
+    ON valuations(course_id, semester, academic_year);
+
+COMMIT;
@@ -0,0 +1,58 @@
+-- V012__revaluation.sql
+-- Student revaluation / re-totaling requests and the revision history of
+-- evaluations they change.
+
+BEGIN;
+
+ALTER TABLE evaluations
+    ADD COLUMN IF NOT EXISTS revision integer NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS revaluation_requests (
+    id serial PRIMARY KEY,
+    evaluation_id integer NOT NULL REFERENCES evaluations(id) ON DELETE CASCADE,
+    student_usn text NOT NULL,
+    course_id text NOT NULL,
+    semester text NOT NULL,
+    academic_year text NOT NULL,
+    kind text NOT NULL,                          -- revaluation / retotal
+    reason text NOT NULL DEFAULT '',
+    status text NOT NULL DEFAULT 'pending',      -- pending / approved / rejected / completed
+    evaluator_id text NOT NULL DEFAULT '',       -- evaluator doing the revaluation (empty for re-totaling)
+    decided_by text NOT NULL DEFAULT '',
+    decided_at timestamptz,
+    decision_note text NOT NULL DEFAULT '',
+    completed_by text NOT NULL DEFAULT '',
+    completed_at timestamptz,
+    previous_score integer,
+    revised_score integer,
+    block_hash text NOT NULL DEFAULT '',         -- chain transaction carrying the revised marks
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT chk_revaluation_kind CHECK (kind IN ('revaluation', 'retotal')),
+    CONSTRAINT chk_revaluation_status CHECK (status IN ('pending', 'approved', 'rejected', 'completed'))
+);
+
+-- one open request per evaluation
+CREATE UNIQUE INDEX IF NOT EXISTS uq_revaluation_open
+    ON revaluation_requests(evaluation_id) WHERE status IN ('pending', 'approved');
+
+CREATE INDEX IF NOT EXISTS idx_revaluation_usn ON revaluation_requests(student_usn);
+CREATE INDEX IF NOT EXISTS idx_revaluation_status ON revaluation_requests(status);
+CREATE INDEX IF NOT EXISTS idx_revaluation_evaluator ON revaluation_requests(evaluator_id) WHERE evaluator_id <> '';
+
+-- Every superseded version of an evaluation row.
+CREATE TABLE IF NOT EXISTS evaluation_revisions (
+    id serial PRIMARY KEY,
+    evaluation_id integer NOT NULL REFERENCES evaluations(id) ON DELETE CASCADE,
+    revision integer NOT NULL,
+    evaluator_id text NOT NULL,
+    marks jsonb NOT NULL,
+    total_marks integer NOT NULL,
+    result text NOT NULL,
+    block_hash text NOT NULL,
+    superseded_by_request integer REFERENCES revaluation_requests(id) ON DELETE SET NULL,
+    superseded_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT uq_evaluation_revision UNIQUE (evaluation_id, revision)
+);
+
+COMMIT;
@@ -0,0 +1,32 @@
+-- V013__moderation.sql
+-- Authority moderation of submitted marks before release. Every change is a
+-- signed chain transaction referencing the evaluation block it moderates.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS moderations (
+    id serial PRIMARY KEY,
+    evaluation_id integer NOT NULL REFERENCES evaluations(id) ON DELETE CASCADE,
+    batch_id text NOT NULL DEFAULT '',           -- shared by the rows of one bulk rule
+    kind text NOT NULL,                          -- adjust / grace / note
+    moderator_id text NOT NULL,
+    reason text NOT NULL,
+    changes jsonb NOT NULL DEFAULT '{}'::jsonb,
+    previous_score integer NOT NULL,
+    moderated_score integer NOT NULL,
+    previous_result text NOT NULL,
+    moderated_result text NOT NULL,
+    evaluation_block_hash text NOT NULL,         -- evaluation block the change refers to
+    block_hash text NOT NULL,                    -- moderation transaction block
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT chk_moderation_kind CHECK (kind IN ('adjust', 'grace', 'note')),
+    CONSTRAINT chk_moderation_reason CHECK (length(trim(reason)) > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderations_evaluation ON moderations(evaluation_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_moderations_batch ON moderations(batch_id) WHERE batch_id <> '';
+
+ALTER TABLE evaluation_revisions
+    ADD COLUMN IF NOT EXISTS superseded_by_moderation integer REFERENCES moderations(id) ON DELETE SET NULL;
+
+COMMIT;
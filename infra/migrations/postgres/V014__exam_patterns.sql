@@ -0,0 +1,16 @@
+-- V014__exam_patterns.sql
+-- Exam pattern definitions (sections, questions, sub-questions, choice rules
+-- and maximum marks) referenced by courses.exam_pattern. They drive marks
+-- validation and totals; courses without a pattern use the built-in DEFAULT.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS exam_patterns (
+    code text PRIMARY KEY,
+    name text NOT NULL DEFAULT '',
+    definition jsonb NOT NULL,                   -- sections / questions / sub_questions
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+COMMIT;
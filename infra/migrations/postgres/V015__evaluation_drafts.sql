@@ -0,0 +1,19 @@
+-- V015__evaluation_drafts.sql
+-- Autosaved partial marks per assignment. Saving a draft moves the assignment
+-- to in_progress; submitting the evaluation removes the draft.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS evaluation_drafts (
+    assignment_id integer PRIMARY KEY REFERENCES assigned_scripts(id) ON DELETE CASCADE,
+    script_id text NOT NULL,
+    evaluator_id text NOT NULL,
+    payload jsonb NOT NULL,                      -- submit payload as last saved
+    version integer NOT NULL DEFAULT 1,          -- bumped on every save, guards stale tabs
+    created_at timestamptz NOT NULL DEFAULT now(),
+    saved_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_evaluation_drafts_evaluator ON evaluation_drafts(evaluator_id);
+
+COMMIT;
@@ -0,0 +1,33 @@
+-- V016__assignment_deadlines.sql
+-- Assignment deadlines: a due date per assignment, reminder / revocation /
+-- reassignment events, and the link from a reassignment to the revoked
+-- assignment it replaces. Assignments without due_at never expire.
+
+BEGIN;
+
+ALTER TABLE assigned_scripts
+    ADD COLUMN IF NOT EXISTS due_at timestamptz,
+    ADD COLUMN IF NOT EXISTS revoked_at timestamptz,
+    ADD COLUMN IF NOT EXISTS revoke_reason text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS reassigned_from integer REFERENCES assigned_scripts(id);
+
+CREATE INDEX IF NOT EXISTS idx_assigned_scripts_due
+    ON assigned_scripts(due_at) WHERE status IN ('assigned', 'in_progress');
+
+CREATE TABLE IF NOT EXISTS assignment_events (
+    id serial PRIMARY KEY,
+    assignment_id integer NOT NULL REFERENCES assigned_scripts(id) ON DELETE CASCADE,
+    evaluator_id text NOT NULL,
+    kind text NOT NULL,                          -- reminder / revoked / reassigned / extended
+    detail text NOT NULL DEFAULT '',             -- e.g. reminder offset "24h", new evaluator
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- each reminder offset fires once per assignment
+CREATE UNIQUE INDEX IF NOT EXISTS uq_assignment_reminder
+    ON assignment_events(assignment_id, detail) WHERE kind = 'reminder';
+
+CREATE INDEX IF NOT EXISTS idx_assignment_events_assignment
+    ON assignment_events(assignment_id);
+
+COMMIT;
@@ -0,0 +1,49 @@
+-- V017__conflict_of_interest.sql
+-- Evaluator profiles (affiliation, college code, department), conflict of
+-- interest rules applied when scripts are assigned, and the record of which
+-- scripts were withheld from which evaluator and why.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS evaluator_profiles (
+    evaluator_id text PRIMARY KEY,
+    name text NOT NULL DEFAULT '',
+    affiliation text NOT NULL DEFAULT '',        -- college / institution name
+    college_code text NOT NULL DEFAULT '',       -- USN prefix of the college, e.g. 1BI
+    department text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS coi_rules (
+    id serial PRIMARY KEY,
+    kind text NOT NULL,                          -- college_prefix / block_usn
+    evaluator_id text NOT NULL DEFAULT '',       -- '' = applies to every evaluator
+    value text NOT NULL DEFAULT '',              -- block_usn: USN, or prefix ending in *
+    reason text NOT NULL DEFAULT '',
+    enabled boolean NOT NULL DEFAULT true,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_coi_rules_evaluator ON coi_rules(evaluator_id);
+
+-- evaluators never mark scripts from their own college unless disabled
+INSERT INTO coi_rules (kind, reason)
+SELECT 'college_prefix', 'script is from the evaluator''s own college'
+WHERE NOT EXISTS (SELECT 1 FROM coi_rules WHERE kind = 'college_prefix' AND evaluator_id = '');
+
+CREATE TABLE IF NOT EXISTS assignment_exclusions (
+    id serial PRIMARY KEY,
+    evaluator_id text NOT NULL,
+    script_id text NOT NULL,
+    course_id text NOT NULL,
+    semester text NOT NULL,
+    rule_id integer REFERENCES coi_rules(id) ON DELETE SET NULL,
+    reason text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT uq_assignment_exclusion UNIQUE (evaluator_id, script_id)
+);
+
+COMMIT;
@@ -0,0 +1,22 @@
+-- V018__blind_evaluation.sql
+-- Blind evaluation: every script shown to evaluators under a random dummy
+-- number. The mapping stays with the authority and is revealed when the
+-- script's results are released.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS script_masks (
+    script_id text PRIMARY KEY,
+    dummy_number text NOT NULL,
+    course_id text NOT NULL DEFAULT '',
+    semester text NOT NULL DEFAULT '',
+    masked_cid text NOT NULL DEFAULT '',         -- IPFS copy without student identifiers
+    created_at timestamptz NOT NULL DEFAULT now(),
+    revealed_at timestamptz,                     -- set when the results are released
+    revealed_by text NOT NULL DEFAULT '',
+    CONSTRAINT uq_script_mask_dummy UNIQUE (dummy_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_script_masks_course ON script_masks(course_id, semester);
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- V019__evaluation_block_hash.sql
+-- Evaluation rows are written from the node's outbox and may be retried;
+-- the insert skips a block whose row already exists, looked up here.
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_eval_block_hash ON evaluations(block_hash);
+
+COMMIT;
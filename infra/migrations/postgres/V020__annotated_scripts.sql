@@ -0,0 +1,25 @@
+-- V020__annotated_scripts.sql
+-- The annotated copy of a script an evaluator uploads (IPFS CID and SHA-256
+-- of the file), one per assignment, and the copy each evaluation was
+-- submitted with.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS annotated_scripts (
+    id serial PRIMARY KEY,
+    assignment_id integer NOT NULL UNIQUE REFERENCES assigned_scripts(id) ON DELETE CASCADE,
+    script_id text NOT NULL,
+    evaluator_id text NOT NULL,
+    cid text NOT NULL,
+    sha256 text NOT NULL,
+    file_name text NOT NULL DEFAULT '',
+    uploaded_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_annotated_scripts_script ON annotated_scripts(script_id);
+
+ALTER TABLE evaluations
+    ADD COLUMN IF NOT EXISTS annotated_cid text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS annotated_sha256 text NOT NULL DEFAULT '';
+
+COMMIT;
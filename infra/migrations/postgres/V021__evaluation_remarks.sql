@@ -0,0 +1,11 @@
+-- V021__evaluation_remarks.sql
+-- Per-question remarks and page annotations (tick / cross / comment at a
+-- point on a page) an evaluator gives with the marks.
+
+BEGIN;
+
+ALTER TABLE evaluations
+    ADD COLUMN IF NOT EXISTS remarks jsonb NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS annotations jsonb NOT NULL DEFAULT '[]';
+
+COMMIT;
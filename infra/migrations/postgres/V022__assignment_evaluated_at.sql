@@ -0,0 +1,22 @@
+-- V022__assignment_evaluated_at.sql
+-- When each assignment was evaluated, for the workload dashboard's time per
+-- script and throughput. Existing evaluated assignments take the time of
+-- their valuation or evaluation.
+
+BEGIN;
+
+ALTER TABLE assigned_scripts
+    ADD COLUMN IF NOT EXISTS evaluated_at timestamptz;
+
+UPDATE assigned_scripts a SET evaluated_at = v.created_at
+FROM valuations v
+WHERE v.assignment_id = a.id AND a.status = 'evaluated' AND a.evaluated_at IS NULL;
+
+UPDATE assigned_scripts a SET evaluated_at = e.created_at
+FROM evaluations e
+WHERE e.script_id = a.script_id AND e.evaluator_id = a.evaluator_id
+  AND a.status = 'evaluated' AND a.evaluated_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_assigned_scripts_course_sem ON assigned_scripts(course_id, semester);
+
+COMMIT;
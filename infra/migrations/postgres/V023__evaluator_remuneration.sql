@@ -0,0 +1,49 @@
+-- V023__evaluator_remuneration.sql
+-- Evaluator remuneration: per-script rates by course type and valuation
+-- type, and the per-evaluator statements computed for each cycle.
+
+BEGIN;
+
+ALTER TABLE courses
+    ADD COLUMN IF NOT EXISTS course_type text NOT NULL DEFAULT '';  -- theory / lab / project ...; '' = unclassified
+
+-- Rate paid per script. Empty scope fields match anything; the most specific
+-- rate wins (course type, then valuation type, then academic year).
+CREATE TABLE IF NOT EXISTS remuneration_rates (
+    id serial PRIMARY KEY,
+    course_type text NOT NULL DEFAULT '',
+    valuation_type text NOT NULL DEFAULT '',     -- single / double / third / revaluation
+    academic_year text NOT NULL DEFAULT '',
+    rate_per_script numeric(10,2) NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT uq_remuneration_rate_scope UNIQUE (course_type, valuation_type, academic_year),
+    CONSTRAINT chk_remuneration_valuation_type CHECK (valuation_type IN ('', 'single', 'double', 'third', 'revaluation')),
+    CONSTRAINT chk_remuneration_rate CHECK (rate_per_script >= 0)
+);
+
+-- One statement per evaluator and cycle. Drafts are replaced on every
+-- computation; approved and paid statements are frozen.
+CREATE TABLE IF NOT EXISTS remuneration_statements (
+    id serial PRIMARY KEY,
+    evaluator_id text NOT NULL,
+    semester text NOT NULL,
+    academic_year text NOT NULL,
+    scripts integer NOT NULL DEFAULT 0,
+    amount numeric(12,2) NOT NULL DEFAULT 0,
+    lines jsonb NOT NULL DEFAULT '[]',
+    unrated integer NOT NULL DEFAULT 0,          -- scripts with no matching rate
+    status text NOT NULL DEFAULT 'draft',        -- draft / approved / paid
+    computed_at timestamptz NOT NULL DEFAULT now(),
+    approved_by text NOT NULL DEFAULT '',
+    approved_at timestamptz,
+    payout_reference text NOT NULL DEFAULT '',
+    paid_by text NOT NULL DEFAULT '',
+    paid_at timestamptz,
+    CONSTRAINT uq_remuneration_statement UNIQUE (evaluator_id, semester, academic_year),
+    CONSTRAINT chk_remuneration_status CHECK (status IN ('draft', 'approved', 'paid'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_remuneration_statements_cycle ON remuneration_statements(semester, academic_year);
+
+COMMIT;
@@ -0,0 +1,29 @@
+-- V024__evaluator_eligibility.sql
+-- Registry of the courses or departments each evaluator is qualified to
+-- evaluate, with experience level and validity period. Evaluation requests
+-- are only accepted for courses an evaluator is registered for.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS evaluator_eligibility (
+    id serial PRIMARY KEY,
+    evaluator_id text NOT NULL,
+    course_id text NOT NULL DEFAULT '',          -- one course, or
+    department text NOT NULL DEFAULT '',         -- every course of a department
+    experience_level text NOT NULL DEFAULT 'junior', -- junior / senior / chief
+    experience_years integer NOT NULL DEFAULT 0,
+    valid_from date NOT NULL DEFAULT current_date,
+    valid_until date,                            -- NULL = no end date
+    note text NOT NULL DEFAULT '',
+    created_by text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT chk_eligibility_scope CHECK ((course_id = '') <> (department = '')),
+    CONSTRAINT chk_eligibility_level CHECK (experience_level IN ('junior', 'senior', 'chief')),
+    CONSTRAINT chk_eligibility_years CHECK (experience_years >= 0),
+    CONSTRAINT chk_eligibility_period CHECK (valid_until IS NULL OR valid_until >= valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_eligibility_evaluator ON evaluator_eligibility(evaluator_id);
+
+COMMIT;
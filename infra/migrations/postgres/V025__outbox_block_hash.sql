@@ -0,0 +1,10 @@
+-- V025__outbox_block_hash.sql
+-- Valuation and moderation rows are written from the node's outbox as well;
+-- a replayed item finds the rows of its block through these indexes.
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_valuations_block_hash ON valuations(block_hash);
+CREATE INDEX IF NOT EXISTS idx_moderations_block_hash ON moderations(block_hash);
+
+COMMIT;
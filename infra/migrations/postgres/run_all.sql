@@ -3,4 +3,23 @@
 \i 'G:/digital-eval-system/infra/migrations/postgres/V003__audit_logs.sql'
 \i 'G:/digital-eval-system/infra/migrations/postgres/V004__authority_evaluator.sql'
 \i 'G:/digital-eval-system/infra/migrations/postgres/V005__evaluations_table.sql'
-\i 'G:/digital-eval-system/infra/migrations/postgres/V006__results_release.sql'
\ No newline at end of file
+\i 'G:/digital-eval-system/infra/migrations/postgres/V006__results_release.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V007__grading_schemes.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V008__courses.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V009__student_accounts.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V010__anomaly_reports.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V011__double_valuation.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V012__revaluation.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V013__moderation.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V014__exam_patterns.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V015__evaluation_drafts.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V016__assignment_deadlines.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V017__conflict_of_interest.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V018__blind_evaluation.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V019__evaluation_block_hash.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V020__annotated_scripts.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V021__evaluation_remarks.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V022__assignment_evaluated_at.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V023__evaluator_remuneration.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V024__evaluator_eligibility.sql'
+\i 'G:/digital-eval-system/infra/migrations/postgres/V025__outbox_block_hash.sql'
@@ -15,19 +15,34 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"digital-eval-system/services/go-node/internal/admin"
+	"digital-eval-system/services/go-node/internal/analytics"
+	"digital-eval-system/services/go-node/internal/anomaly"
 	"digital-eval-system/services/go-node/internal/api"
 	"digital-eval-system/services/go-node/internal/auth"
 	"digital-eval-system/services/go-node/internal/authority"
+	"digital-eval-system/services/go-node/internal/blind"
 	"digital-eval-system/services/go-node/internal/chain"
+	"digital-eval-system/services/go-node/internal/conflict"
 	"digital-eval-system/services/go-node/internal/core"
+	"digital-eval-system/services/go-node/internal/course"
 	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/deadline"
+	"digital-eval-system/services/go-node/internal/eligibility"
 	"digital-eval-system/services/go-node/internal/evaluator"
 	"digital-eval-system/services/go-node/internal/examiner"
+	"digital-eval-system/services/go-node/internal/exampattern"
+	"digital-eval-system/services/go-node/internal/grading"
 	"digital-eval-system/services/go-node/internal/logger"
+	"digital-eval-system/services/go-node/internal/moderation"
+	"digital-eval-system/services/go-node/internal/outbox"
 	"digital-eval-system/services/go-node/internal/pybridge"
+	"digital-eval-system/services/go-node/internal/remuneration"
+	"digital-eval-system/services/go-node/internal/revaluation"
 	"digital-eval-system/services/go-node/internal/rootdir"
 	"digital-eval-system/services/go-node/internal/storage"
 	"digital-eval-system/services/go-node/internal/student"
+	"digital-eval-system/services/go-node/internal/tabulation"
+	"digital-eval-system/services/go-node/internal/valuation"
 	"digital-eval-system/services/go-node/ui"
 )
 
@@ -48,6 +63,7 @@ type Config struct {
 	Block struct {
 		SignerID      string `yaml:"signer_id"`
 		SignatureAlgo string `yaml:"signature_algo"`
+		PrivKeyPath   string `yaml:"priv_key_path"`
 	} `yaml:"block"`
 	PythonExtractor struct {
 		URL string `yaml:"url"`
@@ -71,6 +87,9 @@ type Config struct {
 		AccessTTLSeconds  int    `yaml:"access_ttl_seconds"`
 		RefreshTTLSeconds int    `yaml:"refresh_ttl_seconds"`
 	} `yaml:"auth"`
+	Assignments deadline.Policy `yaml:"assignments"`
+	Blind       blind.Policy    `yaml:"blind_evaluation"`
+	Outbox      outbox.Policy   `yaml:"outbox"`
 }
 
 func loadConfig(path string) (*Config, error) {
@@ -99,6 +118,7 @@ func resolveConfigPaths(cfg *Config) {
 	cfg.Server.TLS.CertPath = resolve(cfg.Server.TLS.CertPath)
 	cfg.Server.TLS.KeyPath = resolve(cfg.Server.TLS.KeyPath)
 	cfg.Storage.BoltDBPath = resolve(cfg.Storage.BoltDBPath)
+	cfg.Block.PrivKeyPath = resolve(cfg.Block.PrivKeyPath)
 	cfg.Auth.PrivKeyPath = resolve(cfg.Auth.PrivKeyPath)
 	cfg.Auth.PubKeyPath = resolve(cfg.Auth.PubKeyPath)
 }
@@ -197,37 +217,127 @@ func main() {
 	registry.Register("auth_service", authSvc)
 	logrus.Info("auth service registered")
 
+	// grading schemes (per regulation / academic year)
+	gradingSvc := grading.NewService(pgDB)
+	registry.Register("grading_service", gradingSvc)
+	logrus.Info("grading service registered")
+
+	// course catalog (names, credits, regulation)
+	courseSvc := course.NewService(pgDB)
+	registry.Register("course_service", courseSvc)
+	logrus.Info("course service registered")
+
+	// exam patterns (sections, questions, choice rules) referenced by courses
+	examPatternSvc := exampattern.NewService(pgDB)
+	registry.Register("exam_pattern_service", examPatternSvc)
+	logrus.Info("exam pattern service registered")
+
+	// evaluator profiles and conflict of interest rules applied to every assignment
+	conflictSvc := conflict.NewService(pgDB, store)
+	registry.Register("conflict_service", conflictSvc)
+	logrus.Info("conflict of interest service registered")
+
+	// which evaluators may evaluate which courses or departments, and until when
+	eligibilitySvc := eligibility.NewService(pgDB, courseSvc)
+	registry.Register("eligibility_service", eligibilitySvc)
+	logrus.Info("eligibility service registered")
+
+	// assignment deadlines: reminders, revocation of overdue scripts, reassignment
+	deadlineSvc := deadline.NewService(pgDB, cfg.Assignments, conflictSvc)
+	registry.Register("deadline_service", deadlineSvc)
+	logrus.Info("deadline service registered")
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go deadlineSvc.Run(jobsCtx)
+
+	// valuation policies (single / double valuation per course or semester)
+	valuationSvc := valuation.NewService(pgDB, deadlineSvc, conflictSvc)
+	registry.Register("valuation_service", valuationSvc)
+	logrus.Info("valuation service registered")
+
 	// -----------------------------------------
 	// Phase 5 – Authority Service
 	// -----------------------------------------
-	authoritySvc := authority.NewService(pgDB, store)
+	authoritySvc := authority.NewService(pgDB, store, courseSvc, valuationSvc, deadlineSvc, conflictSvc, eligibilitySvc)
 	registry.Register("authority_service", authoritySvc)
 	logrus.Info("authority service registered")
 
+	// blind evaluation: dummy numbers and masked script PDFs (the extractor masks)
+	if err := cfg.Blind.Validate(); err != nil {
+		logrus.Fatalf("invalid blind_evaluation config: %v", err)
+	}
+	blindSvc := blind.NewService(pgDB, pyClient, cfg.Blind)
+	registry.Register("blind_service", blindSvc)
+	logrus.Infof("blind evaluation service registered (enabled=%v)", cfg.Blind.Enabled)
+
+	// outbox: Postgres writes of appended blocks, retried until they succeed
+	if err := cfg.Outbox.Validate(); err != nil {
+		logrus.Fatalf("invalid outbox config: %v", err)
+	}
+	outboxSvc := outbox.NewService(pgDB, store, cfg.Outbox)
+	registry.Register("outbox_service", outboxSvc)
+	logrus.Info("outbox service registered")
+	go outboxSvc.Run(jobsCtx)
+
 	// -----------------------------------------
 	// Phase 5 – Evaluator Service
 	// -----------------------------------------
 
-	evSvc := evaluator.NewService(pgDB, store, pyValidatorClient, chain.NewChain(store))
+	// one submit pipeline behind every submission endpoint
+	submitSvc := evaluator.NewSubmitService(pgDB, store, pyValidatorClient, chain.NewChain(store), gradingSvc, courseSvc, valuationSvc, examPatternSvc, blindSvc, outboxSvc)
+	registry.Register("evaluator_submit_service", submitSvc)
+	logrus.Infof("evaluator submit service registered (stages: %v)", submitSvc.Pipeline().Stages())
+
+	evSvc := evaluator.NewService(pgDB, store, submitSvc, blindSvc, eligibilitySvc)
 	registry.Register("evaluator_service", evSvc)
 	logrus.Info("evaluator service registered")
 
-	submitSvc := evaluator.NewSubmitService(pgDB, store, pyValidatorClient, chain.NewChain(store))
-	registry.Register("evaluator_submit_service", submitSvc)
-	logrus.Info("evaluator submit service registered")
-
 	// Evaluator Upload Service (New)
-	evalUploadSvc := evaluator.NewUploadService(pyClient) // uses pyClient (extractor) for IPFS upload
+	evalUploadSvc := evaluator.NewUploadService(pyClient, pgDB, store, blindSvc) // uses pyClient (extractor) for IPFS upload
 	registry.Register("evaluator_upload_service", evalUploadSvc)
 	logrus.Info("evaluator upload service registered")
 
 	// Release service
-	releaseSvc := authority.NewReleaseService(pgDB, chain.NewChain(store))
+	releaseSvc := authority.NewReleaseService(pgDB, chain.NewChain(store), blindSvc, outboxSvc)
 	registry.Register("authority_release_service", releaseSvc)
 	logrus.Info("authority release service registered")
 
+	// Tabulation register exports
+	tabulationSvc := tabulation.NewService(pgDB, gradingSvc, courseSvc)
+	registry.Register("tabulation_service", tabulationSvc)
+	logrus.Info("tabulation service registered")
+
+	// Evaluator remuneration: rate tables and per-cycle statements
+	remunerationSvc := remuneration.NewService(pgDB, courseSvc)
+	registry.Register("remuneration_service", remunerationSvc)
+	logrus.Info("remuneration service registered")
+
+	// Result analytics (cached per release)
+	analyticsSvc := analytics.NewService(pgDB, gradingSvc, courseSvc)
+	registry.Register("analytics_service", analyticsSvc)
+	logrus.Info("analytics service registered")
+
+	// revaluation / re-totaling requests; revised marks supersede the original on-chain
+	revaluationSvc := revaluation.NewService(pgDB, chain.NewChain(store), gradingSvc, courseSvc, analyticsSvc, outboxSvc)
+	registry.Register("revaluation_service", revaluationSvc)
+	logrus.Info("revaluation service registered")
+
+	// moderation of submitted marks before release (signed with the node's chain key)
+	chainSigner, err := chain.LoadSigner(cfg.Block.SignerID, cfg.Block.PrivKeyPath)
+	if err != nil {
+		logrus.Warnf("no chain signing key, moderation changes disabled: %v", err)
+	}
+	moderationSvc := moderation.NewService(pgDB, chain.NewChain(store), gradingSvc, courseSvc, chainSigner, outboxSvc)
+	registry.Register("moderation_service", moderationSvc)
+	logrus.Info("moderation service registered")
+
+	// Evaluator leniency / anomaly detection
+	anomalySvc := anomaly.NewService(pgDB, gradingSvc, courseSvc)
+	registry.Register("anomaly_service", anomalySvc)
+	logrus.Info("anomaly service registered")
+
 	// student service
-	studentSvc := student.NewService(pgDB)
+	studentSvc := student.NewService(pgDB, gradingSvc, courseSvc)
 	registry.Register("student_service", studentSvc)
 	logrus.Info("student service registered")
 
@@ -275,6 +385,7 @@ func main() {
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 	<-stop
 	logrus.Info("shutdown signal received")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -0,0 +1,95 @@
+package analytics
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler exposes result analytics endpoints for authorities.
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) semester(w http.ResponseWriter, r *http.Request) (*SemesterAnalytics, bool) {
+	q := r.URL.Query()
+	semester := q.Get("semester")
+	academicYear := q.Get("academic_year")
+	if semester == "" || academicYear == "" {
+		http.Error(w, "semester and academic_year are required", http.StatusBadRequest)
+		return nil, false
+	}
+	a, err := h.svc.Semester(r.Context(), semester, academicYear)
+	if err != nil {
+		http.Error(w, "failed to compute analytics", http.StatusInternalServerError)
+		return nil, false
+	}
+	return a, true
+}
+
+// GET /api/v1/authority/analytics/semester?semester=...&academic_year=...
+func (h *Handler) Semester(w http.ResponseWriter, r *http.Request) {
+	if a, ok := h.semester(w, r); ok {
+		writeJSON(w, a, http.StatusOK)
+	}
+}
+
+// GET /api/v1/authority/analytics/courses?semester=...&academic_year=...&course_id=...
+// Pass rate, mean, median, histogram and question-wise averages per course.
+func (h *Handler) Courses(w http.ResponseWriter, r *http.Request) {
+	a, ok := h.semester(w, r)
+	if !ok {
+		return
+	}
+	courseID := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("course_id")))
+	if courseID == "" {
+		writeJSON(w, a.Courses, http.StatusOK)
+		return
+	}
+	for _, c := range a.Courses {
+		if c.CourseCode == courseID {
+			writeJSON(w, c, http.StatusOK)
+			return
+		}
+	}
+	http.Error(w, "no evaluations for course", http.StatusNotFound)
+}
+
+// GET /api/v1/authority/analytics/departments?semester=...&academic_year=...
+func (h *Handler) Departments(w http.ResponseWriter, r *http.Request) {
+	if a, ok := h.semester(w, r); ok {
+		writeJSON(w, a.Departments, http.StatusOK)
+	}
+}
+
+// GET /api/v1/authority/analytics/comparison?course_id=...|department=...
+// Semester-over-semester view across released semesters.
+func (h *Handler) Comparison(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	points, err := h.svc.Comparison(r.Context(), strings.ToUpper(strings.TrimSpace(q.Get("course_id"))), q.Get("department"))
+	if err != nil {
+		http.Error(w, "failed to compute comparison", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, points, http.StatusOK)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func RegisterAnalyticsRoutes(r *mux.Router, svc *Service) {
+	h := NewHandler(svc)
+	r.HandleFunc("/authority/analytics/semester", h.Semester).Methods("GET")
+	r.HandleFunc("/authority/analytics/courses", h.Courses).Methods("GET")
+	r.HandleFunc("/authority/analytics/departments", h.Departments).Methods("GET")
+	r.HandleFunc("/authority/analytics/comparison", h.Comparison).Methods("GET")
+}
@@ -0,0 +1,180 @@
+package analytics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"digital-eval-system/services/go-node/internal/course"
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/grading"
+)
+
+// SemesterAnalytics is the full statistics set for one semester sitting.
+type SemesterAnalytics struct {
+	Semester     string            `json:"semester"`
+	AcademicYear string            `json:"academic_year"`
+	Released     bool              `json:"released"`
+	ReleaseID    int64             `json:"release_id,omitempty"`
+	Summary      Summary           `json:"summary"`
+	Courses      []CourseStats     `json:"courses"`
+	Departments  []DepartmentStats `json:"departments"`
+	GeneratedAt  time.Time         `json:"generated_at"`
+}
+
+// ComparisonPoint is one released semester in a semester-over-semester view.
+type ComparisonPoint struct {
+	Semester     string  `json:"semester"`
+	AcademicYear string  `json:"academic_year"`
+	ReleaseID    int64   `json:"release_id"`
+	Evaluations  int     `json:"evaluations"`
+	Appeared     int     `json:"appeared"`
+	Passed       int     `json:"passed"`
+	PassRate     float64 `json:"pass_rate"`
+	Mean         float64 `json:"mean"`
+	Median       float64 `json:"median,omitempty"`
+}
+
+type cacheKey struct {
+	semester, academicYear string
+	releaseID              int64
+}
+
+// Service computes result analytics. Released semesters are immutable, so their
+// analytics are cached per release record; unreleased (provisional) semesters
+// are recomputed on every request.
+type Service struct {
+	pg      *db.PostgresDB
+	grading *grading.Service
+	courses *course.Service
+
+	mu    sync.Mutex
+	cache map[cacheKey]*SemesterAnalytics
+}
+
+func NewService(pg *db.PostgresDB, gradingSvc *grading.Service, courseSvc *course.Service) *Service {
+	return &Service{
+		pg:      pg,
+		grading: gradingSvc,
+		courses: courseSvc,
+		cache:   map[cacheKey]*SemesterAnalytics{},
+	}
+}
+
+// Semester returns analytics for a semester sitting, from cache when released.
+func (s *Service) Semester(ctx context.Context, semester, academicYear string) (*SemesterAnalytics, error) {
+	rel, err := s.pg.GetRelease(ctx, semester, academicYear)
+	if err != nil {
+		return nil, err
+	}
+	if rel == nil {
+		return s.compute(ctx, semester, academicYear, nil)
+	}
+
+	key := cacheKey{semester, academicYear, rel.ID}
+	s.mu.Lock()
+	cached, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	a, err := s.compute(ctx, semester, academicYear, rel)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.cache[key] = a
+	s.mu.Unlock()
+	return a, nil
+}
+
+// Invalidate drops cached analytics of a semester, e.g. after marks change post-release.
+func (s *Service) Invalidate(semester, academicYear string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k := range s.cache {
+		if k.semester == semester && k.academicYear == academicYear {
+			delete(s.cache, k)
+		}
+	}
+}
+
+// Comparison returns one point per released semester, optionally narrowed to
+// a course or a department.
+func (s *Service) Comparison(ctx context.Context, courseID, department string) ([]ComparisonPoint, error) {
+	releases, err := s.pg.ListReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := []ComparisonPoint{}
+	for _, rel := range releases {
+		a, err := s.Semester(ctx, rel.Semester, rel.AcademicYear)
+		if err != nil {
+			return nil, err
+		}
+		p := ComparisonPoint{Semester: rel.Semester, AcademicYear: rel.AcademicYear, ReleaseID: rel.ID}
+		switch {
+		case courseID != "":
+			found := false
+			for _, c := range a.Courses {
+				if c.CourseCode == courseID {
+					p.Evaluations, p.Appeared, p.Passed = c.Registered, c.Appeared, c.Passed
+					p.PassRate, p.Mean, p.Median = c.PassRate, c.Mean, c.Median
+					found = true
+				}
+			}
+			if !found {
+				continue
+			}
+		case department != "":
+			found := false
+			for _, d := range a.Departments {
+				if d.Department == department {
+					p.Evaluations, p.Appeared, p.Passed = d.Evaluations, d.Appeared, d.Passed
+					p.PassRate, p.Mean = d.PassRate, d.Mean
+					found = true
+				}
+			}
+			if !found {
+				continue
+			}
+		default:
+			p.Evaluations, p.Appeared, p.Passed = a.Summary.Evaluations, a.Summary.Appeared, a.Summary.Passed
+			p.PassRate, p.Mean, p.Median = a.Summary.PassRate, a.Summary.Mean, a.Summary.Median
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func (s *Service) compute(ctx context.Context, semester, academicYear string, rel *db.ReleaseRow) (*SemesterAnalytics, error) {
+	rows, err := s.pg.FetchResultsBySemester(ctx, semester, academicYear)
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, len(rows))
+	for _, r := range rows {
+		codes = append(codes, r.CourseID)
+	}
+	cat, err := s.courses.Catalog(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+	scheme := s.grading.Resolve(ctx, cat.Regulation(codes...), academicYear)
+
+	courses, depts, sum := computeSemester(rows, cat, scheme)
+	a := &SemesterAnalytics{
+		Semester:     semester,
+		AcademicYear: academicYear,
+		Summary:      sum,
+		Courses:      courses,
+		Departments:  depts,
+		GeneratedAt:  time.Now().UTC(),
+	}
+	if rel != nil {
+		a.Released = true
+		a.ReleaseID = rel.ID
+	}
+	return a, nil
+}
@@ -0,0 +1,295 @@
+package analytics
+
+import (
+	"encoding/json"
+	"sort"
+
+	"digital-eval-system/services/go-node/internal/course"
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/grading"
+	"digital-eval-system/services/go-node/internal/student"
+)
+
+// histogramBuckets is the number of equal-width percentage buckets (0-10, 10-20, ... 90-100).
+const histogramBuckets = 10
+
+// Bucket is one bar of a marks histogram, in percent of total marks.
+type Bucket struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+// QuestionStats is the average mark of one question across present scripts.
+type QuestionStats struct {
+	Question  int     `json:"question"`
+	Attempted int     `json:"attempted"`
+	Average   float64 `json:"average"`
+	MaxMarks  int     `json:"max_marks,omitempty"`
+}
+
+// CourseStats summarises one course in a semester.
+type CourseStats struct {
+	CourseCode string          `json:"course_code"`
+	CourseName string          `json:"course_name"`
+	Department string          `json:"department,omitempty"`
+	Registered int             `json:"registered"`
+	Appeared   int             `json:"appeared"`
+	Passed     int             `json:"passed"`
+	PassRate   float64         `json:"pass_rate"`
+	Mean       float64         `json:"mean"`
+	Median     float64         `json:"median"`
+	Min        float64         `json:"min"`
+	Max        float64         `json:"max"`
+	Histogram  []Bucket        `json:"histogram"`
+	Questions  []QuestionStats `json:"questions"`
+}
+
+// DepartmentStats aggregates the courses of one department.
+type DepartmentStats struct {
+	Department  string  `json:"department"`
+	Students    int     `json:"students"`
+	Evaluations int     `json:"evaluations"`
+	Appeared    int     `json:"appeared"`
+	Passed      int     `json:"passed"`
+	PassRate    float64 `json:"pass_rate"`
+	Mean        float64 `json:"mean"`
+}
+
+// Summary is the semester-wide headline used for comparisons.
+type Summary struct {
+	Students    int     `json:"students"`
+	Evaluations int     `json:"evaluations"`
+	Appeared    int     `json:"appeared"`
+	Passed      int     `json:"passed"`
+	PassRate    float64 `json:"pass_rate"`
+	Mean        float64 `json:"mean"`
+	Median      float64 `json:"median"`
+}
+
+// accumulator collects percentages and outcomes for a group of evaluations.
+type accumulator struct {
+	registered int
+	passed     int
+	scores     []float64
+	students   map[string]bool
+}
+
+func newAccumulator() *accumulator {
+	return &accumulator{students: map[string]bool{}}
+}
+
+func (a *accumulator) add(usn string, perc float64, appeared, passed bool) {
+	a.registered++
+	if usn != "" {
+		a.students[usn] = true
+	}
+	if !appeared {
+		return
+	}
+	a.scores = append(a.scores, perc)
+	if passed {
+		a.passed++
+	}
+}
+
+func (a *accumulator) passRate() float64 {
+	if len(a.scores) == 0 {
+		return 0
+	}
+	return round2(float64(a.passed) * 100 / float64(len(a.scores)))
+}
+
+func (a *accumulator) mean() float64 {
+	if len(a.scores) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range a.scores {
+		sum += v
+	}
+	return round2(sum / float64(len(a.scores)))
+}
+
+func (a *accumulator) median() float64 {
+	n := len(a.scores)
+	if n == 0 {
+		return 0
+	}
+	s := append([]float64(nil), a.scores...)
+	sort.Float64s(s)
+	if n%2 == 1 {
+		return round2(s[n/2])
+	}
+	return round2((s[n/2-1] + s[n/2]) / 2)
+}
+
+func (a *accumulator) histogram() []Bucket {
+	out := make([]Bucket, histogramBuckets)
+	width := 100 / histogramBuckets
+	for i := range out {
+		out[i] = Bucket{From: i * width, To: (i + 1) * width}
+	}
+	for _, v := range a.scores {
+		i := int(v) / width
+		if i >= histogramBuckets {
+			i = histogramBuckets - 1
+		}
+		if i < 0 {
+			i = 0
+		}
+		out[i].Count++
+	}
+	return out
+}
+
+// questionAccumulator sums per-question marks of present scripts.
+type questionAccumulator struct {
+	sums      []float64
+	attempted []int
+	maxMarks  []int
+	scripts   int
+}
+
+func (q *questionAccumulator) add(marks json.RawMessage) {
+	var m struct {
+		MarksScored   []int `json:"marks_scored"`
+		MarksAllotted []int `json:"marks_allotted"`
+	}
+	if err := json.Unmarshal(marks, &m); err != nil || len(m.MarksScored) == 0 {
+		return
+	}
+	q.scripts++
+	for len(q.sums) < len(m.MarksScored) {
+		q.sums = append(q.sums, 0)
+		q.attempted = append(q.attempted, 0)
+		q.maxMarks = append(q.maxMarks, 0)
+	}
+	for i, v := range m.MarksScored {
+		q.sums[i] += float64(v)
+		if v > 0 {
+			q.attempted[i]++
+		}
+		if i < len(m.MarksAllotted) && m.MarksAllotted[i] > q.maxMarks[i] {
+			q.maxMarks[i] = m.MarksAllotted[i]
+		}
+	}
+}
+
+func (q *questionAccumulator) stats() []QuestionStats {
+	out := make([]QuestionStats, 0, len(q.sums))
+	for i := range q.sums {
+		out = append(out, QuestionStats{
+			Question:  i + 1,
+			Attempted: q.attempted[i],
+			Average:   round2(q.sums[i] / float64(q.scripts)),
+			MaxMarks:  q.maxMarks[i],
+		})
+	}
+	return out
+}
+
+// computeSemester builds course, department and overall statistics. rows are
+// ordered oldest first; only the latest evaluation per student and course counts.
+func computeSemester(rows []db.EvaluationRow, cat course.Catalog, scheme *grading.Scheme) ([]CourseStats, []DepartmentStats, Summary) {
+	type key struct{ usn, course string }
+	latest := map[key]db.EvaluationRow{}
+	var order []key
+	for _, r := range rows {
+		k := key{r.StudentUSN.String, r.CourseID}
+		if !r.StudentUSN.Valid {
+			k.usn = r.ScriptID
+		}
+		if _, seen := latest[k]; !seen {
+			order = append(order, k)
+		}
+		latest[k] = r
+	}
+
+	courseAcc := map[string]*accumulator{}
+	questionAcc := map[string]*questionAccumulator{}
+	deptAcc := map[string]*accumulator{}
+	overall := newAccumulator()
+
+	for _, k := range order {
+		r := latest[k]
+		appeared := !scheme.IsNonAttempt(r.Result)
+		passed := r.Result == grading.ResultPass
+		perc := grading.Percentage(student.RowMarksScored(r), r.TotalMarks)
+
+		if courseAcc[r.CourseID] == nil {
+			courseAcc[r.CourseID] = newAccumulator()
+			questionAcc[r.CourseID] = &questionAccumulator{}
+		}
+		courseAcc[r.CourseID].add(k.usn, perc, appeared, passed)
+		if appeared {
+			questionAcc[r.CourseID].add(r.Marks)
+		}
+
+		dept := "UNASSIGNED"
+		if c, ok := cat.Get(r.CourseID); ok && c.Department != "" {
+			dept = c.Department
+		}
+		if deptAcc[dept] == nil {
+			deptAcc[dept] = newAccumulator()
+		}
+		deptAcc[dept].add(k.usn, perc, appeared, passed)
+		overall.add(k.usn, perc, appeared, passed)
+	}
+
+	courses := make([]CourseStats, 0, len(courseAcc))
+	for code, a := range courseAcc {
+		cs := CourseStats{
+			CourseCode: code,
+			CourseName: "-",
+			Registered: a.registered,
+			Appeared:   len(a.scores),
+			Passed:     a.passed,
+			PassRate:   a.passRate(),
+			Mean:       a.mean(),
+			Median:     a.median(),
+			Histogram:  a.histogram(),
+			Questions:  questionAcc[code].stats(),
+		}
+		if c, ok := cat.Get(code); ok {
+			cs.CourseName = c.CourseName
+			cs.Department = c.Department
+		}
+		if len(a.scores) > 0 {
+			s := append([]float64(nil), a.scores...)
+			sort.Float64s(s)
+			cs.Min, cs.Max = round2(s[0]), round2(s[len(s)-1])
+		}
+		courses = append(courses, cs)
+	}
+	sort.Slice(courses, func(i, j int) bool { return courses[i].CourseCode < courses[j].CourseCode })
+
+	depts := make([]DepartmentStats, 0, len(deptAcc))
+	for d, a := range deptAcc {
+		depts = append(depts, DepartmentStats{
+			Department:  d,
+			Students:    len(a.students),
+			Evaluations: a.registered,
+			Appeared:    len(a.scores),
+			Passed:      a.passed,
+			PassRate:    a.passRate(),
+			Mean:        a.mean(),
+		})
+	}
+	sort.Slice(depts, func(i, j int) bool { return depts[i].Department < depts[j].Department })
+
+	sum := Summary{
+		Students:    len(overall.students),
+		Evaluations: overall.registered,
+		Appeared:    len(overall.scores),
+		Passed:      overall.passed,
+		PassRate:    overall.passRate(),
+		Mean:        overall.mean(),
+		Median:      overall.median(),
+	}
+	return courses, depts, sum
+}
+
+func round2(v float64) float64 {
+	return (&grading.Scheme{Rounding: grading.RoundHalfUp, Decimals: 2}).Round(v)
+}
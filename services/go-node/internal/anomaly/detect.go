@@ -0,0 +1,336 @@
+package anomaly
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/grading"
+	"digital-eval-system/services/go-node/internal/student"
+)
+
+// Flag kinds raised by the detector.
+const (
+	KindLenient      = "lenient"
+	KindHarsh        = "harsh"
+	KindDistribution = "distribution"
+	KindIdentical    = "identical_marks"
+	KindFast         = "fast_evaluation"
+)
+
+// Options tune the detector; zero values fall back to the defaults below.
+type Options struct {
+	ZThreshold        float64 `json:"z_threshold"`         // |z| of evaluator mean vs peers (default 2.5)
+	KSAlpha           float64 `json:"ks_alpha"`            // KS test significance level (default 0.01)
+	MinScripts        int     `json:"min_scripts"`         // evaluator scripts needed before comparing (default 5)
+	IdenticalMinCount int     `json:"identical_min_count"` // repeats of one marks array before flagging (default 3)
+	IdenticalShare    float64 `json:"identical_share"`     // share of the evaluator's scripts (default 0.3)
+	MinSeconds        int     `json:"min_seconds"`         // assignment-to-submit time considered implausible (default 120)
+	FastShare         float64 `json:"fast_share"`          // share of fast scripts before flagging (default 0.2)
+}
+
+func (o Options) withDefaults() Options {
+	if o.ZThreshold <= 0 {
+		o.ZThreshold = 2.5
+	}
+	if o.KSAlpha <= 0 {
+		o.KSAlpha = 0.01
+	}
+	if o.MinScripts <= 0 {
+		o.MinScripts = 5
+	}
+	if o.IdenticalMinCount <= 0 {
+		o.IdenticalMinCount = 3
+	}
+	if o.IdenticalShare <= 0 {
+		o.IdenticalShare = 0.3
+	}
+	if o.MinSeconds <= 0 {
+		o.MinSeconds = 120
+	}
+	if o.FastShare <= 0 {
+		o.FastShare = 0.2
+	}
+	return o
+}
+
+// EvaluatorStats compares one evaluator's scripts on a course with their peers.
+type EvaluatorStats struct {
+	EvaluatorID    string  `json:"evaluator_id"`
+	CourseID       string  `json:"course_id"`
+	Scripts        int     `json:"scripts"`
+	Mean           float64 `json:"mean"`
+	PeerScripts    int     `json:"peer_scripts"`
+	PeerMean       float64 `json:"peer_mean"`
+	ZScore         float64 `json:"z_score"`
+	KSStatistic    float64 `json:"ks_statistic"`
+	KSPValue       float64 `json:"ks_p_value"`
+	MaxIdentical   int     `json:"max_identical"`
+	FastScripts    int     `json:"fast_scripts"`
+	MedianSeconds  float64 `json:"median_seconds"`
+	ComparedToPeer bool    `json:"compared_to_peers"`
+}
+
+// Flag is one anomaly for the authority to review.
+type Flag struct {
+	EvaluatorID string   `json:"evaluator_id"`
+	CourseID    string   `json:"course_id"`
+	Kind        string   `json:"kind"`
+	Detail      string   `json:"detail"`
+	ScriptIDs   []string `json:"script_ids,omitempty"`
+}
+
+// Report is the output of one detection run.
+type Report struct {
+	Semester     string           `json:"semester"`
+	AcademicYear string           `json:"academic_year"`
+	Options      Options          `json:"options"`
+	Evaluators   []EvaluatorStats `json:"evaluators"`
+	Flags        []Flag           `json:"flags"`
+	GeneratedAt  time.Time        `json:"generated_at"`
+}
+
+type script struct {
+	id      string
+	perc    float64
+	marks   string
+	seconds float64
+	timed   bool
+}
+
+// detect groups present scripts by course and evaluator and runs every check.
+func detect(rows []db.EvaluationRow, timings []db.EvaluationTimingRow, scheme *grading.Scheme, o Options) ([]EvaluatorStats, []Flag) {
+	o = o.withDefaults()
+
+	timing := map[string]db.EvaluationTimingRow{}
+	for _, t := range timings {
+		timing[t.ScriptID+"|"+t.EvaluatorID] = t
+	}
+
+	// course -> evaluator -> scripts
+	groups := map[string]map[string][]script{}
+	for _, r := range rows {
+		if scheme.IsNonAttempt(r.Result) {
+			continue
+		}
+		var m struct {
+			MarksScored []int `json:"marks_scored"`
+		}
+		_ = json.Unmarshal(r.Marks, &m)
+		key, _ := json.Marshal(m.MarksScored)
+
+		sc := script{
+			id:    r.ScriptID,
+			perc:  grading.Percentage(student.RowMarksScored(r), r.TotalMarks),
+			marks: string(key),
+		}
+		if t, ok := timing[r.ScriptID+"|"+r.Evaluator]; ok {
+			sc.seconds = t.EvaluatedAt.Sub(t.AssignedAt).Seconds()
+			sc.timed = true
+		}
+		if groups[r.CourseID] == nil {
+			groups[r.CourseID] = map[string][]script{}
+		}
+		groups[r.CourseID][r.Evaluator] = append(groups[r.CourseID][r.Evaluator], sc)
+	}
+
+	var stats []EvaluatorStats
+	var flags []Flag
+	for courseID, byEval := range groups {
+		for evalID, mine := range byEval {
+			var peers []float64
+			for other, s := range byEval {
+				if other == evalID {
+					continue
+				}
+				for _, sc := range s {
+					peers = append(peers, sc.perc)
+				}
+			}
+			own := percentages(mine)
+
+			st := EvaluatorStats{
+				EvaluatorID: evalID,
+				CourseID:    courseID,
+				Scripts:     len(mine),
+				Mean:        round2(mean(own)),
+				PeerScripts: len(peers),
+			}
+
+			// distribution vs peers
+			if len(mine) >= o.MinScripts && len(peers) >= o.MinScripts {
+				st.ComparedToPeer = true
+				st.PeerMean = round2(mean(peers))
+				if sd := stddev(peers); sd > 0 {
+					st.ZScore = round2((mean(own) - mean(peers)) / (sd / math.Sqrt(float64(len(own)))))
+				}
+				st.KSStatistic, st.KSPValue = ksTest(own, peers)
+				st.KSStatistic, st.KSPValue = round4(st.KSStatistic), round4(st.KSPValue)
+
+				switch {
+				case st.ZScore >= o.ZThreshold:
+					flags = append(flags, Flag{EvaluatorID: evalID, CourseID: courseID, Kind: KindLenient,
+						Detail: fmt.Sprintf("mean %.2f%% vs peers %.2f%% (z=%.2f)", st.Mean, st.PeerMean, st.ZScore)})
+				case st.ZScore <= -o.ZThreshold:
+					flags = append(flags, Flag{EvaluatorID: evalID, CourseID: courseID, Kind: KindHarsh,
+						Detail: fmt.Sprintf("mean %.2f%% vs peers %.2f%% (z=%.2f)", st.Mean, st.PeerMean, st.ZScore)})
+				case st.KSPValue < o.KSAlpha:
+					flags = append(flags, Flag{EvaluatorID: evalID, CourseID: courseID, Kind: KindDistribution,
+						Detail: fmt.Sprintf("score distribution differs from peers (KS D=%.3f, p=%.4f)", st.KSStatistic, st.KSPValue)})
+				}
+			}
+
+			// identical marks arrays
+			same := map[string][]string{}
+			for _, sc := range mine {
+				same[sc.marks] = append(same[sc.marks], sc.id)
+			}
+			for arr, ids := range same {
+				if len(ids) > st.MaxIdentical {
+					st.MaxIdentical = len(ids)
+				}
+				if len(ids) >= o.IdenticalMinCount && float64(len(ids)) >= o.IdenticalShare*float64(len(mine)) {
+					sort.Strings(ids)
+					flags = append(flags, Flag{EvaluatorID: evalID, CourseID: courseID, Kind: KindIdentical,
+						Detail:    fmt.Sprintf("%d of %d scripts have marks_scored %s", len(ids), len(mine), arr),
+						ScriptIDs: ids})
+				}
+			}
+
+			// implausibly fast evaluations
+			var secs []float64
+			var fast []string
+			for _, sc := range mine {
+				if !sc.timed {
+					continue
+				}
+				secs = append(secs, sc.seconds)
+				if sc.seconds < float64(o.MinSeconds) {
+					fast = append(fast, sc.id)
+				}
+			}
+			st.FastScripts = len(fast)
+			st.MedianSeconds = round2(median(secs))
+			if len(fast) > 0 && float64(len(fast)) >= o.FastShare*float64(len(secs)) {
+				sort.Strings(fast)
+				flags = append(flags, Flag{EvaluatorID: evalID, CourseID: courseID, Kind: KindFast,
+					Detail:    fmt.Sprintf("%d of %d scripts evaluated in under %ds", len(fast), len(secs), o.MinSeconds),
+					ScriptIDs: fast})
+			}
+
+			stats = append(stats, st)
+		}
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].CourseID != stats[j].CourseID {
+			return stats[i].CourseID < stats[j].CourseID
+		}
+		return stats[i].EvaluatorID < stats[j].EvaluatorID
+	})
+	sort.SliceStable(flags, func(i, j int) bool {
+		if flags[i].CourseID != flags[j].CourseID {
+			return flags[i].CourseID < flags[j].CourseID
+		}
+		return flags[i].EvaluatorID < flags[j].EvaluatorID
+	})
+	return stats, flags
+}
+
+func percentages(s []script) []float64 {
+	out := make([]float64, 0, len(s))
+	for _, sc := range s {
+		out = append(out, sc.perc)
+	}
+	return out
+}
+
+func mean(v []float64) float64 {
+	if len(v) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, x := range v {
+		sum += x
+	}
+	return sum / float64(len(v))
+}
+
+// stddev is the sample standard deviation.
+func stddev(v []float64) float64 {
+	if len(v) < 2 {
+		return 0
+	}
+	m := mean(v)
+	var ss float64
+	for _, x := range v {
+		ss += (x - m) * (x - m)
+	}
+	return math.Sqrt(ss / float64(len(v)-1))
+}
+
+func median(v []float64) float64 {
+	n := len(v)
+	if n == 0 {
+		return 0
+	}
+	s := append([]float64(nil), v...)
+	sort.Float64s(s)
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return (s[n/2-1] + s[n/2]) / 2
+}
+
+// ksTest runs the two-sample Kolmogorov-Smirnov test and returns the D
+// statistic with its asymptotic p-value.
+func ksTest(a, b []float64) (float64, float64) {
+	x := append([]float64(nil), a...)
+	y := append([]float64(nil), b...)
+	sort.Float64s(x)
+	sort.Float64s(y)
+
+	var d float64
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		v := math.Min(x[i], y[j])
+		for i < len(x) && x[i] <= v {
+			i++
+		}
+		for j < len(y) && y[j] <= v {
+			j++
+		}
+		diff := math.Abs(float64(i)/float64(len(x)) - float64(j)/float64(len(y)))
+		if diff > d {
+			d = diff
+		}
+	}
+
+	n := float64(len(x)) * float64(len(y)) / float64(len(x)+len(y))
+	en := math.Sqrt(n)
+	return d, kolmogorovQ((en + 0.12 + 0.11/en) * d)
+}
+
+// kolmogorovQ is the complementary Kolmogorov distribution Q_KS(lambda).
+func kolmogorovQ(lambda float64) float64 {
+	if lambda < 1e-3 {
+		return 1
+	}
+	var sum float64
+	sign := 1.0
+	for k := 1; k <= 100; k++ {
+		term := sign * 2 * math.Exp(-2*float64(k*k)*lambda*lambda)
+		sum += term
+		if math.Abs(term) < 1e-10 {
+			break
+		}
+		sign = -sign
+	}
+	return math.Max(0, math.Min(1, sum))
+}
+
+func round2(v float64) float64 { return math.Round(v*100) / 100 }
+func round4(v float64) float64 { return math.Round(v*10000) / 10000 }
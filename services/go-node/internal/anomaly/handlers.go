@@ -0,0 +1,97 @@
+package anomaly
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler exposes the anomaly detection job and its reports to the authority.
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// POST /api/v1/authority/anomalies/run
+// payload: { "semester": "5", "academic_year": "2024-2025", "generated_by": "authority_1", "options": {...} }
+func (h *Handler) Run(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Semester     string  `json:"semester"`
+		AcademicYear string  `json:"academic_year"`
+		GeneratedBy  string  `json:"generated_by"`
+		Options      Options `json:"options"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if payload.Semester == "" || payload.AcademicYear == "" || payload.GeneratedBy == "" {
+		http.Error(w, "missing fields", http.StatusBadRequest)
+		return
+	}
+	id, rep, err := h.svc.Run(r.Context(), payload.Semester, payload.AcademicYear, payload.GeneratedBy, payload.Options)
+	if err != nil {
+		http.Error(w, "anomaly detection failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"id": id, "report": rep}, http.StatusOK)
+}
+
+// GET /api/v1/authority/anomalies?semester=...&academic_year=...
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	rows, err := h.svc.List(r.Context(), q.Get("semester"), q.Get("academic_year"))
+	if err != nil {
+		http.Error(w, "failed to load reports", http.StatusInternalServerError)
+		return
+	}
+	out := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, map[string]interface{}{
+			"id":            row.ID,
+			"semester":      row.Semester,
+			"academic_year": row.AcademicYear,
+			"generated_by":  row.GeneratedBy,
+			"generated_at":  row.GeneratedAt,
+			"flag_count":    row.FlagCount,
+		})
+	}
+	writeJSON(w, out, http.StatusOK)
+}
+
+// GET /api/v1/authority/anomalies/{id}
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	rep, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, "failed to load report", http.StatusInternalServerError)
+		return
+	}
+	if rep == nil {
+		http.Error(w, "report not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, rep, http.StatusOK)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func RegisterAnomalyRoutes(r *mux.Router, svc *Service) {
+	h := NewHandler(svc)
+	r.HandleFunc("/authority/anomalies/run", h.Run).Methods("POST")
+	r.HandleFunc("/authority/anomalies", h.List).Methods("GET")
+	r.HandleFunc("/authority/anomalies/{id}", h.Get).Methods("GET")
+}
@@ -0,0 +1,94 @@
+package anomaly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"digital-eval-system/services/go-node/internal/course"
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/grading"
+)
+
+// Service runs the evaluator anomaly detection job and stores its reports.
+type Service struct {
+	pg      *db.PostgresDB
+	grading *grading.Service
+	courses *course.Service
+}
+
+func NewService(pg *db.PostgresDB, gradingSvc *grading.Service, courseSvc *course.Service) *Service {
+	return &Service{pg: pg, grading: gradingSvc, courses: courseSvc}
+}
+
+// Run analyses every evaluation of a semester, stores the report and returns it with its id.
+// Each evaluator is judged on the marks they gave: per valuation for
+// double-valued scripts, and before any moderation or revaluation otherwise.
+func (s *Service) Run(ctx context.Context, semester, academicYear, generatedBy string, o Options) (int64, *Report, error) {
+	rows, err := s.pg.FetchEvaluatorMarks(ctx, semester, academicYear)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(rows) == 0 {
+		return 0, nil, fmt.Errorf("no evaluations for semester %s %s", semester, academicYear)
+	}
+	timings, err := s.pg.FetchEvaluationTimings(ctx, semester, academicYear)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	codes := make([]string, 0, len(rows))
+	for _, r := range rows {
+		codes = append(codes, r.CourseID)
+	}
+	cat, err := s.courses.Catalog(ctx, codes)
+	if err != nil {
+		return 0, nil, err
+	}
+	scheme := s.grading.Resolve(ctx, cat.Regulation(codes...), academicYear)
+
+	stats, flags := detect(rows, timings, scheme, o)
+	rep := &Report{
+		Semester:     semester,
+		AcademicYear: academicYear,
+		Options:      o.withDefaults(),
+		Evaluators:   stats,
+		Flags:        flags,
+		GeneratedAt:  time.Now().UTC(),
+	}
+	if rep.Flags == nil {
+		rep.Flags = []Flag{}
+	}
+
+	data, err := json.Marshal(rep)
+	if err != nil {
+		return 0, nil, err
+	}
+	id, err := s.pg.InsertAnomalyReport(ctx, semester, academicYear, generatedBy, len(rep.Flags), data)
+	if err != nil {
+		return 0, nil, err
+	}
+	logrus.Infof("anomaly report %d for semester %s %s: %d flags", id, semester, academicYear, len(rep.Flags))
+	return id, rep, nil
+}
+
+// List returns stored report headers, newest first.
+func (s *Service) List(ctx context.Context, semester, academicYear string) ([]db.AnomalyReportRow, error) {
+	return s.pg.ListAnomalyReports(ctx, semester, academicYear)
+}
+
+// Get loads a stored report; nil, nil if not found.
+func (s *Service) Get(ctx context.Context, id int64) (*Report, error) {
+	row, err := s.pg.GetAnomalyReport(ctx, id)
+	if err != nil || row == nil {
+		return nil, err
+	}
+	var rep Report
+	if err := json.Unmarshal(row.Report, &rep); err != nil {
+		return nil, err
+	}
+	return &rep, nil
+}
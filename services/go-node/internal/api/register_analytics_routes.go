@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/analytics"
+	"digital-eval-system/services/go-node/internal/core"
+)
+
+// RegisterAnalyticsRoutes adds result analytics endpoints if service registered
+func RegisterAnalyticsRoutes(r *mux.Router, registry *core.ServiceRegistry) {
+	if svcIf, ok := registry.Get("analytics_service"); ok {
+		if svc, ok2 := svcIf.(*analytics.Service); ok2 {
+			analytics.RegisterAnalyticsRoutes(r, svc)
+		}
+	}
+}
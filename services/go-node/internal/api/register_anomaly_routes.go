@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/anomaly"
+	"digital-eval-system/services/go-node/internal/core"
+)
+
+// RegisterAnomalyRoutes adds evaluator anomaly detection endpoints if service registered
+func RegisterAnomalyRoutes(r *mux.Router, registry *core.ServiceRegistry) {
+	if svcIf, ok := registry.Get("anomaly_service"); ok {
+		if svc, ok2 := svcIf.(*anomaly.Service); ok2 {
+			anomaly.RegisterAnomalyRoutes(r, svc)
+		}
+	}
+}
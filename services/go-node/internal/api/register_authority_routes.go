@@ -16,6 +16,8 @@ func RegisterAuthorityRoutes(r *mux.Router, h *Handler, registry *core.ServiceRe
 			r.HandleFunc("/authority/requests/history", handler.ListHistory).Methods("GET")
 			r.HandleFunc("/authority/requests/{id}/approve", handler.ApproveRequest).Methods("POST")
 			r.HandleFunc("/authority/requests/{id}/reject", handler.RejectRequest).Methods("POST")
+			r.HandleFunc("/authority/assignments/distribute", handler.Distribute).Methods("POST")
+			r.HandleFunc("/authority/workload", handler.Workload).Methods("GET")
 		}
 	}
 	// else: no routes (service not configured)
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/blind"
+	"digital-eval-system/services/go-node/internal/core"
+)
+
+// RegisterBlindRoutes adds the blind evaluation mapping endpoints if service registered
+func RegisterBlindRoutes(r *mux.Router, registry *core.ServiceRegistry) {
+	if svcIf, ok := registry.Get("blind_service"); ok {
+		if svc, ok2 := svcIf.(*blind.Service); ok2 {
+			blind.RegisterBlindRoutes(r, svc)
+		}
+	}
+}
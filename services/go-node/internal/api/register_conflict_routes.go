@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/conflict"
+	"digital-eval-system/services/go-node/internal/core"
+)
+
+// RegisterConflictRoutes adds evaluator profile and conflict of interest endpoints if service registered
+func RegisterConflictRoutes(r *mux.Router, registry *core.ServiceRegistry) {
+	if svcIf, ok := registry.Get("conflict_service"); ok {
+		if svc, ok2 := svcIf.(*conflict.Service); ok2 {
+			conflict.RegisterConflictRoutes(r, svc)
+		}
+	}
+}
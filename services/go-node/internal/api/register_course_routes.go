@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/core"
+	"digital-eval-system/services/go-node/internal/course"
+)
+
+// RegisterCourseRoutes adds course catalog admin endpoints if service registered
+func RegisterCourseRoutes(r *mux.Router, registry *core.ServiceRegistry) {
+	if svcIf, ok := registry.Get("course_service"); ok {
+		if svc, ok2 := svcIf.(*course.Service); ok2 {
+			course.RegisterCourseRoutes(r, svc)
+		}
+	}
+}
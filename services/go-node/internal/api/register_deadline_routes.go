@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/core"
+	"digital-eval-system/services/go-node/internal/deadline"
+)
+
+// RegisterDeadlineRoutes adds assignment deadline endpoints if service registered
+func RegisterDeadlineRoutes(r *mux.Router, registry *core.ServiceRegistry) {
+	if svcIf, ok := registry.Get("deadline_service"); ok {
+		if svc, ok2 := svcIf.(*deadline.Service); ok2 {
+			deadline.RegisterDeadlineRoutes(r, svc)
+		}
+	}
+}
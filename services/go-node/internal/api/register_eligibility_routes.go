@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/core"
+	"digital-eval-system/services/go-node/internal/eligibility"
+)
+
+// RegisterEligibilityRoutes adds evaluator eligibility registry endpoints if service registered
+func RegisterEligibilityRoutes(r *mux.Router, registry *core.ServiceRegistry) {
+	if svcIf, ok := registry.Get("eligibility_service"); ok {
+		if svc, ok2 := svcIf.(*eligibility.Service); ok2 {
+			eligibility.RegisterEligibilityRoutes(r, svc)
+		}
+	}
+}
@@ -50,5 +50,6 @@ func RegisterEvaluatorRoutes(r *mux.Router, h *Handler, registry *core.ServiceRe
 		uploadSvc := uploadSvcIf.(*evaluator.UploadService)
 		uploadHandler := evaluator.NewUploadHandler(uploadSvc, "")
 		r.Handle("/evaluator/upload", uploadHandler).Methods("POST")
+		r.HandleFunc("/authority/scripts/{script_id}/annotated", uploadHandler.ListAnnotated).Methods("GET")
 	}
 }
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/core"
+	"digital-eval-system/services/go-node/internal/exampattern"
+)
+
+// RegisterExamPatternRoutes adds exam pattern admin endpoints if service registered
+func RegisterExamPatternRoutes(r *mux.Router, registry *core.ServiceRegistry) {
+	if svcIf, ok := registry.Get("exam_pattern_service"); ok {
+		if svc, ok2 := svcIf.(*exampattern.Service); ok2 {
+			exampattern.RegisterExamPatternRoutes(r, svc)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/core"
+	"digital-eval-system/services/go-node/internal/grading"
+)
+
+// RegisterGradingRoutes adds grading scheme admin endpoints if service registered
+func RegisterGradingRoutes(r *mux.Router, registry *core.ServiceRegistry) {
+	if svcIf, ok := registry.Get("grading_service"); ok {
+		if svc, ok2 := svcIf.(*grading.Service); ok2 {
+			grading.RegisterGradingRoutes(r, svc)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/auth"
+	"digital-eval-system/services/go-node/internal/core"
+	"digital-eval-system/services/go-node/internal/moderation"
+)
+
+// RegisterModerationRoutes adds authority moderation endpoints if service registered
+func RegisterModerationRoutes(r *mux.Router, registry *core.ServiceRegistry, jwtMgr *auth.Manager) {
+	if svcIf, ok := registry.Get("moderation_service"); ok {
+		if svc, ok2 := svcIf.(*moderation.Service); ok2 {
+			moderation.RegisterModerationRoutes(r, svc, jwtMgr)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/core"
+	"digital-eval-system/services/go-node/internal/outbox"
+)
+
+// RegisterOutboxRoutes adds the admin outbox endpoints if service registered
+func RegisterOutboxRoutes(r *mux.Router, registry *core.ServiceRegistry) {
+	if svcIf, ok := registry.Get("outbox_service"); ok {
+		if svc, ok2 := svcIf.(*outbox.Service); ok2 {
+			outbox.RegisterOutboxRoutes(r, svc)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/core"
+	"digital-eval-system/services/go-node/internal/remuneration"
+)
+
+// RegisterRemunerationRoutes adds remuneration rate and statement endpoints if service registered
+func RegisterRemunerationRoutes(r *mux.Router, registry *core.ServiceRegistry) {
+	if svcIf, ok := registry.Get("remuneration_service"); ok {
+		if svc, ok2 := svcIf.(*remuneration.Service); ok2 {
+			remuneration.RegisterRemunerationRoutes(r, svc)
+		}
+	}
+}
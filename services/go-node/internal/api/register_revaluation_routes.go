@@ -0,0 +1,19 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/auth"
+	"digital-eval-system/services/go-node/internal/core"
+	"digital-eval-system/services/go-node/internal/revaluation"
+	"digital-eval-system/services/go-node/internal/student"
+)
+
+// RegisterRevaluationRoutes adds revaluation workflow endpoints if service registered
+func RegisterRevaluationRoutes(r *mux.Router, registry *core.ServiceRegistry, studentSvc *student.Service, jwtMgr *auth.Manager) {
+	if svcIf, ok := registry.Get("revaluation_service"); ok {
+		if svc, ok2 := svcIf.(*revaluation.Service); ok2 {
+			revaluation.RegisterRevaluationRoutes(r, svc, studentSvc, jwtMgr)
+		}
+	}
+}
@@ -3,12 +3,13 @@ package api
 import (
 	"github.com/gorilla/mux"
 
+	"digital-eval-system/services/go-node/internal/auth"
 	"digital-eval-system/services/go-node/internal/student"
 )
 
-// RegisterStudentRoutes wires student endpoints
-func RegisterStudentRoutes(r *mux.Router, studentSvc *student.Service) {
+// RegisterStudentRoutes wires student endpoints (student token required)
+func RegisterStudentRoutes(r *mux.Router, studentSvc *student.Service, jwtMgr *auth.Manager) {
 	if studentSvc != nil {
-		student.RegisterStudentRoutes(r, studentSvc)
+		student.RegisterStudentRoutes(r, studentSvc, jwtMgr)
 	}
 }
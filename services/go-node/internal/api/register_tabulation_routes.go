@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/core"
+	"digital-eval-system/services/go-node/internal/tabulation"
+)
+
+// RegisterTabulationRoutes adds tabulation register exports if service registered
+func RegisterTabulationRoutes(r *mux.Router, registry *core.ServiceRegistry) {
+	if svcIf, ok := registry.Get("tabulation_service"); ok {
+		if svc, ok2 := svcIf.(*tabulation.Service); ok2 {
+			tabulation.RegisterTabulationRoutes(r, svc)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/core"
+	"digital-eval-system/services/go-node/internal/valuation"
+)
+
+// RegisterValuationRoutes adds valuation policy and third valuation endpoints if service registered
+func RegisterValuationRoutes(r *mux.Router, registry *core.ServiceRegistry) {
+	if svcIf, ok := registry.Get("valuation_service"); ok {
+		if svc, ok2 := svcIf.(*valuation.Service); ok2 {
+			valuation.RegisterValuationRoutes(r, svc)
+		}
+	}
+}
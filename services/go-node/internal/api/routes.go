@@ -23,7 +23,8 @@ func NewRouter(h *Handler) http.Handler {
 	// JSON-only except upload
 	r.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if r.URL.Path == "/api/v1/examiner/upload" || r.URL.Path == "/api/v1/evaluator/upload" {
+			if r.URL.Path == "/api/v1/examiner/upload" || r.URL.Path == "/api/v1/evaluator/upload" ||
+				r.URL.Path == "/api/v1/admin/courses/import" {
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -79,16 +80,31 @@ func NewRouter(h *Handler) http.Handler {
 	// Release results
 	releaseSvc := h.registry.MustGet("authority_release_service").(*authority.ReleaseService)
 	authority.RegisterReleaseRoutes(apiR, releaseSvc)
+	RegisterTabulationRoutes(apiR, h.registry)
+	RegisterAnalyticsRoutes(apiR, h.registry)
+	RegisterAnomalyRoutes(apiR, h.registry)
+	RegisterModerationRoutes(apiR, h.registry, authSvc.JWTManager())
+	RegisterDeadlineRoutes(apiR, h.registry)
+	RegisterConflictRoutes(apiR, h.registry)
+	RegisterEligibilityRoutes(apiR, h.registry)
+	RegisterBlindRoutes(apiR, h.registry)
+	RegisterOutboxRoutes(apiR, h.registry)
+	RegisterRemunerationRoutes(apiR, h.registry)
 
 	// Student result access (correct mounting under /api/v1)
+	// Requires a student token; the USN comes from the account, not the query.
 	studentSvc := h.registry.MustGet("student_service").(*student.Service)
-	studentHandler := student.NewHandler(studentSvc)
+	RegisterStudentRoutes(apiR, studentSvc, authSvc.JWTManager())
 
-	// MOUNT inside /api/v1
-	apiR.HandleFunc("/student/results", studentHandler.GetResults).Methods("GET")
-	apiR.HandleFunc("/student/download", studentHandler.DownloadPDF).Methods("GET")
+	// Revaluation / re-totaling: students file, authority approves, evaluators revalue
+	RegisterRevaluationRoutes(apiR, h.registry, studentSvc, authSvc.JWTManager())
 
 	// ADMIN ROUTES
+	RegisterGradingRoutes(apiR, h.registry)
+	RegisterCourseRoutes(apiR, h.registry)
+	RegisterExamPatternRoutes(apiR, h.registry)
+	RegisterValuationRoutes(apiR, h.registry)
+	student.RegisterStudentAccountRoutes(apiR, studentSvc, authSvc.JWTManager())
 	if val, ok := h.registry.Get("admin_service"); ok {
 		if adminSvc, ok := val.(*admin.Service); ok {
 			RegisterAdminRoutes(apiR, adminSvc)
@@ -0,0 +1,370 @@
+package authority
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"digital-eval-system/services/go-node/internal/block"
+	"digital-eval-system/services/go-node/internal/conflict"
+	"digital-eval-system/services/go-node/internal/course"
+	"digital-eval-system/services/go-node/internal/valuation"
+)
+
+// PoolStats explains which uploaded scripts of a course could be assigned
+// to an evaluator.
+type PoolStats struct {
+	Uploaded        int `json:"uploaded"`          // upload records for the course and semester
+	Evaluated       int `json:"evaluated"`         // already have a final evaluation
+	FullyAssigned   int `json:"fully_assigned"`    // every valuation round is taken
+	HeldByEvaluator int `json:"held_by_evaluator"` // the evaluator holds or held a round
+	Conflicted      int `json:"conflicted"`        // withheld by a conflict of interest rule
+	Available       int `json:"available"`
+}
+
+// FailedAssignment is a candidate script whose assignment could not be created.
+type FailedAssignment struct {
+	ScriptID string `json:"script_id"`
+	Error    string `json:"error"`
+}
+
+// AssignmentReport is the outcome of assigning scripts to one evaluator.
+type AssignmentReport struct {
+	EvaluatorID string               `json:"evaluator_id"`
+	CourseID    string               `json:"course_id"`
+	Semester    string               `json:"semester"`
+	Requested   int                  `json:"requested"`
+	Assigned    []string             `json:"assigned"`
+	Shortfall   int                  `json:"shortfall"`
+	Reason      string               `json:"reason,omitempty"`
+	OpenBefore  int                  `json:"open_before"` // the evaluator's open assignments before this one
+	Pool        PoolStats            `json:"pool"`
+	Excluded    []conflict.Exclusion `json:"excluded,omitempty"`
+	Failed      []FailedAssignment   `json:"failed,omitempty"`
+}
+
+// EvaluatorShare is one evaluator's part of a distribution.
+type EvaluatorShare struct {
+	EvaluatorID string   `json:"evaluator_id"`
+	OpenBefore  int      `json:"open_before"`
+	Assigned    []string `json:"assigned"`
+}
+
+// DistributionReport is the outcome of spreading a course's unassigned
+// scripts over its evaluators.
+type DistributionReport struct {
+	CourseID   string               `json:"course_id"`
+	Semester   string               `json:"semester"`
+	Evaluators []EvaluatorShare     `json:"evaluators"`
+	Assigned   int                  `json:"assigned"`
+	Remaining  int                  `json:"remaining"` // script rounds still without an evaluator
+	Reason     string               `json:"reason,omitempty"`
+	Excluded   []conflict.Exclusion `json:"excluded,omitempty"`
+	Failed     []FailedAssignment   `json:"failed,omitempty"`
+}
+
+// candidate is a script round that can be assigned.
+type candidate struct {
+	scriptID string
+	round    int
+}
+
+// pool is the assignment state of one course and semester.
+type pool struct {
+	scripts   []string                   // uploaded script ids, upload order
+	usn       map[string]string          // student USN per script
+	evaluated map[string]bool            // scripts with an evaluation
+	taken     map[string]map[int]bool    // live rounds per script
+	holders   map[string]map[string]bool // evaluators who ever held a script
+	rounds    int
+}
+
+// loadPool reads the uploaded scripts of a course and semester from the
+// chain and their assignment state from Postgres.
+func (s *Service) loadPool(ctx context.Context, courseID, semester string, policy *valuation.Policy) (*pool, error) {
+	p := &pool{
+		usn:     map[string]string{},
+		taken:   map[string]map[int]bool{},
+		holders: map[string]map[string]bool{},
+		rounds:  policy.Rounds(),
+	}
+	seen := map[string]bool{}
+	err := s.store.ForEachBlock(func(blk *block.Block) {
+		for i := range blk.Transactions {
+			tx := &blk.Transactions[i]
+			if !isUpload(tx) || seen[tx.ScriptID] {
+				continue
+			}
+			if strings.EqualFold(strings.TrimSpace(tx.CourseID), courseID) && strings.TrimSpace(tx.Semester) == semester {
+				seen[tx.ScriptID] = true
+				p.scripts = append(p.scripts, tx.ScriptID)
+				p.usn[tx.ScriptID] = tx.USN
+				if tx.USN == "" {
+					p.usn[tx.ScriptID] = tx.Meta["USN"]
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if p.evaluated, err = s.db.ListEvaluatedScripts(ctx, courseID, semester); err != nil {
+		return nil, fmt.Errorf("load evaluations: %w", err)
+	}
+	all, err := s.db.ListAssignments(ctx, "", courseID, "", false)
+	if err != nil {
+		return nil, fmt.Errorf("load assignments: %w", err)
+	}
+	for _, a := range all {
+		if a.Semester != semester {
+			continue
+		}
+		p.hold(a.ScriptID, a.Evaluator)
+		if a.Status != "revoked" {
+			p.take(a.ScriptID, a.ValuationRound)
+		}
+	}
+	return p, nil
+}
+
+// isUpload reports whether a transaction is a script upload record rather
+// than an evaluation, valuation, release or other derived transaction.
+func isUpload(tx *block.Transaction) bool {
+	if _, ok := tx.Meta["_upload_record"]; ok {
+		return true
+	}
+	for k := range tx.Meta {
+		if strings.HasPrefix(k, "_") {
+			return false
+		}
+	}
+	return block.ValidateTransaction(tx)
+}
+
+func (p *pool) hold(scriptID, evaluatorID string) {
+	if p.holders[scriptID] == nil {
+		p.holders[scriptID] = map[string]bool{}
+	}
+	p.holders[scriptID][evaluatorID] = true
+}
+
+func (p *pool) take(scriptID string, round int) {
+	if p.taken[scriptID] == nil {
+		p.taken[scriptID] = map[int]bool{}
+	}
+	p.taken[scriptID][round] = true
+}
+
+// nextRound returns the first free valuation round of a script, 0 if none.
+func (p *pool) nextRound(scriptID string) int {
+	for round := 1; round <= p.rounds; round++ {
+		if !p.taken[scriptID][round] {
+			return round
+		}
+	}
+	return 0
+}
+
+// candidates lists the script rounds an evaluator can take and the scripts
+// the conflict of interest rules withhold from them.
+func (p *pool) candidates(evaluatorID string, chk *conflict.Checker) ([]candidate, []conflict.Exclusion, PoolStats) {
+	st := PoolStats{Uploaded: len(p.scripts)}
+	var out []candidate
+	var excl []conflict.Exclusion
+	for _, sid := range p.scripts {
+		round := p.nextRound(sid)
+		switch {
+		case p.evaluated[sid]:
+			st.Evaluated++
+		case round == 0:
+			st.FullyAssigned++
+		case p.holders[sid][evaluatorID]:
+			st.HeldByEvaluator++
+		default:
+			if e := chk.Check(sid, p.usn[sid]); e != nil {
+				st.Conflicted++
+				excl = append(excl, *e)
+				continue
+			}
+			out = append(out, candidate{scriptID: sid, round: round})
+		}
+	}
+	st.Available = len(out)
+	return out, excl, st
+}
+
+// remaining counts script rounds that still need an evaluator.
+func (p *pool) remaining() int {
+	n := 0
+	for _, sid := range p.scripts {
+		if p.evaluated[sid] {
+			continue
+		}
+		for round := 1; round <= p.rounds; round++ {
+			if !p.taken[sid][round] {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// assignOne creates the assignment of a candidate and updates the pool.
+func (s *Service) assignOne(ctx context.Context, p *pool, c candidate, evaluatorID, courseID, semester, academicYear string, co *course.Course, dueDays int) error {
+	if _, err := s.db.CreateAssignment(ctx, c.scriptID, evaluatorID, courseID, semester, academicYear, co.Credits, c.round, s.deadlines.DueAt(dueDays)); err != nil {
+		return err
+	}
+	p.hold(c.scriptID, evaluatorID)
+	p.take(c.scriptID, c.round)
+	return nil
+}
+
+// shuffleByRound randomises candidates within each valuation round.
+func (s *Service) shuffleByRound(cands []candidate) {
+	s.rand.Shuffle(len(cands), func(i, j int) { cands[i], cands[j] = cands[j], cands[i] })
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].round < cands[j].round })
+}
+
+// AssignScripts assigns exactly n uploaded, unassigned scripts of a course to
+// an evaluator, or as many as exist with a report explaining the shortfall.
+func (s *Service) AssignScripts(ctx context.Context, evaluatorID, courseID, semester, academicYear string, n, dueDays int) (*AssignmentReport, error) {
+	co, err := s.courses.Get(ctx, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("course lookup failed: %w", err)
+	}
+	if co == nil {
+		return nil, fmt.Errorf("course %s is not in the course catalog", courseID)
+	}
+	courseID = co.CourseCode
+	if n <= 0 {
+		return nil, fmt.Errorf("number of scripts must be > 0")
+	}
+
+	policy := s.valuation.Resolve(ctx, courseID, semester)
+	p, err := s.loadPool(ctx, courseID, semester, policy)
+	if err != nil {
+		return nil, err
+	}
+	rep := &AssignmentReport{EvaluatorID: evaluatorID, CourseID: courseID, Semester: semester, Requested: n, Assigned: []string{}}
+	if rep.OpenBefore, err = s.db.CountOpenAssignments(ctx, evaluatorID); err != nil {
+		return nil, fmt.Errorf("load evaluator load: %w", err)
+	}
+	chk, err := s.conflicts.Checker(ctx, evaluatorID)
+	if err != nil {
+		return nil, err
+	}
+	cands, excl, stats := p.candidates(evaluatorID, chk)
+	rep.Pool = stats
+	rep.Excluded = excl
+	s.conflicts.Record(ctx, courseID, semester, excl)
+	s.shuffleByRound(cands)
+
+	for _, c := range cands {
+		if len(rep.Assigned) == n {
+			break
+		}
+		if err := s.assignOne(ctx, p, c, evaluatorID, courseID, semester, academicYear, co, dueDays); err != nil {
+			logrus.Warnf("failed to create assignment for script %s: %v", c.scriptID, err)
+			rep.Failed = append(rep.Failed, FailedAssignment{ScriptID: c.scriptID, Error: err.Error()})
+			continue
+		}
+		rep.Assigned = append(rep.Assigned, c.scriptID)
+	}
+	rep.Shortfall = n - len(rep.Assigned)
+	if rep.Shortfall > 0 {
+		rep.Reason = fmt.Sprintf("only %d of %d scripts could be assigned: %d uploaded, %d already evaluated, %d fully assigned, %d already held by %s, %d withheld by conflict of interest rules, %d failed",
+			len(rep.Assigned), n, stats.Uploaded, stats.Evaluated, stats.FullyAssigned, stats.HeldByEvaluator, evaluatorID, stats.Conflicted, len(rep.Failed))
+	}
+	return rep, nil
+}
+
+// Distribute spreads the unassigned scripts of a course over its approved
+// evaluators, always giving the next script to the least loaded evaluator.
+// perEvaluator > 0 caps how many scripts each evaluator receives.
+func (s *Service) Distribute(ctx context.Context, courseID, semester, academicYear string, perEvaluator, dueDays int) (*DistributionReport, error) {
+	co, err := s.courses.Get(ctx, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("course lookup failed: %w", err)
+	}
+	if co == nil {
+		return nil, fmt.Errorf("course %s is not in the course catalog", courseID)
+	}
+	courseID = co.CourseCode
+
+	policy := s.valuation.Resolve(ctx, courseID, semester)
+	p, err := s.loadPool(ctx, courseID, semester, policy)
+	if err != nil {
+		return nil, err
+	}
+	evaluators, err := s.db.ListCourseEvaluators(ctx, courseID, semester)
+	if err != nil {
+		return nil, fmt.Errorf("load evaluators: %w", err)
+	}
+	rep := &DistributionReport{CourseID: courseID, Semester: semester}
+	if len(evaluators) == 0 {
+		rep.Remaining = p.remaining()
+		rep.Reason = "no evaluator is approved for this course and semester"
+		return rep, nil
+	}
+
+	shares := make([]EvaluatorShare, len(evaluators))
+	load := make([]int, len(evaluators))
+	done := make([]bool, len(evaluators))
+	checkers := make([]*conflict.Checker, len(evaluators))
+	for i, e := range evaluators {
+		shares[i] = EvaluatorShare{EvaluatorID: e.EvaluatorID, OpenBefore: e.Open, Assigned: []string{}}
+		load[i] = e.Open
+		if checkers[i], err = s.conflicts.Checker(ctx, e.EvaluatorID); err != nil {
+			return nil, err
+		}
+	}
+	excluded := map[string]bool{} // evaluator/script pairs already reported
+	for {
+		// least loaded evaluator that can still take a script
+		pick := -1
+		for i := range evaluators {
+			if done[i] || (perEvaluator > 0 && len(shares[i].Assigned) >= perEvaluator) {
+				continue
+			}
+			if pick < 0 || load[i] < load[pick] {
+				pick = i
+			}
+		}
+		if pick < 0 {
+			break
+		}
+		cands, excl, _ := p.candidates(shares[pick].EvaluatorID, checkers[pick])
+		for _, e := range excl {
+			if key := e.EvaluatorID + "/" + e.ScriptID; !excluded[key] {
+				excluded[key] = true
+				rep.Excluded = append(rep.Excluded, e)
+			}
+		}
+		if len(cands) == 0 {
+			done[pick] = true
+			continue
+		}
+		s.shuffleByRound(cands)
+		c := cands[0]
+		if err := s.assignOne(ctx, p, c, shares[pick].EvaluatorID, courseID, semester, academicYear, co, dueDays); err != nil {
+			logrus.Warnf("failed to create assignment for script %s: %v", c.scriptID, err)
+			rep.Failed = append(rep.Failed, FailedAssignment{ScriptID: c.scriptID, Error: err.Error()})
+			// keep the failing script away from this evaluator in this run
+			p.hold(c.scriptID, shares[pick].EvaluatorID)
+			continue
+		}
+		shares[pick].Assigned = append(shares[pick].Assigned, c.scriptID)
+		load[pick]++
+		rep.Assigned++
+	}
+	rep.Evaluators = shares
+	rep.Remaining = p.remaining()
+	s.conflicts.Record(ctx, courseID, semester, rep.Excluded)
+	if rep.Remaining > 0 {
+		rep.Reason = fmt.Sprintf("%d script rounds still need an evaluator: every approved evaluator has reached the cap, already holds them or is excluded by a conflict of interest rule", rep.Remaining)
+	}
+	return rep, nil
+}
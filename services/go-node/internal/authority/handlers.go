@@ -2,10 +2,13 @@ package authority
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/eligibility"
 )
 
 // Handler wraps service and exposes HTTP endpoints.
@@ -77,12 +80,37 @@ func (h *Handler) ApproveRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	assigned, err := h.svc.ApproveRequest(r.Context(), id, target.EvaluatorID, target.CourseID, target.Semester, target.AcademicYear, p.AssignNum)
+	report, err := h.svc.ApproveRequest(r.Context(), id, target.EvaluatorID, target.CourseID, target.Semester, target.AcademicYear, p.AssignNum, p.DueDays)
+	if errors.Is(err, eligibility.ErrIneligible) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "approve failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"assigned": report.Assigned, "report": report}, http.StatusOK)
+}
+
+// POST /api/v1/authority/assignments/distribute
+// Body: {"course_id":"...","semester":"...","academic_year":"...","per_evaluator":0,"due_days":0}
+// Spreads the course's unassigned scripts over its approved evaluators, least loaded first.
+func (h *Handler) Distribute(w http.ResponseWriter, r *http.Request) {
+	var p DistributePayload
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if p.CourseID == "" || p.Semester == "" {
+		http.Error(w, "course_id and semester are required", http.StatusBadRequest)
+		return
+	}
+	report, err := h.svc.Distribute(r.Context(), p.CourseID, p.Semester, p.AcademicYear, p.PerEvaluator, p.DueDays)
 	if err != nil {
-		http.Error(w, "approve failed", http.StatusInternalServerError)
+		http.Error(w, "distribute failed: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	writeJSON(w, map[string]interface{}{"assigned": assigned}, http.StatusOK)
+	writeJSON(w, report, http.StatusOK)
 }
 
 // POST /api/v1/authority/requests/{id}/reject
@@ -100,3 +128,25 @@ func (h *Handler) RejectRequest(w http.ResponseWriter, r *http.Request) {
 	}
 	writeJSON(w, map[string]interface{}{"status": "rejected"}, http.StatusOK)
 }
+
+// GET /api/v1/authority/workload?semester=&academic_year=&course_id=&window_days=
+// Per-evaluator load and pace and per-course completion with projected
+// completion dates.
+func (h *Handler) Workload(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := WorkloadFilter{Semester: q.Get("semester"), AcademicYear: q.Get("academic_year"), CourseID: q.Get("course_id")}
+	if v := q.Get("window_days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 365 {
+			http.Error(w, "window_days must be between 1 and 365", http.StatusBadRequest)
+			return
+		}
+		f.WindowDays = n
+	}
+	rep, err := h.svc.Workload(r.Context(), f)
+	if err != nil {
+		http.Error(w, "failed to build workload: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rep, http.StatusOK)
+}
@@ -1,6 +1,11 @@
 package authority
 
-import "time"
+import (
+	"time"
+
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/eligibility"
+)
 
 // Authority-side request / assignment models
 
@@ -14,7 +19,23 @@ type RequestRow struct {
 	CreatedAt    time.Time `json:"created_at"`
 }
 
+// PendingRequest is a pending request with the evaluator's current
+// eligibility for the course, for the authority deciding on it.
+type PendingRequest struct {
+	db.EvalRequestRow
+	Eligibility *eligibility.Decision `json:"eligibility"`
+}
+
 type ApprovePayload struct {
 	RequestID int64 `json:"request_id"`
 	AssignNum int   `json:"assign_num"` // number of scripts to assign (default 5)
+	DueDays   int   `json:"due_days"`   // days to evaluate (default from the deadline policy)
+}
+
+type DistributePayload struct {
+	CourseID     string `json:"course_id"`
+	Semester     string `json:"semester"`
+	AcademicYear string `json:"academic_year"`
+	PerEvaluator int    `json:"per_evaluator"` // cap per evaluator (0 = no cap)
+	DueDays      int    `json:"due_days"`
 }
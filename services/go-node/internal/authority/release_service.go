@@ -8,8 +8,10 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"digital-eval-system/services/go-node/internal/blind"
 	"digital-eval-system/services/go-node/internal/block"
 	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/outbox"
 )
 
 // ReleaseService performs semester result release
@@ -18,16 +20,27 @@ type ReleaseService struct {
 	chain interface {
 		AppendBlock(*block.Block) (string, error)
 	}
+	blind  *blind.Service
+	outbox *outbox.Service
 }
 
 func NewReleaseService(pg *db.PostgresDB, chain interface {
 	AppendBlock(*block.Block) (string, error)
-}) *ReleaseService {
-	return &ReleaseService{pg: pg, chain: chain}
+}, blindSvc *blind.Service, outboxSvc *outbox.Service) *ReleaseService {
+	return &ReleaseService{pg: pg, chain: chain, blind: blindSvc, outbox: outboxSvc}
 }
 
 // ReleaseResults aggregates evaluations for semester, writes a release block, and records release.
 func (s *ReleaseService) ReleaseResults(ctx context.Context, semester, academicYear, releasedBy string) (string, error) {
+	// a semester is released once; a second release block could never be recorded
+	prev, err := s.pg.GetRelease(ctx, semester, academicYear)
+	if err != nil {
+		return "", err
+	}
+	if prev != nil {
+		return "", fmt.Errorf("semester %s %s already released in block %s", semester, academicYear, prev.BlockHash.String)
+	}
+
 	// 1. fetch all evaluations for semester
 	rows, err := s.pg.FetchResultsBySemester(ctx, semester, academicYear)
 	if err != nil {
@@ -74,15 +87,24 @@ func (s *ReleaseService) ReleaseResults(ctx context.Context, semester, academicY
 		SignerID:     releasedBy,
 	}
 	newBlock := block.NewBlock("", []block.Transaction{tx}, releasedBy)
-	blockHash, err := s.chain.AppendBlock(newBlock)
+	blockHash, pending, err := s.outbox.Append(ctx, s.chain, newBlock, outbox.Effect{
+		Kind:    outbox.KindResultRelease,
+		Payload: outbox.ResultRelease{Semester: semester, AcademicYear: academicYear, ReleasedBy: releasedBy},
+	})
 	if err != nil {
 		return "", err
 	}
 
-	// 4. insert a record in postgres
-	_, err = s.pg.RecordRelease(ctx, semester, academicYear, releasedBy, blockHash)
-	if err != nil {
-		logrus.Warnf("failed to record release in pg: %v", err)
+	// 4. insert a record in postgres (retried from the outbox until it succeeds)
+	if err := s.outbox.Deliver(ctx, pending...); err != nil {
+		logrus.Warnf("release of semester %s %s queued for retry: %v", semester, academicYear, err)
+	}
+
+	// 5. released scripts may now be traced from dummy number to student
+	if n, err := s.blind.Reveal(ctx, semester, academicYear, releasedBy); err != nil {
+		logrus.Warnf("failed to reveal dummy numbers: %v", err)
+	} else if n > 0 {
+		logrus.Infof("revealed %d dummy numbers for semester %s %s", n, semester, academicYear)
 	}
 
 	return blockHash, nil
@@ -7,30 +7,57 @@ import (
 
 	"github.com/sirupsen/logrus"
 
-	"digital-eval-system/services/go-node/internal/block"
+	"digital-eval-system/services/go-node/internal/conflict"
+	"digital-eval-system/services/go-node/internal/course"
 	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/deadline"
+	"digital-eval-system/services/go-node/internal/eligibility"
 	"digital-eval-system/services/go-node/internal/storage"
+	"digital-eval-system/services/go-node/internal/valuation"
 )
 
 // Service handles authority operations
 type Service struct {
-	db    *db.PostgresDB
-	store storage.Storage
-	rand  *rand.Rand
+	db        *db.PostgresDB
+	store     storage.Storage
+	courses   *course.Service
+	valuation *valuation.Service
+	deadlines *deadline.Service
+	conflicts *conflict.Service
+	elig      *eligibility.Service
+	rand      *rand.Rand
 }
 
 // NewService constructs authority service
-func NewService(pg *db.PostgresDB, store storage.Storage) *Service {
+func NewService(pg *db.PostgresDB, store storage.Storage, courseSvc *course.Service, valuationSvc *valuation.Service, deadlineSvc *deadline.Service, conflictSvc *conflict.Service, eligibilitySvc *eligibility.Service) *Service {
 	return &Service{
-		db:    pg,
-		store: store,
-		rand:  rand.New(rand.NewSource(time.Now().UnixNano())),
+		db:        pg,
+		store:     store,
+		courses:   courseSvc,
+		valuation: valuationSvc,
+		deadlines: deadlineSvc,
+		conflicts: conflictSvc,
+		elig:      eligibilitySvc,
+		rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
-// ListPendingRequests returns pending requests
-func (s *Service) ListPending(ctx context.Context) ([]db.EvalRequestRow, error) {
-	return s.db.ListPendingRequests(ctx)
+// ListPending returns pending requests, each with whether the evaluator is
+// eligible for the course today.
+func (s *Service) ListPending(ctx context.Context) ([]PendingRequest, error) {
+	rows, err := s.db.ListPendingRequests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]PendingRequest, 0, len(rows))
+	for _, r := range rows {
+		d, err := s.elig.Check(ctx, r.EvaluatorID, r.CourseID)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, PendingRequest{EvalRequestRow: r, Eligibility: d})
+	}
+	return out, nil
 }
 
 // ListHistory returns approved/rejected requests
@@ -38,48 +65,29 @@ func (s *Service) ListHistory(ctx context.Context) ([]db.EvalRequestRow, error)
 	return s.db.ListRequestHistory(ctx)
 }
 
-// ApproveRequest approves and assigns random scripts
-func (s *Service) ApproveRequest(ctx context.Context, requestID int64, evaluatorID, courseID, semester, academicYear string, assignNum int) ([]string, error) {
-	// find candidate scripts from BoltDB store where CourseID & Semester match and not yet assigned (we'll use store.Iterator)
-
-	var eligible []string
-
-	err := s.store.ForEachBlock(func(blk *block.Block) {
-		for _, tx := range blk.Transactions {
-			if tx.CourseID == courseID && tx.Semester == semester {
-				eligible = append(eligible, tx.ScriptID)
-			}
-		}
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	// shuffle and pick assignNum
-	s.rand.Shuffle(len(eligible), func(i, j int) { eligible[i], eligible[j] = eligible[j], eligible[i] })
+// ApproveRequest approves a request and assigns assignNum uploaded,
+// unassigned scripts to the evaluator, due dueDays from now (0 = the
+// deadline policy's default). The report explains any shortfall.
+func (s *Service) ApproveRequest(ctx context.Context, requestID int64, evaluatorID, courseID, semester, academicYear string, assignNum, dueDays int) (*AssignmentReport, error) {
 	if assignNum <= 0 {
 		assignNum = 5
 	}
-	n := assignNum
-	if n > len(eligible) {
-		n = len(eligible)
+	// the registration may have lapsed since the request was made
+	if err := s.elig.Require(ctx, evaluatorID, courseID); err != nil {
+		return nil, err
 	}
-	selected := eligible[:n]
-
-	assigned := []string{}
-	for _, sid := range selected {
-		_, err := s.db.CreateAssignment(ctx, sid, evaluatorID, courseID, semester, academicYear, 0)
-		if err != nil {
-			logrus.Warnf("failed to create assignment for script %s: %v", sid, err)
-			continue
-		}
-		assigned = append(assigned, sid)
+	rep, err := s.AssignScripts(ctx, evaluatorID, courseID, semester, academicYear, assignNum, dueDays)
+	if err != nil {
+		return nil, err
+	}
+	if rep.Shortfall > 0 {
+		logrus.Warnf("request %d: %s", requestID, rep.Reason)
 	}
 	// mark request approved
 	if err := s.db.UpdateRequestStatus(ctx, requestID, "approved"); err != nil {
 		logrus.Warnf("failed to update request status: %v", err)
 	}
-	return assigned, nil
+	return rep, nil
 }
 
 // RejectRequest marks request rejected
@@ -0,0 +1,336 @@
+package authority
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"digital-eval-system/services/go-node/internal/block"
+	"digital-eval-system/services/go-node/internal/db"
+)
+
+// DefaultWorkloadWindow is the number of recent days throughput is measured
+// over when the request does not say.
+const DefaultWorkloadWindow = 7
+
+// WorkloadFilter scopes a workload report; empty fields match anything.
+type WorkloadFilter struct {
+	Semester     string
+	AcademicYear string
+	CourseID     string
+	WindowDays   int
+}
+
+// EvaluatorWorkload is one evaluator's load and pace.
+type EvaluatorWorkload struct {
+	EvaluatorID       string     `json:"evaluator_id"`
+	Courses           []string   `json:"courses"`
+	Assigned          int        `json:"assigned"`
+	InProgress        int        `json:"in_progress"`
+	Evaluated         int        `json:"evaluated"`
+	Revoked           int        `json:"revoked"`
+	Overdue           int        `json:"overdue"`                        // open and past their due date
+	AvgHoursPerScript *float64   `json:"avg_hours_per_script,omitempty"` // assignment to evaluation
+	EvaluatedInWindow int        `json:"evaluated_in_window"`
+	PerDay            float64    `json:"per_day"`
+	LastEvaluatedAt   *time.Time `json:"last_evaluated_at,omitempty"`
+	ProjectedClearAt  *time.Time `json:"projected_clear_at,omitempty"` // open scripts done at the recent pace
+}
+
+// CourseWorkload is the progress of one course and semester. Work is counted
+// in script rounds: a script under double valuation is two rounds, three when
+// its valuations disagree. A course is complete only when every uploaded
+// script has a final evaluation.
+type CourseWorkload struct {
+	CourseID          string     `json:"course_id"`
+	Semester          string     `json:"semester"`
+	Uploaded          int        `json:"uploaded"`
+	Finalized         int        `json:"finalized"`     // scripts with a final evaluation
+	ThirdPending      int        `json:"third_pending"` // valued twice, awaiting the third valuation
+	CompletionPercent float64    `json:"completion_percent"`
+	Rounds            int        `json:"rounds"`       // valuations per script under the course policy
+	RoundsTotal       int        `json:"rounds_total"` // including the third valuations needed so far
+	RoundsOpen        int        `json:"rounds_open"`
+	RoundsEvaluated   int        `json:"rounds_evaluated"`
+	Unassigned        int        `json:"unassigned"` // rounds nobody holds yet
+	Evaluators        int        `json:"evaluators"` // with an open or evaluated round
+	AvgHoursPerScript *float64   `json:"avg_hours_per_script,omitempty"`
+	EvaluatedInWindow int        `json:"evaluated_in_window"`
+	PerDay            float64    `json:"per_day"`
+	ProjectedAt       *time.Time `json:"projected_completion_at,omitempty"`
+	Projection        string     `json:"projection,omitempty"` // why there is no projected date
+}
+
+// WorkloadReport is the evaluator workload and throughput dashboard.
+type WorkloadReport struct {
+	GeneratedAt  time.Time           `json:"generated_at"`
+	Semester     string              `json:"semester,omitempty"`
+	AcademicYear string              `json:"academic_year,omitempty"`
+	CourseID     string              `json:"course_id,omitempty"`
+	WindowDays   int                 `json:"window_days"`
+	Evaluators   []EvaluatorWorkload `json:"evaluators"`
+	Courses      []CourseWorkload    `json:"courses"`
+}
+
+// tally accumulates the timing of a group of assignments.
+type tally struct {
+	first     time.Time // earliest assignment
+	open      int
+	evaluated int
+	inWindow  int
+	hours     float64 // summed assignment-to-evaluation time
+	timed     int
+	last      *time.Time
+}
+
+func (t *tally) add(a *db.WorkloadRow, since time.Time) {
+	if t.first.IsZero() || a.AssignedAt.Before(t.first) {
+		t.first = a.AssignedAt
+	}
+	switch a.Status {
+	case "assigned", "in_progress":
+		t.open++
+	case "evaluated":
+		t.evaluated++
+		if e := a.EvaluatedAt; e != nil {
+			if !e.Before(since) {
+				t.inWindow++
+			}
+			if e.After(a.AssignedAt) {
+				t.hours += e.Sub(a.AssignedAt).Hours()
+				t.timed++
+			}
+			if t.last == nil || e.After(*t.last) {
+				t.last = e
+			}
+		}
+	}
+}
+
+func (t *tally) avgHours() *float64 {
+	if t.timed == 0 {
+		return nil
+	}
+	v := round2(t.hours / float64(t.timed))
+	return &v
+}
+
+// perDay is the recent evaluation rate. A group that started inside the
+// window is measured from its first assignment, at least a day, so a cycle's
+// first week is not diluted by days before it began.
+func (t *tally) perDay(now time.Time, window int) float64 {
+	if t.inWindow == 0 {
+		return 0
+	}
+	days := float64(window)
+	if since := now.Sub(t.first).Hours() / 24; since < days {
+		days = math.Max(since, 1)
+	}
+	return float64(t.inWindow) / days
+}
+
+// project returns when remaining rounds are done at the given rate.
+func project(now time.Time, remaining int, perDay float64) *time.Time {
+	if remaining <= 0 || perDay <= 0 {
+		return nil
+	}
+	at := now.Add(time.Duration(float64(remaining) / perDay * 24 * float64(time.Hour))).Truncate(time.Hour)
+	return &at
+}
+
+func round2(v float64) float64 { return math.Round(v*100) / 100 }
+
+// Workload aggregates assignments and evaluations into per-evaluator load and
+// per-course completion, with completion dates projected from the pace of the
+// last WindowDays days.
+func (s *Service) Workload(ctx context.Context, f WorkloadFilter) (*WorkloadReport, error) {
+	if f.WindowDays <= 0 {
+		f.WindowDays = DefaultWorkloadWindow
+	}
+	f.CourseID = strings.ToUpper(strings.TrimSpace(f.CourseID))
+	now := time.Now().UTC()
+	since := now.AddDate(0, 0, -f.WindowDays)
+
+	rows, err := s.db.ListWorkload(ctx, f.Semester, f.AcademicYear, f.CourseID)
+	if err != nil {
+		return nil, fmt.Errorf("load assignments: %w", err)
+	}
+	finalized, err := s.db.CountEvaluatedScripts(ctx, f.Semester, f.AcademicYear, f.CourseID)
+	if err != nil {
+		return nil, fmt.Errorf("load evaluations: %w", err)
+	}
+	thirdPending, err := s.db.CountPendingThirdValuations(ctx, f.Semester, f.AcademicYear, f.CourseID)
+	if err != nil {
+		return nil, fmt.Errorf("load valuations: %w", err)
+	}
+	uploaded, err := s.countUploads(f)
+	if err != nil {
+		return nil, fmt.Errorf("read chain: %w", err)
+	}
+
+	evaluators := map[string]*EvaluatorWorkload{}
+	evalTally := map[string]*tally{}
+	courseTally := map[string]*tally{}
+	courseEvaluators := map[string]map[string]bool{}
+	thirdDone := map[string]int{} // third valuations already evaluated
+	for i := range rows {
+		a := &rows[i]
+		key := strings.ToUpper(a.CourseID) + "|" + a.Semester
+
+		ew := evaluators[a.EvaluatorID]
+		if ew == nil {
+			ew = &EvaluatorWorkload{EvaluatorID: a.EvaluatorID}
+			evaluators[a.EvaluatorID] = ew
+			evalTally[a.EvaluatorID] = &tally{}
+		}
+		switch a.Status {
+		case "assigned":
+			ew.Assigned++
+		case "in_progress":
+			ew.InProgress++
+		case "evaluated":
+			ew.Evaluated++
+		case "revoked":
+			ew.Revoked++
+		}
+		if (a.Status == "assigned" || a.Status == "in_progress") && a.DueAt != nil && a.DueAt.Before(now) {
+			ew.Overdue++
+		}
+		if !containsFold(ew.Courses, a.CourseID) {
+			ew.Courses = append(ew.Courses, a.CourseID)
+		}
+		evalTally[a.EvaluatorID].add(a, since)
+
+		if courseTally[key] == nil {
+			courseTally[key] = &tally{}
+			courseEvaluators[key] = map[string]bool{}
+		}
+		courseTally[key].add(a, since)
+		if a.Round == 3 && a.Status == "evaluated" {
+			thirdDone[key]++
+		}
+		if a.Status != "revoked" {
+			courseEvaluators[key][a.EvaluatorID] = true
+		}
+	}
+
+	rep := &WorkloadReport{
+		GeneratedAt:  now,
+		Semester:     f.Semester,
+		AcademicYear: f.AcademicYear,
+		CourseID:     f.CourseID,
+		WindowDays:   f.WindowDays,
+		Evaluators:   []EvaluatorWorkload{},
+		Courses:      []CourseWorkload{},
+	}
+	for id, ew := range evaluators {
+		t := evalTally[id]
+		ew.AvgHoursPerScript = t.avgHours()
+		ew.EvaluatedInWindow = t.inWindow
+		ew.PerDay = round2(t.perDay(now, f.WindowDays))
+		ew.LastEvaluatedAt = t.last
+		ew.ProjectedClearAt = project(now, t.open, t.perDay(now, f.WindowDays))
+		sort.Strings(ew.Courses)
+		rep.Evaluators = append(rep.Evaluators, *ew)
+	}
+	sort.Slice(rep.Evaluators, func(i, j int) bool { return rep.Evaluators[i].EvaluatorID < rep.Evaluators[j].EvaluatorID })
+
+	keys := map[string]bool{}
+	for k := range uploaded {
+		keys[k] = true
+	}
+	for k := range courseTally {
+		keys[k] = true
+	}
+	for k := range thirdPending {
+		keys[k] = true
+	}
+	for key := range keys {
+		courseID, semester, _ := strings.Cut(key, "|")
+		t := courseTally[key]
+		if t == nil {
+			t = &tally{}
+		}
+		cw := CourseWorkload{
+			CourseID:          courseID,
+			Semester:          semester,
+			Uploaded:          uploaded[key],
+			Finalized:         finalized[key],
+			ThirdPending:      thirdPending[key],
+			Rounds:            s.valuation.Resolve(ctx, courseID, semester).Rounds(),
+			RoundsOpen:        t.open,
+			RoundsEvaluated:   t.evaluated,
+			Evaluators:        len(courseEvaluators[key]),
+			AvgHoursPerScript: t.avgHours(),
+			EvaluatedInWindow: t.inWindow,
+		}
+		cw.RoundsTotal = cw.Uploaded*cw.Rounds + cw.ThirdPending + thirdDone[key]
+		if u := cw.RoundsTotal - cw.RoundsOpen - cw.RoundsEvaluated; u > 0 {
+			cw.Unassigned = u
+		}
+		if cw.Uploaded > 0 {
+			cw.CompletionPercent = round2(math.Min(100, float64(cw.Finalized)*100/float64(cw.Uploaded)))
+		}
+		rate := t.perDay(now, f.WindowDays)
+		cw.PerDay = round2(rate)
+		remaining := cw.RoundsTotal - cw.RoundsEvaluated
+		switch {
+		case cw.Uploaded == 0:
+			cw.Projection = "no scripts uploaded"
+		case cw.Finalized >= cw.Uploaded:
+			cw.Projection = "complete"
+		case remaining <= 0:
+			cw.Projection = fmt.Sprintf("%d script(s) valued, awaiting their final result", cw.Uploaded-cw.Finalized)
+		case rate == 0:
+			cw.Projection = fmt.Sprintf("no evaluations in the last %d days", f.WindowDays)
+		default:
+			cw.ProjectedAt = project(now, remaining, rate)
+		}
+		rep.Courses = append(rep.Courses, cw)
+	}
+	sort.Slice(rep.Courses, func(i, j int) bool {
+		if rep.Courses[i].CourseID != rep.Courses[j].CourseID {
+			return rep.Courses[i].CourseID < rep.Courses[j].CourseID
+		}
+		return rep.Courses[i].Semester < rep.Courses[j].Semester
+	})
+	return rep, nil
+}
+
+// countUploads counts the uploaded scripts on the chain per course and
+// semester, keyed "COURSE|semester". Uploads without an academic year are
+// kept when filtering by one.
+func (s *Service) countUploads(f WorkloadFilter) (map[string]int, error) {
+	out := map[string]int{}
+	seen := map[string]bool{}
+	err := s.store.ForEachBlock(func(blk *block.Block) {
+		for i := range blk.Transactions {
+			tx := &blk.Transactions[i]
+			if !isUpload(tx) || seen[tx.ScriptID] {
+				continue
+			}
+			courseID := strings.ToUpper(strings.TrimSpace(tx.CourseID))
+			semester := strings.TrimSpace(tx.Semester)
+			year := strings.TrimSpace(tx.AcademicYear)
+			if (f.CourseID != "" && courseID != f.CourseID) || (f.Semester != "" && semester != f.Semester) ||
+				(f.AcademicYear != "" && year != "" && year != f.AcademicYear) {
+				continue
+			}
+			seen[tx.ScriptID] = true
+			out[courseID+"|"+semester]++
+		}
+	})
+	return out, err
+}
+
+func containsFold(list []string, v string) bool {
+	for _, s := range list {
+		if strings.EqualFold(s, v) {
+			return true
+		}
+	}
+	return false
+}
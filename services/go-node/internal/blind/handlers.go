@@ -0,0 +1,55 @@
+package blind
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler exposes the authority's view of the dummy number mapping.
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GET /api/v1/authority/blind/masks?course_id=&semester=
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	rows, err := h.svc.List(r.Context(), q.Get("course_id"), q.Get("semester"))
+	if err != nil {
+		http.Error(w, "failed to load dummy numbers", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"enabled": h.svc.Enabled(), "masks": rows}, http.StatusOK)
+}
+
+// GET /api/v1/authority/blind/resolve/{dummy_number}
+// Names the student only after the results have been released.
+func (h *Handler) Resolve(w http.ResponseWriter, r *http.Request) {
+	res, err := h.svc.Lookup(r.Context(), mux.Vars(r)["dummy_number"])
+	if err != nil {
+		http.Error(w, "failed to resolve dummy number", http.StatusInternalServerError)
+		return
+	}
+	if res == nil {
+		http.Error(w, "dummy number not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, res, http.StatusOK)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func RegisterBlindRoutes(r *mux.Router, svc *Service) {
+	h := NewHandler(svc)
+	r.HandleFunc("/authority/blind/masks", h.List).Methods("GET")
+	r.HandleFunc("/authority/blind/resolve/{dummy_number}", h.Resolve).Methods("GET")
+}
@@ -0,0 +1,68 @@
+package blind
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Policy switches blind evaluation on and tunes how script PDFs are masked.
+type Policy struct {
+	Enabled        bool    `yaml:"enabled" json:"enabled"`
+	HeaderFraction float64 `yaml:"header_fraction" json:"header_fraction"` // share of page 1 redacted; 0 = extractor default
+}
+
+// Validate checks the policy.
+func (p Policy) Validate() error {
+	if p.HeaderFraction < 0 || p.HeaderFraction > 1 {
+		return fmt.Errorf("header_fraction must be between 0 and 1")
+	}
+	return nil
+}
+
+// dummyPrefix marks dummy numbers; script ids are lower-case UUIDs and never
+// start with it.
+const dummyPrefix = "DN"
+
+// IsDummy reports whether ref looks like a dummy number rather than a script id.
+func IsDummy(ref string) bool {
+	ref = strings.ToUpper(strings.TrimSpace(ref))
+	if len(ref) != len(dummyPrefix)+8 || !strings.HasPrefix(ref, dummyPrefix) {
+		return false
+	}
+	for _, c := range ref[len(dummyPrefix):] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// newDummy draws a random dummy number such as DN04819377.
+func newDummy() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(100000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%08d", dummyPrefix, n.Int64()), nil
+}
+
+// visibleFields are the script metadata evaluators may see in blind mode;
+// everything else (USN, institute, names, free-form extras) is dropped.
+var visibleFields = []string{"CourseID", "CourseName", "Semester", "AcademicYear", "Date"}
+
+// Strip returns the metadata an evaluator may see: the visible fields, the
+// dummy number and the masked PDF CID (empty when no masked copy exists yet,
+// never the original).
+func Strip(meta map[string]string, dummyNumber, maskedCID string) map[string]string {
+	out := map[string]string{}
+	for _, k := range visibleFields {
+		if v, ok := meta[k]; ok {
+			out[k] = v
+		}
+	}
+	out["dummy_number"] = dummyNumber
+	out["pdf_cid"] = maskedCID
+	return out
+}
@@ -0,0 +1,152 @@
+package blind
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/pybridge"
+)
+
+// Service maps scripts to dummy numbers and produces masked copies of their
+// PDFs. The mapping is sealed until the results are released.
+type Service struct {
+	pg     *db.PostgresDB
+	py     *pybridge.Client // extractor, which also masks PDFs
+	policy Policy
+}
+
+func NewService(pg *db.PostgresDB, py *pybridge.Client, policy Policy) *Service {
+	return &Service{pg: pg, py: py, policy: policy}
+}
+
+// Enabled reports whether evaluators mark blind.
+func (s *Service) Enabled() bool {
+	return s != nil && s.policy.Enabled
+}
+
+// Ensure returns a script's mask, drawing a dummy number on first use.
+func (s *Service) Ensure(ctx context.Context, scriptID, courseID, semester string) (*db.ScriptMaskRow, error) {
+	if m, err := s.pg.GetScriptMask(ctx, scriptID); err != nil || m != nil {
+		return m, err
+	}
+	for attempt := 0; attempt < 5; attempt++ {
+		dummy, err := newDummy()
+		if err != nil {
+			return nil, err
+		}
+		m, err := s.pg.InsertScriptMask(ctx, scriptID, dummy, courseID, semester)
+		if err != nil {
+			return nil, fmt.Errorf("create dummy number: %w", err)
+		}
+		if m != nil {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("could not draw a free dummy number for script %s", scriptID)
+}
+
+// Resolve turns a dummy number into its script id. Anything else is taken to
+// be a script id already and returned unchanged.
+func (s *Service) Resolve(ctx context.Context, ref string) (string, error) {
+	if s == nil || !IsDummy(ref) {
+		return ref, nil
+	}
+	m, err := s.pg.GetScriptMaskByDummy(ctx, strings.ToUpper(strings.TrimSpace(ref)))
+	if err != nil {
+		return "", err
+	}
+	if m == nil {
+		return "", fmt.Errorf("unknown dummy number %s", ref)
+	}
+	return m.ScriptID, nil
+}
+
+// Stamp returns the dummy number printed on the masked copy evaluators are
+// given of a script, "" when blind mode is off or no masked copy exists.
+func (s *Service) Stamp(ctx context.Context, scriptID string) (string, error) {
+	if !s.Enabled() {
+		return "", nil
+	}
+	m, err := s.pg.GetScriptMask(ctx, scriptID)
+	if err != nil || m == nil || m.MaskedCID == "" {
+		return "", err
+	}
+	return m.DummyNumber, nil
+}
+
+// Label is how a script is shown to evaluators: its dummy number in blind
+// mode, otherwise its script id.
+func (s *Service) Label(ctx context.Context, scriptID, courseID, semester string) (string, error) {
+	if !s.Enabled() {
+		return scriptID, nil
+	}
+	m, err := s.Ensure(ctx, scriptID, courseID, semester)
+	if err != nil {
+		return "", err
+	}
+	return m.DummyNumber, nil
+}
+
+// Mask strips the student identifiers from a script's metadata and swaps its
+// PDF for the masked copy, creating that copy on first use. If the copy
+// cannot be made the PDF is withheld rather than shown unmasked.
+func (s *Service) Mask(ctx context.Context, scriptID string, meta map[string]string) (map[string]string, error) {
+	m, err := s.Ensure(ctx, scriptID, meta["CourseID"], meta["Semester"])
+	if err != nil {
+		return nil, err
+	}
+	if m.MaskedCID == "" && meta["pdf_cid"] != "" {
+		resp, err := s.py.MaskPDF(ctx, pybridge.MaskRequest{CID: meta["pdf_cid"], Label: m.DummyNumber, HeaderFraction: s.policy.HeaderFraction})
+		if err != nil {
+			logrus.Warnf("failed to mask script %s: %v", m.DummyNumber, err)
+		} else {
+			m.MaskedCID = resp.MaskedCID
+			if err := s.pg.SetMaskedCID(ctx, scriptID, resp.MaskedCID); err != nil {
+				logrus.Warnf("failed to record masked copy of script %s: %v", m.DummyNumber, err)
+			}
+		}
+	}
+	out := Strip(meta, m.DummyNumber, m.MaskedCID)
+	if m.MaskedCID == "" && meta["pdf_cid"] != "" {
+		out["pdf_error"] = "masked copy of the script is not available yet"
+	}
+	return out, nil
+}
+
+// List returns the dummy number mapping for the authority.
+func (s *Service) List(ctx context.Context, courseID, semester string) ([]db.ScriptMaskRow, error) {
+	return s.pg.ListScriptMasks(ctx, courseID, semester)
+}
+
+// Resolution is a dummy number resolved for the authority. The student is
+// named only once the script's results have been released.
+type Resolution struct {
+	db.ScriptMaskRow
+	Revealed   bool   `json:"revealed"`
+	StudentUSN string `json:"student_usn,omitempty"`
+}
+
+// Lookup resolves a dummy number; nil, nil if it is unknown.
+func (s *Service) Lookup(ctx context.Context, dummyNumber string) (*Resolution, error) {
+	m, err := s.pg.GetScriptMaskByDummy(ctx, strings.ToUpper(strings.TrimSpace(dummyNumber)))
+	if err != nil || m == nil {
+		return nil, err
+	}
+	res := &Resolution{ScriptMaskRow: *m, Revealed: m.RevealedAt != nil}
+	if res.Revealed {
+		if res.StudentUSN, err = s.pg.GetScriptStudentUSN(ctx, m.ScriptID); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Reveal unseals the mapping of every script released for a semester and
+// academic year.
+func (s *Service) Reveal(ctx context.Context, semester, academicYear, by string) (int64, error) {
+	return s.pg.RevealScriptMasks(ctx, semester, academicYear, by)
+}
@@ -1,5 +1,14 @@
 package block
 
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+)
+
 // transaction.go kept minimal for now. Transaction struct defined in block.go.
 // Add helpers here if needed (validation, canonicalization).
 
@@ -13,3 +22,42 @@ func ValidateTransaction(tx *Transaction) bool {
 	}
 	return true
 }
+
+// txBytes produces canonical transaction bytes for signing (excluding ExtraSig).
+func (tx *Transaction) txBytes() ([]byte, error) {
+	c := *tx
+	c.ExtraSig = nil
+	return json.Marshal(c)
+}
+
+// SignRSA computes an RSA-SHA256 signature over the transaction and sets ExtraSig.
+// Sign before building the block so the merkle root covers the signature.
+func (tx *Transaction) SignRSA(priv *rsa.PrivateKey) error {
+	if priv == nil {
+		return errors.New("private key nil")
+	}
+	tb, err := tx.txBytes()
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(tb)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA256, hash[:])
+	if err != nil {
+		return err
+	}
+	tx.ExtraSig = sig
+	return nil
+}
+
+// VerifyRSA checks ExtraSig against the transaction contents.
+func (tx *Transaction) VerifyRSA(pub *rsa.PublicKey) error {
+	if pub == nil {
+		return errors.New("public key nil")
+	}
+	tb, err := tx.txBytes()
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(tb)
+	return rsa.VerifyPKCS1v15(pub, crypto.SHA256, hash[:], tx.ExtraSig)
+}
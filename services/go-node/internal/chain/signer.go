@@ -0,0 +1,53 @@
+package chain
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Signer is the node's block signing key. ID is the identity recorded in
+// Header.SignerID of the blocks it signs, so ValidateChain can find the
+// public key again through PublicKey.
+type Signer struct {
+	ID  string
+	Key *rsa.PrivateKey
+}
+
+// LoadSigner reads a PEM RSA private key (PKCS#8 or PKCS#1) for signer id.
+func LoadSigner(id, privPath string) (*Signer, error) {
+	if id == "" {
+		return nil, errors.New("signer id is required")
+	}
+	data, err := os.ReadFile(privPath)
+	if err != nil {
+		return nil, err
+	}
+	blk, _ := pem.Decode(data)
+	if blk == nil {
+		return nil, fmt.Errorf("%s: no PEM block", privPath)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(blk.Bytes); err == nil {
+		return &Signer{ID: id, Key: key}, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(blk.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", privPath, err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: not an RSA key", privPath)
+	}
+	return &Signer{ID: id, Key: key}, nil
+}
+
+// PublicKey is a ValidateChain key loader for the blocks this signer signed.
+func (s *Signer) PublicKey(signerID string) (*rsa.PublicKey, error) {
+	if signerID != s.ID {
+		return nil, fmt.Errorf("unknown signer %q", signerID)
+	}
+	return &s.Key.PublicKey, nil
+}
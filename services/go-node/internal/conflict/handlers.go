@@ -0,0 +1,151 @@
+package conflict
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler exposes evaluator profile and conflict of interest endpoints.
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GET /api/v1/admin/evaluator-profiles
+func (h *Handler) ListProfiles(w http.ResponseWriter, r *http.Request) {
+	profiles, err := h.svc.ListProfiles(r.Context())
+	if err != nil {
+		http.Error(w, "failed to load evaluator profiles", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, profiles, http.StatusOK)
+}
+
+// GET /api/v1/admin/evaluator-profiles/{evaluator_id}
+func (h *Handler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	p, err := h.svc.GetProfile(r.Context(), mux.Vars(r)["evaluator_id"])
+	if err != nil {
+		http.Error(w, "failed to load evaluator profile", http.StatusInternalServerError)
+		return
+	}
+	if p == nil {
+		http.Error(w, "evaluator profile not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, p, http.StatusOK)
+}
+
+// POST /api/v1/admin/evaluator-profiles
+// Creates the profile or replaces the evaluator's existing one.
+func (h *Handler) SaveProfile(w http.ResponseWriter, r *http.Request) {
+	var p Profile
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.SaveProfile(r.Context(), &p); err != nil {
+		http.Error(w, "save failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, p, http.StatusOK)
+}
+
+// GET /api/v1/admin/coi-rules?evaluator_id=
+func (h *Handler) ListRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.svc.ListRules(r.Context(), r.URL.Query().Get("evaluator_id"))
+	if err != nil {
+		http.Error(w, "failed to load rules", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rules, http.StatusOK)
+}
+
+// POST /api/v1/admin/coi-rules
+// Body: a Rule. id 0 creates it, otherwise the rule is updated.
+func (h *Handler) SaveRule(w http.ResponseWriter, r *http.Request) {
+	var rule Rule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if _, err := h.svc.SaveRule(r.Context(), &rule); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "rule not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, rule, http.StatusOK)
+}
+
+// DELETE /api/v1/admin/coi-rules/{id}
+func (h *Handler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.DeleteRule(r.Context(), id); err != nil {
+		http.Error(w, "delete failed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "deleted"}, http.StatusOK)
+}
+
+// GET /api/v1/authority/exclusions?evaluator_id=&script_id=&course_id=
+// Lists scripts withheld from evaluators and the rule that withheld them.
+func (h *Handler) Exclusions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	rows, err := h.svc.Exclusions(r.Context(), q.Get("evaluator_id"), q.Get("script_id"), q.Get("course_id"))
+	if err != nil {
+		http.Error(w, "failed to load exclusions", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rows, http.StatusOK)
+}
+
+// GET /api/v1/authority/exclusions/check?evaluator_id=&usn=
+// Previews whether a USN would be withheld from an evaluator.
+func (h *Handler) Check(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("evaluator_id") == "" || q.Get("usn") == "" {
+		http.Error(w, "evaluator_id and usn are required", http.StatusBadRequest)
+		return
+	}
+	chk, err := h.svc.Checker(r.Context(), q.Get("evaluator_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if e := chk.Check("", q.Get("usn")); e != nil {
+		writeJSON(w, map[string]interface{}{"excluded": true, "rule_id": e.RuleID, "reason": e.Reason}, http.StatusOK)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"excluded": false}, http.StatusOK)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func RegisterConflictRoutes(r *mux.Router, svc *Service) {
+	h := NewHandler(svc)
+	r.HandleFunc("/admin/evaluator-profiles", h.ListProfiles).Methods("GET")
+	r.HandleFunc("/admin/evaluator-profiles", h.SaveProfile).Methods("POST")
+	r.HandleFunc("/admin/evaluator-profiles/{evaluator_id}", h.GetProfile).Methods("GET")
+	r.HandleFunc("/admin/coi-rules", h.ListRules).Methods("GET")
+	r.HandleFunc("/admin/coi-rules", h.SaveRule).Methods("POST")
+	r.HandleFunc("/admin/coi-rules/{id}", h.DeleteRule).Methods("DELETE")
+	r.HandleFunc("/authority/exclusions", h.Exclusions).Methods("GET")
+	r.HandleFunc("/authority/exclusions/check", h.Check).Methods("GET")
+}
@@ -0,0 +1,123 @@
+package conflict
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule kinds.
+const (
+	// KindCollegePrefix excludes scripts whose USN starts with the
+	// evaluator's college code.
+	KindCollegePrefix = "college_prefix"
+	// KindBlockUSN excludes one USN, or every USN starting with the value
+	// when it ends in "*".
+	KindBlockUSN = "block_usn"
+)
+
+// Profile is an evaluator's affiliation.
+type Profile struct {
+	EvaluatorID string `json:"evaluator_id"`
+	Name        string `json:"name"`
+	Affiliation string `json:"affiliation"`
+	CollegeCode string `json:"college_code"` // USN prefix of the college, e.g. 1BI
+	Department  string `json:"department"`
+}
+
+// Normalize trims the profile and upper-cases the college code.
+func (p *Profile) Normalize() {
+	p.EvaluatorID = strings.TrimSpace(p.EvaluatorID)
+	p.Name = strings.TrimSpace(p.Name)
+	p.Affiliation = strings.TrimSpace(p.Affiliation)
+	p.CollegeCode = strings.ToUpper(strings.TrimSpace(p.CollegeCode))
+	p.Department = strings.TrimSpace(p.Department)
+}
+
+// Rule is one exclusion rule. An empty EvaluatorID applies it to everyone.
+type Rule struct {
+	ID          int64  `json:"id"`
+	Kind        string `json:"kind"`
+	EvaluatorID string `json:"evaluator_id"`
+	Value       string `json:"value"`
+	Reason      string `json:"reason"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// Normalize trims the rule and upper-cases a blocked USN.
+func (r *Rule) Normalize() {
+	r.Kind = strings.ToLower(strings.TrimSpace(r.Kind))
+	r.EvaluatorID = strings.TrimSpace(r.EvaluatorID)
+	r.Value = strings.ToUpper(strings.TrimSpace(r.Value))
+	r.Reason = strings.TrimSpace(r.Reason)
+}
+
+// Validate checks the rule is one the checker understands.
+func (r *Rule) Validate() error {
+	switch r.Kind {
+	case KindCollegePrefix:
+	case KindBlockUSN:
+		if r.Value == "" || r.Value == "*" {
+			return fmt.Errorf("block_usn rule needs a USN or USN prefix")
+		}
+		if r.EvaluatorID == "" && strings.HasSuffix(r.Value, "*") {
+			// a global prefix block would withhold a whole batch from everyone
+			return fmt.Errorf("a USN prefix block must name an evaluator")
+		}
+	default:
+		return fmt.Errorf("unknown rule kind %q", r.Kind)
+	}
+	return nil
+}
+
+// Exclusion records why a script was withheld from an evaluator.
+type Exclusion struct {
+	EvaluatorID string `json:"evaluator_id"`
+	ScriptID    string `json:"script_id"`
+	RuleID      int64  `json:"rule_id"`
+	Reason      string `json:"reason"`
+}
+
+// Checker applies the rules of one evaluator.
+type Checker struct {
+	EvaluatorID string
+	Profile     *Profile // nil when the evaluator has no profile
+	Rules       []Rule
+}
+
+// Check returns the first rule that keeps the script with the given USN
+// away from the evaluator, or nil if none does. A script without a USN is
+// never excluded: there is nothing to compare.
+func (c *Checker) Check(scriptID, usn string) *Exclusion {
+	usn = strings.ToUpper(strings.TrimSpace(usn))
+	if c == nil || usn == "" {
+		return nil
+	}
+	for _, r := range c.Rules {
+		if !r.Enabled || (r.EvaluatorID != "" && r.EvaluatorID != c.EvaluatorID) {
+			continue
+		}
+		var why string
+		switch r.Kind {
+		case KindCollegePrefix:
+			if c.Profile != nil && c.Profile.CollegeCode != "" && strings.HasPrefix(usn, c.Profile.CollegeCode) {
+				why = fmt.Sprintf("USN %s carries the evaluator's college code %s", usn, c.Profile.CollegeCode)
+			}
+		case KindBlockUSN:
+			if prefix, ok := strings.CutSuffix(r.Value, "*"); ok {
+				if strings.HasPrefix(usn, prefix) {
+					why = fmt.Sprintf("USN %s matches blocked prefix %s", usn, prefix)
+				}
+			} else if usn == r.Value {
+				why = fmt.Sprintf("USN %s is blocked for the evaluator", usn)
+			}
+		}
+		if why == "" {
+			continue
+		}
+		if r.Reason != "" {
+			why = r.Reason + ": " + why
+		}
+		return &Exclusion{EvaluatorID: c.EvaluatorID, ScriptID: scriptID, RuleID: r.ID, Reason: why}
+	}
+	return nil
+}
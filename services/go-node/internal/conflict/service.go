@@ -0,0 +1,205 @@
+package conflict
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"digital-eval-system/services/go-node/internal/block"
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/storage"
+)
+
+// Service stores evaluator profiles and conflict of interest rules and
+// decides which scripts an evaluator must not receive.
+type Service struct {
+	pg    *db.PostgresDB
+	store storage.Storage
+}
+
+func NewService(pg *db.PostgresDB, store storage.Storage) *Service {
+	return &Service{pg: pg, store: store}
+}
+
+// ListProfiles returns every evaluator profile.
+func (s *Service) ListProfiles(ctx context.Context) ([]Profile, error) {
+	rows, err := s.pg.ListEvaluatorProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Profile, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, fromProfileRow(&r))
+	}
+	return out, nil
+}
+
+// GetProfile returns an evaluator's profile; nil, nil if none.
+func (s *Service) GetProfile(ctx context.Context, evaluatorID string) (*Profile, error) {
+	row, err := s.pg.GetEvaluatorProfile(ctx, evaluatorID)
+	if err != nil || row == nil {
+		return nil, err
+	}
+	p := fromProfileRow(row)
+	return &p, nil
+}
+
+// SaveProfile creates or replaces an evaluator's profile.
+func (s *Service) SaveProfile(ctx context.Context, p *Profile) error {
+	p.Normalize()
+	if p.EvaluatorID == "" {
+		return fmt.Errorf("evaluator_id is required")
+	}
+	return s.pg.UpsertEvaluatorProfile(ctx, db.EvaluatorProfileRow{
+		EvaluatorID: p.EvaluatorID,
+		Name:        p.Name,
+		Affiliation: p.Affiliation,
+		CollegeCode: p.CollegeCode,
+		Department:  p.Department,
+	})
+}
+
+// ListRules returns every rule, or with an evaluator those that apply to them.
+func (s *Service) ListRules(ctx context.Context, evaluatorID string) ([]Rule, error) {
+	rows, err := s.pg.ListCOIRules(ctx, evaluatorID, false)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Rule, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, fromRuleRow(r))
+	}
+	return out, nil
+}
+
+// SaveRule validates and stores a rule; ID 0 creates it.
+func (s *Service) SaveRule(ctx context.Context, r *Rule) (int64, error) {
+	r.Normalize()
+	if err := r.Validate(); err != nil {
+		return 0, err
+	}
+	id, err := s.pg.SaveCOIRule(ctx, db.COIRuleRow{
+		ID:          r.ID,
+		Kind:        r.Kind,
+		EvaluatorID: r.EvaluatorID,
+		Value:       r.Value,
+		Reason:      r.Reason,
+		Enabled:     r.Enabled,
+	})
+	if err != nil {
+		return 0, err
+	}
+	r.ID = id
+	return id, nil
+}
+
+// DeleteRule removes a rule.
+func (s *Service) DeleteRule(ctx context.Context, id int64) error {
+	return s.pg.DeleteCOIRule(ctx, id)
+}
+
+// Checker loads the profile and enabled rules of an evaluator.
+func (s *Service) Checker(ctx context.Context, evaluatorID string) (*Checker, error) {
+	p, err := s.GetProfile(ctx, evaluatorID)
+	if err != nil {
+		return nil, fmt.Errorf("load evaluator profile: %w", err)
+	}
+	rows, err := s.pg.ListCOIRules(ctx, evaluatorID, true)
+	if err != nil {
+		return nil, fmt.Errorf("load conflict rules: %w", err)
+	}
+	c := &Checker{EvaluatorID: evaluatorID, Profile: p}
+	for _, r := range rows {
+		c.Rules = append(c.Rules, fromRuleRow(r))
+	}
+	return c, nil
+}
+
+// Record stores exclusions made while assigning scripts of a course.
+// Failures are logged: the assignment itself already respected the rules.
+func (s *Service) Record(ctx context.Context, courseID, semester string, excl []Exclusion) {
+	if len(excl) == 0 {
+		return
+	}
+	rows := make([]db.AssignmentExclusionRow, 0, len(excl))
+	for _, e := range excl {
+		row := db.AssignmentExclusionRow{EvaluatorID: e.EvaluatorID, ScriptID: e.ScriptID, CourseID: courseID, Semester: semester, Reason: e.Reason}
+		if e.RuleID > 0 {
+			row.RuleID = sql.NullInt64{Int64: e.RuleID, Valid: true}
+		}
+		rows = append(rows, row)
+	}
+	if err := s.pg.RecordAssignmentExclusions(ctx, rows); err != nil {
+		logrus.Warnf("failed to record %d assignment exclusions for %s: %v", len(rows), courseID, err)
+	}
+}
+
+// Exclusions returns recorded exclusions filtered by evaluator, script and
+// course (empty = any).
+func (s *Service) Exclusions(ctx context.Context, evaluatorID, scriptID, courseID string) ([]db.AssignmentExclusionRow, error) {
+	return s.pg.ListAssignmentExclusions(ctx, evaluatorID, scriptID, courseID)
+}
+
+// ScriptUSN returns the USN recorded with a script's upload, "" if unknown.
+func (s *Service) ScriptUSN(scriptID string) string {
+	usn := ""
+	_ = s.store.ForEachBlock(func(blk *block.Block) {
+		for _, t := range blk.Transactions {
+			if usn != "" || !strings.EqualFold(strings.TrimSpace(t.ScriptID), strings.TrimSpace(scriptID)) {
+				continue
+			}
+			if t.USN != "" {
+				usn = t.USN
+			} else if v := t.Meta["USN"]; v != "" {
+				usn = v
+			}
+		}
+	})
+	return usn
+}
+
+// Conflict checks one evaluator against one script; nil if they may mark it.
+func (s *Service) Conflict(ctx context.Context, evaluatorID, scriptID string) (*Exclusion, error) {
+	chk, err := s.Checker(ctx, evaluatorID)
+	if err != nil {
+		return nil, err
+	}
+	return chk.Check(scriptID, s.ScriptUSN(scriptID)), nil
+}
+
+// PickEvaluator returns the least loaded evaluator of the course who has
+// never held the script and has no conflict with it, recording everyone
+// skipped for a conflict. Returns "" when nobody qualifies.
+func (s *Service) PickEvaluator(ctx context.Context, courseID, semester, scriptID string) (string, error) {
+	usn := s.ScriptUSN(scriptID)
+	var skipped []string
+	var excl []Exclusion
+	defer func() { s.Record(ctx, courseID, semester, excl) }()
+	for {
+		id, err := s.pg.PickValuationEvaluator(ctx, courseID, semester, scriptID, skipped)
+		if err != nil || id == "" {
+			return "", err
+		}
+		chk, err := s.Checker(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		e := chk.Check(scriptID, usn)
+		if e == nil {
+			return id, nil
+		}
+		excl = append(excl, *e)
+		skipped = append(skipped, id)
+	}
+}
+
+func fromProfileRow(r *db.EvaluatorProfileRow) Profile {
+	return Profile{EvaluatorID: r.EvaluatorID, Name: r.Name, Affiliation: r.Affiliation, CollegeCode: r.CollegeCode, Department: r.Department}
+}
+
+func fromRuleRow(r db.COIRuleRow) Rule {
+	return Rule{ID: r.ID, Kind: r.Kind, EvaluatorID: r.EvaluatorID, Value: r.Value, Reason: r.Reason, Enabled: r.Enabled}
+}
@@ -0,0 +1,116 @@
+package course
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler exposes course catalog administration endpoints.
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GET /api/v1/admin/courses?semester=...&department=...&regulation=...
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	courses, err := h.svc.List(r.Context(), q.Get("semester"), q.Get("department"), q.Get("regulation"))
+	if err != nil {
+		http.Error(w, "failed to load courses", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, courses, http.StatusOK)
+}
+
+// GET /api/v1/admin/courses/{code}
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	c, err := h.svc.Get(r.Context(), mux.Vars(r)["code"])
+	if err != nil {
+		http.Error(w, "failed to load course", http.StatusInternalServerError)
+		return
+	}
+	if c == nil {
+		http.Error(w, "course not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, c, http.StatusOK)
+}
+
+// POST /api/v1/admin/courses
+// Creates the course or replaces the one stored under the same course_code.
+func (h *Handler) Save(w http.ResponseWriter, r *http.Request) {
+	var c Course
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	c.Normalize()
+	if err := c.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	id, err := h.svc.Save(r.Context(), &c)
+	if err != nil {
+		http.Error(w, "save failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"id": id, "course_code": c.CourseCode}, http.StatusOK)
+}
+
+// DELETE /api/v1/admin/courses/{code}
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.Delete(r.Context(), mux.Vars(r)["code"]); err != nil {
+		http.Error(w, "delete failed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "deleted"}, http.StatusOK)
+}
+
+// POST /api/v1/admin/courses/import (multipart, field "file")
+// CSV header: course_code,course_name,credits,semester[,department,regulation,exam_pattern]
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	const maxUploadSize = 5 << 20
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		http.Error(w, "invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	report, err := h.svc.ImportCSV(r.Context(), file)
+	if err != nil {
+		http.Error(w, "import failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(report.Errors) > 0 {
+		writeJSON(w, report, http.StatusUnprocessableEntity)
+		return
+	}
+	writeJSON(w, report, http.StatusOK)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func RegisterCourseRoutes(r *mux.Router, svc *Service) {
+	h := NewHandler(svc)
+	r.HandleFunc("/admin/courses", h.List).Methods("GET")
+	r.HandleFunc("/admin/courses", h.Save).Methods("POST")
+	r.HandleFunc("/admin/courses/import", h.Import).Methods("POST")
+	r.HandleFunc("/admin/courses/{code}", h.Get).Methods("GET")
+	r.HandleFunc("/admin/courses/{code}", h.Delete).Methods("DELETE")
+}
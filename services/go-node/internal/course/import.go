@@ -0,0 +1,109 @@
+package course
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"digital-eval-system/services/go-node/internal/db"
+)
+
+// ImportReport summarises a CSV import.
+type ImportReport struct {
+	Imported int      `json:"imported"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// csvColumns are the accepted header names; course_code, course_name, credits
+// and semester are required, the rest optional.
+var csvColumns = []string{"course_code", "course_name", "credits", "semester", "department", "regulation", "exam_pattern", "course_type"}
+
+// ImportCSV reads a catalog CSV with a header row and upserts every course.
+// The import is all-or-nothing: if any row is invalid nothing is written and
+// the report lists each problem with its line number.
+func (s *Service) ImportCSV(ctx context.Context, r io.Reader) (*ImportReport, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	idx := map[string]int{}
+	for i, h := range header {
+		idx[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+	for _, col := range csvColumns[:4] {
+		if _, ok := idx[col]; !ok {
+			return nil, fmt.Errorf("missing column %q", col)
+		}
+	}
+
+	field := func(rec []string, col string) string {
+		i, ok := idx[col]
+		if !ok || i >= len(rec) {
+			return ""
+		}
+		return rec[i]
+	}
+
+	report := &ImportReport{}
+	var courses []Course
+	seen := map[string]int{}
+	line := 1
+	for {
+		rec, err := cr.Read()
+		line++
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+
+		credits, err := strconv.Atoi(strings.TrimSpace(field(rec, "credits")))
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("line %d: invalid credits %q", line, field(rec, "credits")))
+			continue
+		}
+		c := Course{
+			CourseCode:  field(rec, "course_code"),
+			CourseName:  field(rec, "course_name"),
+			Credits:     credits,
+			Semester:    field(rec, "semester"),
+			Department:  field(rec, "department"),
+			Regulation:  field(rec, "regulation"),
+			ExamPattern: field(rec, "exam_pattern"),
+			CourseType:  field(rec, "course_type"),
+		}
+		c.Normalize()
+		if err := c.Validate(); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		if prev, dup := seen[c.CourseCode]; dup {
+			report.Errors = append(report.Errors, fmt.Sprintf("line %d: duplicate course_code %s (first on line %d)", line, c.CourseCode, prev))
+			continue
+		}
+		seen[c.CourseCode] = line
+		courses = append(courses, c)
+	}
+
+	if len(report.Errors) > 0 {
+		return report, nil
+	}
+
+	rows := make([]db.CourseRow, 0, len(courses))
+	for _, c := range courses {
+		rows = append(rows, toRow(c))
+	}
+	if err := s.pg.UpsertCourses(ctx, rows); err != nil {
+		return nil, err
+	}
+	report.Imported = len(courses)
+	return report, nil
+}
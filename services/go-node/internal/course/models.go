@@ -0,0 +1,67 @@
+package course
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Course is one entry of the authoritative course catalog.
+type Course struct {
+	CourseCode  string `json:"course_code"`
+	CourseName  string `json:"course_name"`
+	Credits     int    `json:"credits"`
+	Semester    string `json:"semester"`
+	Department  string `json:"department"`
+	Regulation  string `json:"regulation"`
+	ExamPattern string `json:"exam_pattern"`
+	CourseType  string `json:"course_type"` // theory, lab, project...; remuneration rates key on it
+}
+
+// Normalize trims fields and upper-cases the course code so lookups match the
+// extractor's CourseID format.
+func (c *Course) Normalize() {
+	c.CourseCode = strings.ToUpper(strings.TrimSpace(c.CourseCode))
+	c.CourseName = strings.TrimSpace(c.CourseName)
+	c.Semester = strings.TrimSpace(c.Semester)
+	c.Department = strings.TrimSpace(c.Department)
+	c.Regulation = strings.TrimSpace(c.Regulation)
+	c.ExamPattern = strings.TrimSpace(c.ExamPattern)
+	c.CourseType = strings.ToLower(strings.TrimSpace(c.CourseType))
+}
+
+// Validate checks required fields.
+func (c *Course) Validate() error {
+	if c.CourseCode == "" {
+		return fmt.Errorf("course_code is required")
+	}
+	if c.CourseName == "" {
+		return fmt.Errorf("course_name is required")
+	}
+	if c.Semester == "" {
+		return fmt.Errorf("semester is required")
+	}
+	if c.Credits < 0 {
+		return fmt.Errorf("credits must be >= 0")
+	}
+	return nil
+}
+
+// Catalog is a lookup of courses keyed by course code.
+type Catalog map[string]Course
+
+// Get returns the course for a code (case-insensitive).
+func (c Catalog) Get(code string) (Course, bool) {
+	co, ok := c[strings.ToUpper(strings.TrimSpace(code))]
+	return co, ok
+}
+
+// Regulation returns the regulation shared by the catalogued courses among
+// codes, or "" when none are catalogued.
+func (c Catalog) Regulation(codes ...string) string {
+	for _, code := range codes {
+		if co, ok := c.Get(code); ok && co.Regulation != "" {
+			return co.Regulation
+		}
+	}
+	return ""
+}
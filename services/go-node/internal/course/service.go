@@ -0,0 +1,100 @@
+package course
+
+import (
+	"context"
+	"strings"
+
+	"digital-eval-system/services/go-node/internal/db"
+)
+
+// Service manages the course catalog stored in Postgres.
+type Service struct {
+	pg *db.PostgresDB
+}
+
+func NewService(pg *db.PostgresDB) *Service {
+	return &Service{pg: pg}
+}
+
+// List returns catalog entries filtered by semester, department and regulation.
+func (s *Service) List(ctx context.Context, semester, department, regulation string) ([]Course, error) {
+	rows, err := s.pg.ListCourses(ctx, semester, department, regulation)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Course, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, fromRow(r))
+	}
+	return out, nil
+}
+
+// Get returns a course by code; nil, nil if it is not catalogued.
+func (s *Service) Get(ctx context.Context, code string) (*Course, error) {
+	r, err := s.pg.GetCourse(ctx, strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil || r == nil {
+		return nil, err
+	}
+	c := fromRow(*r)
+	return &c, nil
+}
+
+// Catalog loads the catalog entries for the given course codes.
+func (s *Service) Catalog(ctx context.Context, codes []string) (Catalog, error) {
+	cat := Catalog{}
+	if s == nil || len(codes) == 0 {
+		return cat, nil
+	}
+	norm := make([]string, 0, len(codes))
+	for _, c := range codes {
+		norm = append(norm, strings.ToUpper(strings.TrimSpace(c)))
+	}
+	rows, err := s.pg.GetCoursesByCode(ctx, norm)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		cat[r.CourseCode] = fromRow(r)
+	}
+	return cat, nil
+}
+
+// Save validates and creates or updates a course.
+func (s *Service) Save(ctx context.Context, c *Course) (int64, error) {
+	c.Normalize()
+	if err := c.Validate(); err != nil {
+		return 0, err
+	}
+	return s.pg.UpsertCourse(ctx, toRow(*c))
+}
+
+// Delete removes a course from the catalog.
+func (s *Service) Delete(ctx context.Context, code string) error {
+	return s.pg.DeleteCourse(ctx, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+func fromRow(r db.CourseRow) Course {
+	return Course{
+		CourseCode:  r.CourseCode,
+		CourseName:  r.CourseName,
+		Credits:     r.Credits,
+		Semester:    r.Semester,
+		Department:  r.Department,
+		Regulation:  r.Regulation,
+		ExamPattern: r.ExamPattern,
+		CourseType:  r.CourseType,
+	}
+}
+
+func toRow(c Course) db.CourseRow {
+	return db.CourseRow{
+		CourseCode:  c.CourseCode,
+		CourseName:  c.CourseName,
+		Credits:     c.Credits,
+		Semester:    c.Semester,
+		Department:  c.Department,
+		Regulation:  c.Regulation,
+		ExamPattern: c.ExamPattern,
+		CourseType:  c.CourseType,
+	}
+}
@@ -2,7 +2,6 @@ package db
 
 import (
 	"context"
-	"database/sql"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -67,23 +66,3 @@ func (p *PostgresDB) EvaluationExistsForStudentSemesterCourse(ctx context.Contex
 	}
 	return cnt > 0, nil
 }
-
-// GetAssignedScriptByScriptID fetches an assigned_scripts row by script_id
-// returns nil, nil if not found.
-func (p *PostgresDB) GetAssignedScriptByScriptID(ctx context.Context, scriptID string) (*AssignedScriptRow, error) {
-	// Assumes there is a struct AssignedScriptRow in your queries or models package.
-	var row AssignedScriptRow
-	err := p.DB.QueryRowContext(ctx, `
-        SELECT id, script_id, evaluator_id, course_id, semester, academic_year, course_credits, assigned_at, status
-        FROM assigned_scripts
-        WHERE script_id = $1
-        LIMIT 1
-    `, scriptID).Scan(&row.ID, &row.ScriptID, &row.Evaluator, &row.CourseID, &row.Semester, &row.AcademicYear, &row.CourseCredits, &row.AssignedAt, &row.Status)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, err
-	}
-	return &row, nil
-}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Annotated script helpers
+
+type AnnotatedScriptRow struct {
+	ID           int64     `json:"id"`
+	AssignmentID int64     `json:"assignment_id"`
+	ScriptID     string    `json:"script_id"`
+	EvaluatorID  string    `json:"evaluator_id"`
+	CID          string    `json:"cid"`
+	SHA256       string    `json:"sha256"`
+	FileName     string    `json:"file_name,omitempty"`
+	UploadedAt   time.Time `json:"uploaded_at"`
+}
+
+const annotatedScriptColumns = `id, assignment_id, script_id, evaluator_id, cid, sha256, file_name, uploaded_at`
+
+func scanAnnotatedScript(sc interface{ Scan(...interface{}) error }) (*AnnotatedScriptRow, error) {
+	var r AnnotatedScriptRow
+	if err := sc.Scan(&r.ID, &r.AssignmentID, &r.ScriptID, &r.EvaluatorID, &r.CID, &r.SHA256, &r.FileName, &r.UploadedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &r, nil
+}
+
+// UpsertAnnotatedScript records the annotated copy uploaded for an
+// assignment, replacing an earlier upload.
+func (p *PostgresDB) UpsertAnnotatedScript(ctx context.Context, r AnnotatedScriptRow) (*AnnotatedScriptRow, error) {
+	return scanAnnotatedScript(p.DB.QueryRowContext(ctx, `
+		INSERT INTO annotated_scripts (assignment_id, script_id, evaluator_id, cid, sha256, file_name, uploaded_at)
+		VALUES ($1,$2,$3,$4,$5,$6, now())
+		ON CONFLICT (assignment_id) DO UPDATE
+		SET cid = EXCLUDED.cid, sha256 = EXCLUDED.sha256, file_name = EXCLUDED.file_name, uploaded_at = now()
+		RETURNING `+annotatedScriptColumns,
+		r.AssignmentID, r.ScriptID, r.EvaluatorID, r.CID, r.SHA256, r.FileName))
+}
+
+// GetAnnotatedScript returns the annotated copy of an assignment; nil, nil if none.
+func (p *PostgresDB) GetAnnotatedScript(ctx context.Context, assignmentID int64) (*AnnotatedScriptRow, error) {
+	return scanAnnotatedScript(p.DB.QueryRowContext(ctx, `SELECT `+annotatedScriptColumns+` FROM annotated_scripts WHERE assignment_id = $1`, assignmentID))
+}
+
+// ListAnnotatedScripts returns every annotated copy of a script, oldest first.
+func (p *PostgresDB) ListAnnotatedScripts(ctx context.Context, scriptID string) ([]AnnotatedScriptRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `SELECT `+annotatedScriptColumns+` FROM annotated_scripts WHERE script_id = $1 ORDER BY uploaded_at ASC`, scriptID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []AnnotatedScriptRow
+	for rows.Next() {
+		r, err := scanAnnotatedScript(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *r)
+	}
+	return out, rows.Err()
+}
+
+// SetEvaluationAnnotatedScript records the annotated copy an evaluation was
+// submitted with on the evaluation row of a block.
+func (p *PostgresDB) SetEvaluationAnnotatedScript(ctx context.Context, blockHash, cid, sha256 string) error {
+	_, err := p.DB.ExecContext(ctx, `UPDATE evaluations SET annotated_cid = $2, annotated_sha256 = $3 WHERE block_hash = $1`, blockHash, cid, sha256)
+	return err
+}
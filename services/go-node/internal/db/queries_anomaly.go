@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Anomaly report helpers
+
+// FetchEvaluatorMarks returns the marks each evaluator gave in a semester.
+// A double-valued script yields one row per valuation, under the evaluator
+// who carried it out, rather than its aggregated evaluation row. Otherwise
+// the evaluation is read as first recorded (its first revision), so marks
+// changed by moderation or revaluation are not held against the evaluator.
+func (p *PostgresDB) FetchEvaluatorMarks(ctx context.Context, semester, academicYear string) ([]EvaluationRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT e.id, e.script_id, e.student_usn, e.course_id, e.semester, e.academic_year, e.course_credits,
+		       COALESCE(v.evaluator_id, o.evaluator_id, e.evaluator_id), COALESCE(v.marks, o.marks, e.marks),
+		       COALESCE(v.total_marks, o.total_marks, e.total_marks), COALESCE(o.result, e.result), COALESCE(v.created_at, e.created_at)
+		FROM evaluations e
+		LEFT JOIN LATERAL (
+			SELECT r.evaluator_id, r.marks, r.total_marks, r.result FROM evaluation_revisions r
+			WHERE r.evaluation_id = e.id ORDER BY r.revision ASC LIMIT 1
+		) o ON true
+		LEFT JOIN valuations v ON v.script_id = e.script_id
+		WHERE e.semester = $1 AND e.academic_year = $2
+		ORDER BY e.created_at ASC, v.valuation_round ASC`, semester, academicYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []EvaluationRow
+	for rows.Next() {
+		var r EvaluationRow
+		if err := rows.Scan(&r.ID, &r.ScriptID, &r.StudentUSN, &r.CourseID, &r.Semester, &r.AcademicYear, &r.CourseCredits, &r.Evaluator, &r.Marks, &r.TotalMarks, &r.Result, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type EvaluationTimingRow struct {
+	ScriptID    string
+	EvaluatorID string
+	CourseID    string
+	AssignedAt  time.Time
+	EvaluatedAt time.Time
+}
+
+// FetchEvaluationTimings pairs each evaluation of a semester (each valuation
+// of a double-valued script) with the time the script was assigned to the
+// same evaluator.
+func (p *PostgresDB) FetchEvaluationTimings(ctx context.Context, semester, academicYear string) ([]EvaluationTimingRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT e.script_id, COALESCE(v.evaluator_id, o.evaluator_id, e.evaluator_id), e.course_id, a.assigned_at, COALESCE(v.created_at, e.created_at)
+		FROM evaluations e
+		LEFT JOIN LATERAL (
+			SELECT r.evaluator_id FROM evaluation_revisions r
+			WHERE r.evaluation_id = e.id ORDER BY r.revision ASC LIMIT 1
+		) o ON true
+		LEFT JOIN valuations v ON v.script_id = e.script_id
+		JOIN assigned_scripts a ON a.script_id = e.script_id AND a.evaluator_id = COALESCE(v.evaluator_id, o.evaluator_id, e.evaluator_id)
+		WHERE e.semester = $1 AND e.academic_year = $2`, semester, academicYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []EvaluationTimingRow
+	for rows.Next() {
+		var r EvaluationTimingRow
+		if err := rows.Scan(&r.ScriptID, &r.EvaluatorID, &r.CourseID, &r.AssignedAt, &r.EvaluatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type AnomalyReportRow struct {
+	ID           int64
+	Semester     string
+	AcademicYear string
+	GeneratedBy  string
+	GeneratedAt  time.Time
+	FlagCount    int
+	Report       json.RawMessage
+}
+
+// InsertAnomalyReport stores a generated report and returns its id.
+func (p *PostgresDB) InsertAnomalyReport(ctx context.Context, semester, academicYear, generatedBy string, flagCount int, report []byte) (int64, error) {
+	var id int64
+	err := p.DB.QueryRowContext(ctx, `
+		INSERT INTO anomaly_reports (semester, academic_year, generated_by, flag_count, report, generated_at)
+		VALUES ($1,$2,$3,$4,$5, now()) RETURNING id`, semester, academicYear, generatedBy, flagCount, report).Scan(&id)
+	return id, err
+}
+
+// ListAnomalyReports returns report headers (without the report body), newest first.
+func (p *PostgresDB) ListAnomalyReports(ctx context.Context, semester, academicYear string) ([]AnomalyReportRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT id, semester, academic_year, generated_by, generated_at, flag_count
+		FROM anomaly_reports
+		WHERE ($1 = '' OR semester = $1) AND ($2 = '' OR academic_year = $2)
+		ORDER BY generated_at DESC`, semester, academicYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []AnomalyReportRow
+	for rows.Next() {
+		var r AnomalyReportRow
+		if err := rows.Scan(&r.ID, &r.Semester, &r.AcademicYear, &r.GeneratedBy, &r.GeneratedAt, &r.FlagCount); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// GetAnomalyReport returns a stored report; nil, nil if not found.
+func (p *PostgresDB) GetAnomalyReport(ctx context.Context, id int64) (*AnomalyReportRow, error) {
+	var r AnomalyReportRow
+	err := p.DB.QueryRowContext(ctx, `
+		SELECT id, semester, academic_year, generated_by, generated_at, flag_count, report
+		FROM anomaly_reports WHERE id = $1`, id).
+		Scan(&r.ID, &r.Semester, &r.AcademicYear, &r.GeneratedBy, &r.GeneratedAt, &r.FlagCount, &r.Report)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &r, nil
+}
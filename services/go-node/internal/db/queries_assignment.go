@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+)
+
+// Assignment engine helpers
+
+// ListEvaluatedScripts returns the ids of scripts of a course and semester
+// that already have an evaluation.
+func (p *PostgresDB) ListEvaluatedScripts(ctx context.Context, courseID, semester string) (map[string]bool, error) {
+	rows, err := p.DB.QueryContext(ctx, `SELECT script_id FROM evaluations WHERE course_id = $1 AND semester = $2`, courseID, semester)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out[id] = true
+	}
+	return out, rows.Err()
+}
+
+type EvaluatorLoadRow struct {
+	EvaluatorID string `json:"evaluator_id"`
+	Open        int    `json:"open"` // assigned or in_progress, any course
+}
+
+// ListCourseEvaluators returns the evaluators approved for (or already
+// assigned to) a course and semester with their open assignment counts,
+// least loaded first.
+func (p *PostgresDB) ListCourseEvaluators(ctx context.Context, courseID, semester string) ([]EvaluatorLoadRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT c.evaluator_id, count(a.id)
+		FROM (
+			SELECT evaluator_id FROM evaluation_requests WHERE course_id = $1 AND semester = $2 AND status = 'approved'
+			UNION
+			SELECT evaluator_id FROM assigned_scripts WHERE course_id = $1 AND semester = $2
+		) c
+		LEFT JOIN assigned_scripts a ON a.evaluator_id = c.evaluator_id AND a.status IN ('assigned', 'in_progress')
+		GROUP BY c.evaluator_id
+		ORDER BY count(a.id) ASC, c.evaluator_id ASC`, courseID, semester)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []EvaluatorLoadRow
+	for rows.Next() {
+		var r EvaluatorLoadRow
+		if err := rows.Scan(&r.EvaluatorID, &r.Open); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// CountOpenAssignments returns how many open assignments an evaluator holds.
+func (p *PostgresDB) CountOpenAssignments(ctx context.Context, evaluatorID string) (int, error) {
+	var n int
+	err := p.DB.QueryRowContext(ctx, `SELECT count(*) FROM assigned_scripts WHERE evaluator_id = $1 AND status IN ('assigned', 'in_progress')`, evaluatorID).Scan(&n)
+	return n, err
+}
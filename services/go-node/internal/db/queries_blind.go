@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Blind evaluation helpers
+
+type ScriptMaskRow struct {
+	ScriptID    string     `json:"script_id"`
+	DummyNumber string     `json:"dummy_number"`
+	CourseID    string     `json:"course_id"`
+	Semester    string     `json:"semester"`
+	MaskedCID   string     `json:"masked_cid,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RevealedAt  *time.Time `json:"revealed_at,omitempty"`
+	RevealedBy  string     `json:"revealed_by,omitempty"`
+}
+
+const scriptMaskColumns = `script_id, dummy_number, course_id, semester, masked_cid, created_at, revealed_at, revealed_by`
+
+func scanScriptMask(sc interface{ Scan(...interface{}) error }) (*ScriptMaskRow, error) {
+	var r ScriptMaskRow
+	if err := sc.Scan(&r.ScriptID, &r.DummyNumber, &r.CourseID, &r.Semester, &r.MaskedCID, &r.CreatedAt, &r.RevealedAt, &r.RevealedBy); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &r, nil
+}
+
+// InsertScriptMask maps a script to a dummy number unless it already has
+// one. Returns the script's mask; nil, nil if the dummy number is taken by
+// another script (the caller draws a new one).
+func (p *PostgresDB) InsertScriptMask(ctx context.Context, scriptID, dummyNumber, courseID, semester string) (*ScriptMaskRow, error) {
+	if _, err := p.DB.ExecContext(ctx, `
+		INSERT INTO script_masks (script_id, dummy_number, course_id, semester, created_at)
+		VALUES ($1,$2,$3,$4, now())
+		ON CONFLICT DO NOTHING`, scriptID, dummyNumber, courseID, semester); err != nil {
+		return nil, err
+	}
+	return p.GetScriptMask(ctx, scriptID)
+}
+
+// GetScriptMask returns a script's mask; nil, nil if it has none.
+func (p *PostgresDB) GetScriptMask(ctx context.Context, scriptID string) (*ScriptMaskRow, error) {
+	return scanScriptMask(p.DB.QueryRowContext(ctx, `SELECT `+scriptMaskColumns+` FROM script_masks WHERE script_id = $1`, scriptID))
+}
+
+// GetScriptMaskByDummy returns the mask with a dummy number; nil, nil if none.
+func (p *PostgresDB) GetScriptMaskByDummy(ctx context.Context, dummyNumber string) (*ScriptMaskRow, error) {
+	return scanScriptMask(p.DB.QueryRowContext(ctx, `SELECT `+scriptMaskColumns+` FROM script_masks WHERE dummy_number = $1`, dummyNumber))
+}
+
+// SetMaskedCID records the IPFS CID of a script's masked copy.
+func (p *PostgresDB) SetMaskedCID(ctx context.Context, scriptID, cid string) error {
+	_, err := p.DB.ExecContext(ctx, `UPDATE script_masks SET masked_cid = $2 WHERE script_id = $1`, scriptID, cid)
+	return err
+}
+
+// ListScriptMasks returns masks filtered by course and semester (empty = any).
+func (p *PostgresDB) ListScriptMasks(ctx context.Context, courseID, semester string) ([]ScriptMaskRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT `+scriptMaskColumns+` FROM script_masks
+		WHERE ($1 = '' OR course_id = $1) AND ($2 = '' OR semester = $2)
+		ORDER BY course_id ASC, dummy_number ASC`, courseID, semester)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []ScriptMaskRow
+	for rows.Next() {
+		r, err := scanScriptMask(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *r)
+	}
+	return out, rows.Err()
+}
+
+// RevealScriptMasks marks the masks of every script evaluated in a semester
+// and academic year as revealed. Returns how many were revealed.
+func (p *PostgresDB) RevealScriptMasks(ctx context.Context, semester, academicYear, revealedBy string) (int64, error) {
+	res, err := p.DB.ExecContext(ctx, `
+		UPDATE script_masks m SET revealed_at = now(), revealed_by = $3
+		FROM evaluations e
+		WHERE e.script_id = m.script_id AND e.semester = $1 AND e.academic_year = $2 AND m.revealed_at IS NULL`,
+		semester, academicYear, revealedBy)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetScriptStudentUSN returns the student USN recorded with a script's
+// evaluation, "" if the script has none.
+func (p *PostgresDB) GetScriptStudentUSN(ctx context.Context, scriptID string) (string, error) {
+	var usn sql.NullString
+	err := p.DB.QueryRowContext(ctx, `SELECT student_usn FROM evaluations WHERE script_id = $1`, scriptID).Scan(&usn)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return usn.String, err
+}
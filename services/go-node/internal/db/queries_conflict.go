@@ -0,0 +1,195 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Evaluator profile and conflict of interest helpers
+
+type EvaluatorProfileRow struct {
+	EvaluatorID string
+	Name        string
+	Affiliation string
+	CollegeCode string
+	Department  string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+const evaluatorProfileColumns = `evaluator_id, name, affiliation, college_code, department, created_at, updated_at`
+
+func scanEvaluatorProfile(sc interface{ Scan(...interface{}) error }) (*EvaluatorProfileRow, error) {
+	var r EvaluatorProfileRow
+	if err := sc.Scan(&r.EvaluatorID, &r.Name, &r.Affiliation, &r.CollegeCode, &r.Department, &r.CreatedAt, &r.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ListEvaluatorProfiles returns every evaluator profile.
+func (p *PostgresDB) ListEvaluatorProfiles(ctx context.Context) ([]EvaluatorProfileRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `SELECT `+evaluatorProfileColumns+` FROM evaluator_profiles ORDER BY evaluator_id ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []EvaluatorProfileRow
+	for rows.Next() {
+		r, err := scanEvaluatorProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *r)
+	}
+	return out, rows.Err()
+}
+
+// GetEvaluatorProfile returns an evaluator's profile; nil, nil if none.
+func (p *PostgresDB) GetEvaluatorProfile(ctx context.Context, evaluatorID string) (*EvaluatorProfileRow, error) {
+	r, err := scanEvaluatorProfile(p.DB.QueryRowContext(ctx, `SELECT `+evaluatorProfileColumns+` FROM evaluator_profiles WHERE evaluator_id = $1`, evaluatorID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+// UpsertEvaluatorProfile creates or replaces an evaluator's profile.
+func (p *PostgresDB) UpsertEvaluatorProfile(ctx context.Context, r EvaluatorProfileRow) error {
+	_, err := p.DB.ExecContext(ctx, `
+		INSERT INTO evaluator_profiles (evaluator_id, name, affiliation, college_code, department, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5, now(), now())
+		ON CONFLICT (evaluator_id) DO UPDATE SET
+			name = EXCLUDED.name,
+			affiliation = EXCLUDED.affiliation,
+			college_code = EXCLUDED.college_code,
+			department = EXCLUDED.department,
+			updated_at = now()`,
+		r.EvaluatorID, r.Name, r.Affiliation, r.CollegeCode, r.Department)
+	return err
+}
+
+type COIRuleRow struct {
+	ID          int64
+	Kind        string
+	EvaluatorID string
+	Value       string
+	Reason      string
+	Enabled     bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+const coiRuleColumns = `id, kind, evaluator_id, value, reason, enabled, created_at, updated_at`
+
+// ListCOIRules returns rules that apply to an evaluator (global and their
+// own); with evaluatorID empty every rule. enabledOnly skips disabled rules.
+func (p *PostgresDB) ListCOIRules(ctx context.Context, evaluatorID string, enabledOnly bool) ([]COIRuleRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT `+coiRuleColumns+` FROM coi_rules
+		WHERE ($1 = '' OR evaluator_id = '' OR evaluator_id = $1) AND (NOT $2 OR enabled)
+		ORDER BY id ASC`, evaluatorID, enabledOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []COIRuleRow
+	for rows.Next() {
+		var r COIRuleRow
+		if err := rows.Scan(&r.ID, &r.Kind, &r.EvaluatorID, &r.Value, &r.Reason, &r.Enabled, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// SaveCOIRule inserts a rule (ID 0) or updates an existing one and returns its id.
+func (p *PostgresDB) SaveCOIRule(ctx context.Context, r COIRuleRow) (int64, error) {
+	if r.ID == 0 {
+		var id int64
+		err := p.DB.QueryRowContext(ctx, `
+			INSERT INTO coi_rules (kind, evaluator_id, value, reason, enabled, created_at, updated_at)
+			VALUES ($1,$2,$3,$4,$5, now(), now()) RETURNING id`,
+			r.Kind, r.EvaluatorID, r.Value, r.Reason, r.Enabled).Scan(&id)
+		return id, err
+	}
+	res, err := p.DB.ExecContext(ctx, `
+		UPDATE coi_rules SET kind = $2, evaluator_id = $3, value = $4, reason = $5, enabled = $6, updated_at = now()
+		WHERE id = $1`, r.ID, r.Kind, r.EvaluatorID, r.Value, r.Reason, r.Enabled)
+	if err != nil {
+		return 0, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return 0, sql.ErrNoRows
+	}
+	return r.ID, nil
+}
+
+// DeleteCOIRule removes a rule by id.
+func (p *PostgresDB) DeleteCOIRule(ctx context.Context, id int64) error {
+	_, err := p.DB.ExecContext(ctx, `DELETE FROM coi_rules WHERE id = $1`, id)
+	return err
+}
+
+type AssignmentExclusionRow struct {
+	EvaluatorID string        `json:"evaluator_id"`
+	ScriptID    string        `json:"script_id"`
+	CourseID    string        `json:"course_id"`
+	Semester    string        `json:"semester"`
+	RuleID      sql.NullInt64 `json:"-"`
+	Reason      string        `json:"reason"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// RecordAssignmentExclusions stores why scripts were withheld from
+// evaluators, keeping the latest reason per evaluator and script.
+func (p *PostgresDB) RecordAssignmentExclusions(ctx context.Context, rows []AssignmentExclusionRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, r := range rows {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO assignment_exclusions (evaluator_id, script_id, course_id, semester, rule_id, reason, created_at, updated_at)
+			VALUES ($1,$2,$3,$4,$5,$6, now(), now())
+			ON CONFLICT (evaluator_id, script_id) DO UPDATE SET
+				rule_id = EXCLUDED.rule_id,
+				reason = EXCLUDED.reason,
+				updated_at = now()`,
+			r.EvaluatorID, r.ScriptID, r.CourseID, r.Semester, r.RuleID, r.Reason); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListAssignmentExclusions returns recorded exclusions filtered by evaluator,
+// script and course (empty = any), newest first.
+func (p *PostgresDB) ListAssignmentExclusions(ctx context.Context, evaluatorID, scriptID, courseID string) ([]AssignmentExclusionRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT evaluator_id, script_id, course_id, semester, rule_id, reason, updated_at FROM assignment_exclusions
+		WHERE ($1 = '' OR evaluator_id = $1) AND ($2 = '' OR script_id = $2) AND ($3 = '' OR course_id = $3)
+		ORDER BY updated_at DESC LIMIT 1000`, evaluatorID, scriptID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []AssignmentExclusionRow
+	for rows.Next() {
+		var r AssignmentExclusionRow
+		if err := rows.Scan(&r.EvaluatorID, &r.ScriptID, &r.CourseID, &r.Semester, &r.RuleID, &r.Reason, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Course catalog helpers
+
+type CourseRow struct {
+	ID          int64
+	CourseCode  string
+	CourseName  string
+	Credits     int
+	Semester    string
+	Department  string
+	Regulation  string
+	ExamPattern string
+	CourseType  string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+const courseColumns = `id, course_code, course_name, credits, semester, department, regulation, exam_pattern, course_type, created_at, updated_at`
+
+func scanCourse(sc interface{ Scan(...interface{}) error }) (*CourseRow, error) {
+	var r CourseRow
+	if err := sc.Scan(&r.ID, &r.CourseCode, &r.CourseName, &r.Credits, &r.Semester, &r.Department, &r.Regulation, &r.ExamPattern, &r.CourseType, &r.CreatedAt, &r.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (p *PostgresDB) queryCourses(ctx context.Context, query string, args ...interface{}) ([]CourseRow, error) {
+	rows, err := p.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []CourseRow
+	for rows.Next() {
+		r, err := scanCourse(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *r)
+	}
+	return out, rows.Err()
+}
+
+// ListCourses returns catalog rows, optionally filtered by semester, department
+// and regulation (empty filter = any).
+func (p *PostgresDB) ListCourses(ctx context.Context, semester, department, regulation string) ([]CourseRow, error) {
+	return p.queryCourses(ctx, `
+		SELECT `+courseColumns+` FROM courses
+		WHERE ($1 = '' OR semester = $1) AND ($2 = '' OR department = $2) AND ($3 = '' OR regulation = $3)
+		ORDER BY semester ASC, course_code ASC`, semester, department, regulation)
+}
+
+// GetCourse returns a course by code; nil, nil if not found.
+func (p *PostgresDB) GetCourse(ctx context.Context, courseCode string) (*CourseRow, error) {
+	r, err := scanCourse(p.DB.QueryRowContext(ctx, `SELECT `+courseColumns+` FROM courses WHERE course_code = $1`, courseCode))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCoursesByCode returns the catalog rows for the given codes (missing codes are skipped).
+func (p *PostgresDB) GetCoursesByCode(ctx context.Context, courseCodes []string) ([]CourseRow, error) {
+	return p.queryCourses(ctx, `SELECT `+courseColumns+` FROM courses WHERE course_code = ANY($1)`, pq.Array(courseCodes))
+}
+
+const upsertCourseSQL = `
+INSERT INTO courses (course_code, course_name, credits, semester, department, regulation, exam_pattern, course_type, created_at, updated_at)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8, now(), now())
+ON CONFLICT (course_code) DO UPDATE SET
+	course_name = EXCLUDED.course_name,
+	credits = EXCLUDED.credits,
+	semester = EXCLUDED.semester,
+	department = EXCLUDED.department,
+	regulation = EXCLUDED.regulation,
+	exam_pattern = EXCLUDED.exam_pattern,
+	course_type = EXCLUDED.course_type,
+	updated_at = now()
+RETURNING id`
+
+// UpsertCourse inserts a course or updates the one with the same code and returns its id.
+func (p *PostgresDB) UpsertCourse(ctx context.Context, c CourseRow) (int64, error) {
+	var id int64
+	err := p.DB.QueryRowContext(ctx, upsertCourseSQL,
+		c.CourseCode, c.CourseName, c.Credits, c.Semester, c.Department, c.Regulation, c.ExamPattern, c.CourseType).Scan(&id)
+	return id, err
+}
+
+// UpsertCourses writes all rows in a single transaction; nothing is stored if any row fails.
+func (p *PostgresDB) UpsertCourses(ctx context.Context, rows []CourseRow) error {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, c := range rows {
+		var id int64
+		if err := tx.QueryRowContext(ctx, upsertCourseSQL,
+			c.CourseCode, c.CourseName, c.Credits, c.Semester, c.Department, c.Regulation, c.ExamPattern, c.CourseType).Scan(&id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteCourse removes a course by code.
+func (p *PostgresDB) DeleteCourse(ctx context.Context, courseCode string) error {
+	_, err := p.DB.ExecContext(ctx, `DELETE FROM courses WHERE course_code = $1`, courseCode)
+	return err
+}
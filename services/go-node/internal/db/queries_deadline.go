@@ -0,0 +1,150 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Assignment deadline helpers
+
+// GetAssignment returns an assignment by id; nil, nil if not found.
+func (p *PostgresDB) GetAssignment(ctx context.Context, id int64) (*AssignedScriptRow, error) {
+	r, err := scanAssignedScript(p.DB.QueryRowContext(ctx, `SELECT `+assignedScriptColumns+` FROM assigned_scripts WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+// ListAssignments returns assignments filtered by status, course and
+// evaluator (empty filter = any); with overdueOnly only open assignments past
+// their due date. Ordered by due date, undated last.
+func (p *PostgresDB) ListAssignments(ctx context.Context, status, courseID, evaluatorID string, overdueOnly bool) ([]AssignedScriptRow, error) {
+	return p.queryAssignedScripts(ctx, `
+		SELECT `+assignedScriptColumns+` FROM assigned_scripts
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR course_id = $2) AND ($3 = '' OR evaluator_id = $3)
+		  AND (NOT $4 OR (status IN ('assigned', 'in_progress') AND due_at < now()))
+		ORDER BY due_at ASC NULLS LAST, id ASC`, status, courseID, evaluatorID, overdueOnly)
+}
+
+// ListOpenAssignmentsDueBefore returns open assignments with a deadline at or before t.
+func (p *PostgresDB) ListOpenAssignmentsDueBefore(ctx context.Context, t time.Time) ([]AssignedScriptRow, error) {
+	return p.queryAssignedScripts(ctx, `
+		SELECT `+assignedScriptColumns+` FROM assigned_scripts
+		WHERE status IN ('assigned', 'in_progress') AND due_at IS NOT NULL AND due_at <= $1
+		ORDER BY due_at ASC`, t)
+}
+
+// SetAssignmentDue changes the deadline of an open assignment. Returns
+// sql.ErrNoRows if the assignment is not open.
+func (p *PostgresDB) SetAssignmentDue(ctx context.Context, id int64, dueAt time.Time) error {
+	res, err := p.DB.ExecContext(ctx, `
+		UPDATE assigned_scripts SET due_at = $2
+		WHERE id = $1 AND status IN ('assigned', 'in_progress')`, id, dueAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// RevokeAssignment revokes an open assignment and drops its draft, so the
+// script returns to the pool. Returns sql.ErrNoRows if it is not open.
+func (p *PostgresDB) RevokeAssignment(ctx context.Context, id int64, reason string) error {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	res, err := tx.ExecContext(ctx, `
+		UPDATE assigned_scripts SET status = 'revoked', revoked_at = now(), revoke_reason = $2
+		WHERE id = $1 AND status IN ('assigned', 'in_progress')`, id, reason)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM evaluation_drafts WHERE assignment_id = $1`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListUnreplacedRevocations returns the latest revoked assignment of every
+// script round that has no live assignment and no evaluation yet: scripts
+// waiting in the pool for reassignment.
+func (p *PostgresDB) ListUnreplacedRevocations(ctx context.Context) ([]AssignedScriptRow, error) {
+	return p.queryAssignedScripts(ctx, `
+		SELECT `+assignedScriptColumns+` FROM (
+			SELECT DISTINCT ON (script_id, valuation_round) * FROM assigned_scripts
+			WHERE status = 'revoked'
+			ORDER BY script_id, valuation_round, revoked_at DESC NULLS LAST
+		) a
+		WHERE NOT EXISTS (SELECT 1 FROM assigned_scripts l
+			WHERE l.script_id = a.script_id AND l.valuation_round = a.valuation_round AND l.status <> 'revoked')
+		  AND NOT EXISTS (SELECT 1 FROM evaluations e WHERE e.script_id = a.script_id)
+		ORDER BY revoked_at ASC`)
+}
+
+// Reassign creates a new assignment of a revoked assignment's script round
+// for another evaluator.
+func (p *PostgresDB) Reassign(ctx context.Context, revoked AssignedScriptRow, evaluatorID string, dueAt *time.Time) (int64, error) {
+	var id int64
+	err := p.DB.QueryRowContext(ctx,
+		`INSERT INTO assigned_scripts (script_id, evaluator_id, course_id, semester, academic_year, course_credits, valuation_round, due_at, reassigned_from, assigned_at, status)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9, now(), 'assigned') RETURNING id`,
+		revoked.ScriptID, evaluatorID, revoked.CourseID, revoked.Semester, revoked.AcademicYear, revoked.CourseCredits, revoked.ValuationRound, dueAt, revoked.ID).Scan(&id)
+	return id, err
+}
+
+type AssignmentEventRow struct {
+	ID           int64     `json:"id"`
+	AssignmentID int64     `json:"assignment_id"`
+	EvaluatorID  string    `json:"evaluator_id"`
+	Kind         string    `json:"kind"`
+	Detail       string    `json:"detail,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// InsertAssignmentEvent records an event. Reminders are recorded once per
+// assignment and offset; returns false if the reminder was already recorded.
+func (p *PostgresDB) InsertAssignmentEvent(ctx context.Context, e AssignmentEventRow) (bool, error) {
+	res, err := p.DB.ExecContext(ctx, `
+		INSERT INTO assignment_events (assignment_id, evaluator_id, kind, detail, created_at)
+		VALUES ($1,$2,$3,$4, now())
+		ON CONFLICT DO NOTHING`, e.AssignmentID, e.EvaluatorID, e.Kind, e.Detail)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// ListAssignmentEvents returns events filtered by assignment and evaluator
+// (0 / empty = any), newest first, at most limit rows.
+func (p *PostgresDB) ListAssignmentEvents(ctx context.Context, assignmentID int64, evaluatorID string, limit int) ([]AssignmentEventRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT id, assignment_id, evaluator_id, kind, detail, created_at FROM assignment_events
+		WHERE ($1 = 0 OR assignment_id = $1) AND ($2 = '' OR evaluator_id = $2)
+		ORDER BY created_at DESC, id DESC LIMIT $3`, assignmentID, evaluatorID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []AssignmentEventRow
+	for rows.Next() {
+		var e AssignmentEventRow
+		if err := rows.Scan(&e.ID, &e.AssignmentID, &e.EvaluatorID, &e.Kind, &e.Detail, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
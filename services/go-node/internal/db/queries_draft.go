@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Evaluation draft helpers
+
+// ErrDraftConflict is returned when a draft was saved elsewhere since the
+// version the caller last read.
+var ErrDraftConflict = errors.New("draft was saved from another session")
+
+type DraftRow struct {
+	AssignmentID int64           `json:"assignment_id"`
+	ScriptID     string          `json:"script_id"`
+	EvaluatorID  string          `json:"evaluator_id"`
+	CourseID     string          `json:"course_id"`
+	Semester     string          `json:"semester"`
+	AcademicYear string          `json:"academic_year"`
+	Payload      json.RawMessage `json:"payload"`
+	Version      int             `json:"version"`
+	CreatedAt    time.Time       `json:"created_at"`
+	SavedAt      time.Time       `json:"saved_at"`
+}
+
+const draftColumns = `d.assignment_id, d.script_id, d.evaluator_id, a.course_id, a.semester, a.academic_year, d.payload, d.version, d.created_at, d.saved_at`
+
+func scanDraft(sc interface{ Scan(...interface{}) error }) (*DraftRow, error) {
+	var r DraftRow
+	err := sc.Scan(&r.AssignmentID, &r.ScriptID, &r.EvaluatorID, &r.CourseID, &r.Semester, &r.AcademicYear, &r.Payload, &r.Version, &r.CreatedAt, &r.SavedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &r, nil
+}
+
+// SaveDraft stores the draft of an assignment and moves the assignment to
+// in_progress. With baseVersion > 0 the save only succeeds if the stored
+// draft is still at that version (ErrDraftConflict otherwise). Returns the
+// new version.
+func (p *PostgresDB) SaveDraft(ctx context.Context, a AssignedScriptRow, payload []byte, baseVersion int) (int, error) {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var version int
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO evaluation_drafts (assignment_id, script_id, evaluator_id, payload, version, created_at, saved_at)
+		VALUES ($1,$2,$3,$4, 1, now(), now())
+		ON CONFLICT (assignment_id) DO UPDATE SET
+			payload = EXCLUDED.payload,
+			version = evaluation_drafts.version + 1,
+			saved_at = now()
+		WHERE $5 = 0 OR evaluation_drafts.version = $5
+		RETURNING version`,
+		a.ID, a.ScriptID, a.Evaluator, payload, baseVersion).Scan(&version)
+	if err == sql.ErrNoRows {
+		return 0, ErrDraftConflict
+	}
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE assigned_scripts SET status = 'in_progress' WHERE id = $1 AND status = 'assigned'`, a.ID); err != nil {
+		return 0, err
+	}
+	return version, tx.Commit()
+}
+
+// GetDraft returns the draft of an assignment; nil, nil if none is saved.
+func (p *PostgresDB) GetDraft(ctx context.Context, assignmentID int64) (*DraftRow, error) {
+	return scanDraft(p.DB.QueryRowContext(ctx, `
+		SELECT `+draftColumns+` FROM evaluation_drafts d JOIN assigned_scripts a ON a.id = d.assignment_id
+		WHERE d.assignment_id = $1`, assignmentID))
+}
+
+// ListDraftsByEvaluator returns an evaluator's drafts, most recently saved first.
+func (p *PostgresDB) ListDraftsByEvaluator(ctx context.Context, evaluatorID string) ([]DraftRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT `+draftColumns+` FROM evaluation_drafts d JOIN assigned_scripts a ON a.id = d.assignment_id
+		WHERE d.evaluator_id = $1 ORDER BY d.saved_at DESC`, evaluatorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []DraftRow
+	for rows.Next() {
+		r, err := scanDraft(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *r)
+	}
+	return out, rows.Err()
+}
+
+// DeleteDraft removes the draft of an assignment. With reopen the assignment
+// goes back from in_progress to assigned (a discarded draft); otherwise its
+// status is left to the caller (a submitted draft).
+func (p *PostgresDB) DeleteDraft(ctx context.Context, assignmentID int64, reopen bool) error {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM evaluation_drafts WHERE assignment_id = $1`, assignmentID); err != nil {
+		return err
+	}
+	if reopen {
+		if _, err := tx.ExecContext(ctx, `UPDATE assigned_scripts SET status = 'assigned' WHERE id = $1 AND status = 'in_progress'`, assignmentID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
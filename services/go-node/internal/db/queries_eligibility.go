@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Evaluator eligibility helpers
+
+type EligibilityRow struct {
+	ID              int64
+	EvaluatorID     string
+	CourseID        string
+	Department      string
+	ExperienceLevel string
+	ExperienceYears int
+	ValidFrom       time.Time
+	ValidUntil      sql.NullTime
+	Note            string
+	CreatedBy       string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+const eligibilityColumns = `id, evaluator_id, course_id, department, experience_level, experience_years, valid_from, valid_until, note, created_by, created_at, updated_at`
+
+// ListEligibility returns registry entries, optionally of one evaluator
+// (empty = every evaluator), newest validity first.
+func (p *PostgresDB) ListEligibility(ctx context.Context, evaluatorID string) ([]EligibilityRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT `+eligibilityColumns+` FROM evaluator_eligibility
+		WHERE ($1 = '' OR evaluator_id = $1)
+		ORDER BY evaluator_id ASC, valid_from DESC, id ASC`, evaluatorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []EligibilityRow
+	for rows.Next() {
+		var r EligibilityRow
+		if err := rows.Scan(&r.ID, &r.EvaluatorID, &r.CourseID, &r.Department, &r.ExperienceLevel, &r.ExperienceYears,
+			&r.ValidFrom, &r.ValidUntil, &r.Note, &r.CreatedBy, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// SaveEligibility creates an entry when ID is 0 and updates it otherwise;
+// sql.ErrNoRows if the entry to update does not exist.
+func (p *PostgresDB) SaveEligibility(ctx context.Context, r EligibilityRow) (int64, error) {
+	if r.ID == 0 {
+		var id int64
+		err := p.DB.QueryRowContext(ctx, `
+			INSERT INTO evaluator_eligibility (evaluator_id, course_id, department, experience_level, experience_years, valid_from, valid_until, note, created_by, created_at, updated_at)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9, now(), now()) RETURNING id`,
+			r.EvaluatorID, r.CourseID, r.Department, r.ExperienceLevel, r.ExperienceYears, r.ValidFrom, r.ValidUntil, r.Note, r.CreatedBy).Scan(&id)
+		return id, err
+	}
+	res, err := p.DB.ExecContext(ctx, `
+		UPDATE evaluator_eligibility
+		SET evaluator_id = $2, course_id = $3, department = $4, experience_level = $5, experience_years = $6,
+		    valid_from = $7, valid_until = $8, note = $9, updated_at = now()
+		WHERE id = $1`,
+		r.ID, r.EvaluatorID, r.CourseID, r.Department, r.ExperienceLevel, r.ExperienceYears, r.ValidFrom, r.ValidUntil, r.Note)
+	if err != nil {
+		return 0, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return 0, sql.ErrNoRows
+	}
+	return r.ID, nil
+}
+
+// DeleteEligibility removes a registry entry by id.
+func (p *PostgresDB) DeleteEligibility(ctx context.Context, id int64) error {
+	_, err := p.DB.ExecContext(ctx, `DELETE FROM evaluator_eligibility WHERE id = $1`, id)
+	return err
+}
@@ -2,17 +2,20 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 )
 
 // Assigned script helpers
 
-func (p *PostgresDB) CreateAssignment(ctx context.Context, scriptID, evaluatorID, courseID, semester string, academicYear string, courseCredits int) (int64, error) {
+// CreateAssignment assigns a script to an evaluator for the given valuation
+// round (1 for single valuation). A nil dueAt means no deadline.
+func (p *PostgresDB) CreateAssignment(ctx context.Context, scriptID, evaluatorID, courseID, semester string, academicYear string, courseCredits int, valuationRound int, dueAt *time.Time) (int64, error) {
 	var id int64
 	err := p.DB.QueryRowContext(ctx,
-		`INSERT INTO assigned_scripts (script_id, evaluator_id, course_id, semester, academic_year, course_credits, assigned_at, status)
-		 VALUES ($1,$2,$3,$4,$5,$6, now(), 'assigned') RETURNING id`,
-		scriptID, evaluatorID, courseID, semester, academicYear, courseCredits).Scan(&id)
+		`INSERT INTO assigned_scripts (script_id, evaluator_id, course_id, semester, academic_year, course_credits, valuation_round, due_at, assigned_at, status)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8, now(), 'assigned') RETURNING id`,
+		scriptID, evaluatorID, courseID, semester, academicYear, courseCredits, valuationRound, dueAt).Scan(&id)
 	return id, err
 }
 
@@ -26,27 +29,76 @@ type AssignedScriptRow struct {
 	CourseCredits int
 	AssignedAt    time.Time
 	Status        string
+	// ValuationRound is 1 for the first (or only) valuation, 2 for the
+	// second and 3 for a third valuation triggered by a discrepancy.
+	ValuationRound int
+	DueAt          *time.Time // nil: no deadline
+	RevokedAt      *time.Time
+	RevokeReason   string
+	ReassignedFrom *int64 // revoked assignment this one replaces
 }
 
-func (p *PostgresDB) ListAssignedByEvaluator(ctx context.Context, evaluatorID string) ([]AssignedScriptRow, error) {
-	rows, err := p.DB.QueryContext(ctx, `SELECT id,script_id,evaluator_id,course_id,semester,academic_year,course_credits,assigned_at,status FROM assigned_scripts WHERE evaluator_id=$1 ORDER BY assigned_at DESC`, evaluatorID)
+const assignedScriptColumns = `id,script_id,evaluator_id,course_id,semester,academic_year,course_credits,assigned_at,status,valuation_round,due_at,revoked_at,revoke_reason,reassigned_from`
+
+func scanAssignedScript(sc interface{ Scan(...interface{}) error }) (*AssignedScriptRow, error) {
+	var r AssignedScriptRow
+	if err := sc.Scan(&r.ID, &r.ScriptID, &r.Evaluator, &r.CourseID, &r.Semester, &r.AcademicYear, &r.CourseCredits, &r.AssignedAt, &r.Status, &r.ValuationRound,
+		&r.DueAt, &r.RevokedAt, &r.RevokeReason, &r.ReassignedFrom); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (p *PostgresDB) queryAssignedScripts(ctx context.Context, query string, args ...interface{}) ([]AssignedScriptRow, error) {
+	rows, err := p.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var out []AssignedScriptRow
 	for rows.Next() {
-		var r AssignedScriptRow
-		if err := rows.Scan(&r.ID, &r.ScriptID, &r.Evaluator, &r.CourseID, &r.Semester, &r.AcademicYear, &r.CourseCredits, &r.AssignedAt, &r.Status); err != nil {
+		r, err := scanAssignedScript(rows)
+		if err != nil {
 			return nil, err
 		}
-		out = append(out, r)
+		out = append(out, *r)
 	}
 	return out, rows.Err()
 }
 
+func (p *PostgresDB) ListAssignedByEvaluator(ctx context.Context, evaluatorID string) ([]AssignedScriptRow, error) {
+	return p.queryAssignedScripts(ctx, `SELECT `+assignedScriptColumns+` FROM assigned_scripts WHERE evaluator_id=$1 ORDER BY assigned_at DESC`, evaluatorID)
+}
+
+// GetAssignmentForEvaluator returns the evaluator's assignment of a script;
+// nil, nil if the script is not assigned to them.
+func (p *PostgresDB) GetAssignmentForEvaluator(ctx context.Context, scriptID, evaluatorID string) (*AssignedScriptRow, error) {
+	r, err := scanAssignedScript(p.DB.QueryRowContext(ctx,
+		`SELECT `+assignedScriptColumns+` FROM assigned_scripts WHERE script_id=$1 AND evaluator_id=$2`, scriptID, evaluatorID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+// ListActiveAssignmentsByScript returns the non-revoked assignments of a script ordered by round.
+func (p *PostgresDB) ListActiveAssignmentsByScript(ctx context.Context, scriptID string) ([]AssignedScriptRow, error) {
+	return p.queryAssignedScripts(ctx, `SELECT `+assignedScriptColumns+` FROM assigned_scripts WHERE script_id=$1 AND status <> 'revoked' ORDER BY valuation_round ASC`, scriptID)
+}
+
+// ListActiveAssignmentsByCourse returns the non-revoked assignments for a course and semester.
+func (p *PostgresDB) ListActiveAssignmentsByCourse(ctx context.Context, courseID, semester string) ([]AssignedScriptRow, error) {
+	return p.queryAssignedScripts(ctx, `SELECT `+assignedScriptColumns+` FROM assigned_scripts WHERE course_id=$1 AND semester=$2 AND status <> 'revoked'`, courseID, semester)
+}
+
 func (p *PostgresDB) UpdateAssignmentStatus(ctx context.Context, id int64, status string) error {
-	_, err := p.DB.ExecContext(ctx, `UPDATE assigned_scripts SET status=$1 WHERE id=$2`, status, id)
+	_, err := p.DB.ExecContext(ctx, `
+		UPDATE assigned_scripts
+		SET status = $1, evaluated_at = CASE WHEN $1 = 'evaluated' THEN now() ELSE evaluated_at END
+		WHERE id = $2`, status, id)
 	return err
 }
 
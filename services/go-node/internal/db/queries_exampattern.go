@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Exam pattern helpers
+
+type ExamPatternRow struct {
+	Code       string
+	Name       string
+	Definition json.RawMessage
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+const examPatternColumns = `code, name, definition, created_at, updated_at`
+
+func scanExamPattern(sc interface{ Scan(...interface{}) error }) (*ExamPatternRow, error) {
+	var r ExamPatternRow
+	if err := sc.Scan(&r.Code, &r.Name, &r.Definition, &r.CreatedAt, &r.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ListExamPatterns returns every stored pattern ordered by code.
+func (p *PostgresDB) ListExamPatterns(ctx context.Context) ([]ExamPatternRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `SELECT `+examPatternColumns+` FROM exam_patterns ORDER BY code ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []ExamPatternRow
+	for rows.Next() {
+		r, err := scanExamPattern(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *r)
+	}
+	return out, rows.Err()
+}
+
+// GetExamPattern returns a pattern by code; nil, nil if not found.
+func (p *PostgresDB) GetExamPattern(ctx context.Context, code string) (*ExamPatternRow, error) {
+	r, err := scanExamPattern(p.DB.QueryRowContext(ctx, `SELECT `+examPatternColumns+` FROM exam_patterns WHERE code = $1`, code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+// UpsertExamPattern stores a pattern, replacing the one with the same code.
+func (p *PostgresDB) UpsertExamPattern(ctx context.Context, r ExamPatternRow) error {
+	_, err := p.DB.ExecContext(ctx, `
+		INSERT INTO exam_patterns (code, name, definition, created_at, updated_at)
+		VALUES ($1,$2,$3, now(), now())
+		ON CONFLICT (code) DO UPDATE SET
+			name = EXCLUDED.name,
+			definition = EXCLUDED.definition,
+			updated_at = now()`,
+		r.Code, r.Name, []byte(r.Definition))
+	return err
+}
+
+// DeleteExamPattern removes a pattern by code.
+func (p *PostgresDB) DeleteExamPattern(ctx context.Context, code string) error {
+	_, err := p.DB.ExecContext(ctx, `DELETE FROM exam_patterns WHERE code = $1`, code)
+	return err
+}
+
+// CountCoursesWithExamPattern returns how many courses reference a pattern.
+func (p *PostgresDB) CountCoursesWithExamPattern(ctx context.Context, code string) (int, error) {
+	var n int
+	err := p.DB.QueryRowContext(ctx, `SELECT count(*) FROM courses WHERE upper(exam_pattern) = $1`, code).Scan(&n)
+	return n, err
+}
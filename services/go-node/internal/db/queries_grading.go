@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Grading scheme helpers
+
+type GradingSchemeRow struct {
+	ID             int64
+	Regulation     string
+	AcademicYear   string
+	PassPercentage float64
+	AbsentCode     string
+	WithheldCode   string
+	Rounding       string
+	Decimals       int
+	Grades         json.RawMessage
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+const gradingSchemeColumns = `id, regulation, academic_year, pass_percentage, absent_code, withheld_code, rounding, decimals, grades, created_at, updated_at`
+
+func scanGradingScheme(sc interface{ Scan(...interface{}) error }) (*GradingSchemeRow, error) {
+	var r GradingSchemeRow
+	err := sc.Scan(&r.ID, &r.Regulation, &r.AcademicYear, &r.PassPercentage, &r.AbsentCode, &r.WithheldCode, &r.Rounding, &r.Decimals, &r.Grades, &r.CreatedAt, &r.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ListGradingSchemes returns every stored scheme ordered by regulation and year.
+func (p *PostgresDB) ListGradingSchemes(ctx context.Context) ([]GradingSchemeRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `SELECT `+gradingSchemeColumns+` FROM grading_schemes ORDER BY regulation ASC, academic_year ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []GradingSchemeRow
+	for rows.Next() {
+		r, err := scanGradingScheme(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *r)
+	}
+	return out, rows.Err()
+}
+
+// FindGradingScheme returns the best matching scheme for a regulation and
+// academic year. Schemes of the given regulation win over the DEFAULT one, and
+// a scheme scoped to the exact year wins over one that applies to every year.
+// With an empty regulation only year-scoped schemes and DEFAULT are considered.
+// Returns nil, nil if nothing matches.
+func (p *PostgresDB) FindGradingScheme(ctx context.Context, regulation, academicYear string) (*GradingSchemeRow, error) {
+	row := p.DB.QueryRowContext(ctx, `
+		SELECT `+gradingSchemeColumns+`
+		FROM grading_schemes
+		WHERE (academic_year = $2 OR academic_year = '')
+		  AND (regulation = $1 OR regulation = 'DEFAULT' OR ($1 = '' AND academic_year = $2))
+		ORDER BY (regulation = $1 AND $1 <> '') DESC, (academic_year = $2) DESC, (regulation = 'DEFAULT') ASC, id DESC
+		LIMIT 1`, regulation, academicYear)
+	r, err := scanGradingScheme(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+// UpsertGradingScheme inserts a scheme or replaces the one stored for the same
+// (regulation, academic_year) and returns its id.
+func (p *PostgresDB) UpsertGradingScheme(ctx context.Context, r GradingSchemeRow) (int64, error) {
+	var id int64
+	err := p.DB.QueryRowContext(ctx, `
+		INSERT INTO grading_schemes (regulation, academic_year, pass_percentage, absent_code, withheld_code, rounding, decimals, grades, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8, now(), now())
+		ON CONFLICT (regulation, academic_year) DO UPDATE SET
+			pass_percentage = EXCLUDED.pass_percentage,
+			absent_code = EXCLUDED.absent_code,
+			withheld_code = EXCLUDED.withheld_code,
+			rounding = EXCLUDED.rounding,
+			decimals = EXCLUDED.decimals,
+			grades = EXCLUDED.grades,
+			updated_at = now()
+		RETURNING id`,
+		r.Regulation, r.AcademicYear, r.PassPercentage, r.AbsentCode, r.WithheldCode, r.Rounding, r.Decimals, r.Grades).Scan(&id)
+	return id, err
+}
+
+// DeleteGradingScheme removes a scheme by id.
+func (p *PostgresDB) DeleteGradingScheme(ctx context.Context, id int64) error {
+	_, err := p.DB.ExecContext(ctx, `DELETE FROM grading_schemes WHERE id=$1`, id)
+	return err
+}
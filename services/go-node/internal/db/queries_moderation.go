@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Moderation helpers
+
+// ListCourseEvaluations returns the current evaluations of a course in a
+// semester and academic year, ordered by USN.
+func (p *PostgresDB) ListCourseEvaluations(ctx context.Context, courseID, semester, academicYear string) ([]CurrentEvaluationRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT `+currentEvaluationColumns+` FROM evaluations
+		WHERE course_id = $1 AND semester = $2 AND academic_year = $3
+		ORDER BY student_usn ASC`, courseID, semester, academicYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []CurrentEvaluationRow
+	for rows.Next() {
+		r, err := scanCurrentEvaluation(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *r)
+	}
+	return out, rows.Err()
+}
+
+type ModerationRow struct {
+	ID                  int64           `json:"id"`
+	EvaluationID        int64           `json:"evaluation_id"`
+	BatchID             string          `json:"batch_id,omitempty"`
+	Kind                string          `json:"kind"`
+	ModeratorID         string          `json:"moderator_id"`
+	Reason              string          `json:"reason"`
+	Changes             json.RawMessage `json:"changes"`
+	PreviousScore       int             `json:"previous_score"`
+	ModeratedScore      int             `json:"moderated_score"`
+	PreviousResult      string          `json:"previous_result"`
+	ModeratedResult     string          `json:"moderated_result"`
+	EvaluationBlockHash string          `json:"evaluation_block_hash"`
+	BlockHash           string          `json:"block_hash"`
+	CreatedAt           time.Time       `json:"created_at"`
+}
+
+// ModerationWrite is one moderation together with the evaluation version it
+// changes. Marks is nil for notes, which leave the evaluation untouched.
+type ModerationWrite struct {
+	Moderation ModerationRow
+	Current    CurrentEvaluationRow
+	Marks      []byte
+}
+
+// ApplyModerations records moderations and, for mark changes, supersedes the
+// evaluation (keeping the previous version in evaluation_revisions). All
+// writes share one transaction so a bulk rule applies to every row or none.
+func (p *PostgresDB) ApplyModerations(ctx context.Context, writes []ModerationWrite) ([]int64, error) {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// a block already stored (the outbox replays items) is not applied again
+	ids := make([]int64, 0, len(writes))
+	if len(writes) > 0 {
+		if err := tx.SelectContext(ctx, &ids, `SELECT id FROM moderations WHERE block_hash = $1 ORDER BY id`, writes[0].Moderation.BlockHash); err != nil {
+			return nil, err
+		}
+		if len(ids) > 0 {
+			return ids, nil
+		}
+	}
+	for _, w := range writes {
+		m := w.Moderation
+		var id int64
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO moderations (evaluation_id, batch_id, kind, moderator_id, reason, changes, previous_score, moderated_score,
+				previous_result, moderated_result, evaluation_block_hash, block_hash)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12) RETURNING id`,
+			m.EvaluationID, m.BatchID, m.Kind, m.ModeratorID, m.Reason, []byte(m.Changes), m.PreviousScore, m.ModeratedScore,
+			m.PreviousResult, m.ModeratedResult, m.EvaluationBlockHash, m.BlockHash).Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+		if w.Marks == nil {
+			continue
+		}
+
+		cur := w.Current
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO evaluation_revisions (evaluation_id, revision, evaluator_id, marks, total_marks, result, block_hash, superseded_by_moderation)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
+			cur.ID, cur.Revision, cur.Evaluator, []byte(cur.Marks), cur.TotalMarks, cur.Result, cur.BlockHash, id); err != nil {
+			return nil, err
+		}
+		res, err := tx.ExecContext(ctx, `
+			UPDATE evaluations SET marks = $2, result = $3, revision = revision + 1, updated_at = now()
+			WHERE id = $1 AND revision = $4`,
+			cur.ID, w.Marks, m.ModeratedResult, cur.Revision)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return nil, fmt.Errorf("evaluation %d was changed concurrently", cur.ID)
+		}
+	}
+	return ids, tx.Commit()
+}
+
+// ListModerations returns the moderations of an evaluation, oldest first.
+func (p *PostgresDB) ListModerations(ctx context.Context, evaluationID int64) ([]ModerationRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT id, evaluation_id, batch_id, kind, moderator_id, reason, changes, previous_score, moderated_score,
+			previous_result, moderated_result, evaluation_block_hash, block_hash, created_at
+		FROM moderations WHERE evaluation_id = $1 ORDER BY created_at ASC, id ASC`, evaluationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []ModerationRow
+	for rows.Next() {
+		var m ModerationRow
+		if err := rows.Scan(&m.ID, &m.EvaluationID, &m.BatchID, &m.Kind, &m.ModeratorID, &m.Reason, &m.Changes, &m.PreviousScore, &m.ModeratedScore,
+			&m.PreviousResult, &m.ModeratedResult, &m.EvaluationBlockHash, &m.BlockHash, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,240 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Evaluator remuneration helpers
+
+type RemunerationRateRow struct {
+	ID            int64
+	CourseType    string
+	ValuationType string
+	AcademicYear  string
+	RatePerScript float64
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+const remunerationRateColumns = `id, course_type, valuation_type, academic_year, rate_per_script, created_at, updated_at`
+
+// ListRemunerationRates returns every stored rate.
+func (p *PostgresDB) ListRemunerationRates(ctx context.Context) ([]RemunerationRateRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `SELECT `+remunerationRateColumns+` FROM remuneration_rates ORDER BY course_type ASC, valuation_type ASC, academic_year ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []RemunerationRateRow
+	for rows.Next() {
+		var r RemunerationRateRow
+		if err := rows.Scan(&r.ID, &r.CourseType, &r.ValuationType, &r.AcademicYear, &r.RatePerScript, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// UpsertRemunerationRate stores a rate, replacing the one with the same scope.
+func (p *PostgresDB) UpsertRemunerationRate(ctx context.Context, r RemunerationRateRow) (int64, error) {
+	var id int64
+	err := p.DB.QueryRowContext(ctx, `
+		INSERT INTO remuneration_rates (course_type, valuation_type, academic_year, rate_per_script, created_at, updated_at)
+		VALUES ($1,$2,$3,$4, now(), now())
+		ON CONFLICT (course_type, valuation_type, academic_year) DO UPDATE
+		SET rate_per_script = EXCLUDED.rate_per_script, updated_at = now()
+		RETURNING id`, r.CourseType, r.ValuationType, r.AcademicYear, r.RatePerScript).Scan(&id)
+	return id, err
+}
+
+// DeleteRemunerationRate removes a stored rate.
+func (p *PostgresDB) DeleteRemunerationRate(ctx context.Context, id int64) error {
+	_, err := p.DB.ExecContext(ctx, `DELETE FROM remuneration_rates WHERE id = $1`, id)
+	return err
+}
+
+// RemunerableEvaluationRow is an evaluation as first recorded: the original
+// evaluator and, under double valuation, the valuations it was derived from.
+// Later revaluation or moderation does not change who did the work.
+type RemunerableEvaluationRow struct {
+	EvaluationID int64
+	ScriptID     string
+	CourseID     string
+	EvaluatorID  string
+	Valuations   json.RawMessage // marks.valuations; nil for a single valuation
+}
+
+// ListRemunerableEvaluations returns the evaluations of a cycle.
+func (p *PostgresDB) ListRemunerableEvaluations(ctx context.Context, semester, academicYear string) ([]RemunerableEvaluationRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT e.id, e.script_id, e.course_id, COALESCE(o.evaluator_id, e.evaluator_id), COALESCE(o.marks, e.marks)->'valuations'
+		FROM evaluations e
+		LEFT JOIN LATERAL (
+			SELECT r.evaluator_id, r.marks FROM evaluation_revisions r
+			WHERE r.evaluation_id = e.id ORDER BY r.revision ASC LIMIT 1
+		) o ON true
+		WHERE e.semester = $1 AND e.academic_year = $2
+		ORDER BY e.id ASC`, semester, academicYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []RemunerableEvaluationRow
+	for rows.Next() {
+		var r RemunerableEvaluationRow
+		var vals []byte
+		if err := rows.Scan(&r.EvaluationID, &r.ScriptID, &r.CourseID, &r.EvaluatorID, &vals); err != nil {
+			return nil, err
+		}
+		r.Valuations = vals
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// CompletedRevaluationRow is a revaluation an evaluator carried out.
+type CompletedRevaluationRow struct {
+	RequestID   int64
+	ScriptID    string
+	CourseID    string
+	EvaluatorID string
+}
+
+// ListCompletedRevaluations returns the completed revaluations (not
+// re-totals) of a cycle.
+func (p *PostgresDB) ListCompletedRevaluations(ctx context.Context, semester, academicYear string) ([]CompletedRevaluationRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT r.id, e.script_id, r.course_id, r.evaluator_id
+		FROM revaluation_requests r JOIN evaluations e ON e.id = r.evaluation_id
+		WHERE r.kind = 'revaluation' AND r.status = 'completed' AND r.evaluator_id <> ''
+		  AND r.semester = $1 AND r.academic_year = $2
+		ORDER BY r.id ASC`, semester, academicYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []CompletedRevaluationRow
+	for rows.Next() {
+		var r CompletedRevaluationRow
+		if err := rows.Scan(&r.RequestID, &r.ScriptID, &r.CourseID, &r.EvaluatorID); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type RemunerationStatementRow struct {
+	ID              int64           `json:"id"`
+	EvaluatorID     string          `json:"evaluator_id"`
+	Semester        string          `json:"semester"`
+	AcademicYear    string          `json:"academic_year"`
+	Scripts         int             `json:"scripts"`
+	Amount          float64         `json:"amount"`
+	Lines           json.RawMessage `json:"lines"`
+	Unrated         int             `json:"unrated"`
+	Status          string          `json:"status"`
+	ComputedAt      time.Time       `json:"computed_at"`
+	ApprovedBy      string          `json:"approved_by,omitempty"`
+	ApprovedAt      *time.Time      `json:"approved_at,omitempty"`
+	PayoutReference string          `json:"payout_reference,omitempty"`
+	PaidBy          string          `json:"paid_by,omitempty"`
+	PaidAt          *time.Time      `json:"paid_at,omitempty"`
+}
+
+const remunerationStatementColumns = `id, evaluator_id, semester, academic_year, scripts, amount, lines, unrated, status, computed_at,
+	approved_by, approved_at, payout_reference, paid_by, paid_at`
+
+func scanRemunerationStatement(sc interface{ Scan(...interface{}) error }) (*RemunerationStatementRow, error) {
+	var r RemunerationStatementRow
+	var approvedAt, paidAt sql.NullTime
+	if err := sc.Scan(&r.ID, &r.EvaluatorID, &r.Semester, &r.AcademicYear, &r.Scripts, &r.Amount, &r.Lines, &r.Unrated, &r.Status, &r.ComputedAt,
+		&r.ApprovedBy, &approvedAt, &r.PayoutReference, &r.PaidBy, &paidAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if approvedAt.Valid {
+		r.ApprovedAt = &approvedAt.Time
+	}
+	if paidAt.Valid {
+		r.PaidAt = &paidAt.Time
+	}
+	return &r, nil
+}
+
+// ListRemunerationStatements returns statements filtered by cycle, evaluator
+// and status (empty filter = any).
+func (p *PostgresDB) ListRemunerationStatements(ctx context.Context, semester, academicYear, evaluatorID, status string) ([]RemunerationStatementRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT `+remunerationStatementColumns+` FROM remuneration_statements
+		WHERE ($1 = '' OR semester = $1) AND ($2 = '' OR academic_year = $2) AND ($3 = '' OR evaluator_id = $3) AND ($4 = '' OR status = $4)
+		ORDER BY academic_year DESC, semester ASC, evaluator_id ASC`, semester, academicYear, evaluatorID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []RemunerationStatementRow
+	for rows.Next() {
+		r, err := scanRemunerationStatement(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *r)
+	}
+	return out, rows.Err()
+}
+
+// GetRemunerationStatement returns a statement by id; nil, nil if not found.
+func (p *PostgresDB) GetRemunerationStatement(ctx context.Context, id int64) (*RemunerationStatementRow, error) {
+	return scanRemunerationStatement(p.DB.QueryRowContext(ctx, `SELECT `+remunerationStatementColumns+` FROM remuneration_statements WHERE id = $1`, id))
+}
+
+// ReplaceDraftStatements drops the draft statements of a cycle and writes
+// rows in their place, in one transaction. Rows of evaluators whose
+// statement is already approved or paid are skipped.
+func (p *PostgresDB) ReplaceDraftStatements(ctx context.Context, semester, academicYear string, rows []RemunerationStatementRow) error {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM remuneration_statements WHERE semester = $1 AND academic_year = $2 AND status = 'draft'`, semester, academicYear); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO remuneration_statements (evaluator_id, semester, academic_year, scripts, amount, lines, unrated, status, computed_at)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,'draft', now())
+			ON CONFLICT (evaluator_id, semester, academic_year) DO NOTHING`,
+			r.EvaluatorID, semester, academicYear, r.Scripts, r.Amount, []byte(r.Lines), r.Unrated); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ApproveRemunerationStatement freezes a draft statement with every script
+// rated. Returns nil, nil if the statement is not such a draft.
+func (p *PostgresDB) ApproveRemunerationStatement(ctx context.Context, id int64, approvedBy string) (*RemunerationStatementRow, error) {
+	return scanRemunerationStatement(p.DB.QueryRowContext(ctx, `
+		UPDATE remuneration_statements
+		SET status = 'approved', approved_by = $2, approved_at = now()
+		WHERE id = $1 AND status = 'draft' AND unrated = 0
+		RETURNING `+remunerationStatementColumns, id, approvedBy))
+}
+
+// RecordRemunerationPayout marks an approved statement paid under the given
+// payout reference. Returns nil, nil if the statement is not approved.
+func (p *PostgresDB) RecordRemunerationPayout(ctx context.Context, id int64, reference, paidBy string) (*RemunerationStatementRow, error) {
+	return scanRemunerationStatement(p.DB.QueryRowContext(ctx, `
+		UPDATE remuneration_statements
+		SET status = 'paid', payout_reference = $2, paid_by = $3, paid_at = now()
+		WHERE id = $1 AND status = 'approved'
+		RETURNING `+remunerationStatementColumns, id, reference, paidBy))
+}
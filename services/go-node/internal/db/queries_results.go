@@ -8,9 +8,13 @@ import (
 )
 
 const insertEvaluationResultSQL = `
-INSERT INTO evaluations (script_id, student_usn, course_id, semester, academic_year, course_credits, evaluator_id, marks, total_marks, result, block_hash) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11) RETURNING id;`
+INSERT INTO evaluations (script_id, student_usn, course_id, semester, academic_year, course_credits, evaluator_id, marks, total_marks, result, block_hash)
+SELECT $1::text, $2::text, $3::text, $4::text, $5::text, $6::int, $7::text, $8::jsonb, $9::int, $10::text, $11::text
+WHERE NOT EXISTS (SELECT 1 FROM evaluations WHERE block_hash = $11)
+RETURNING id;`
 
-// InsertEvaluationResult inserts evaluated data into evaluations table.
+// InsertEvaluationResult inserts evaluated data into evaluations table. It
+// is idempotent per block: a row already written for blockHash is kept.
 func (p *PostgresDB) InsertEvaluationResult(ctx context.Context, scriptID string, studentUSN string, courseID string, semester string, academicYear string, courseCredits int, evaluatorID string, marksJSON []byte, totalMarks int, result string, blockHash string) error {
 
 	var id int
@@ -29,7 +33,16 @@ func (p *PostgresDB) InsertEvaluationResult(ctx context.Context, scriptID string
 		result,
 		blockHash,
 	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
 
+// SetEvaluationRemarks records the per-question remarks and page annotations
+// (JSON arrays) on the evaluation row of a block.
+func (p *PostgresDB) SetEvaluationRemarks(ctx context.Context, blockHash string, remarks, annotations []byte) error {
+	_, err := p.DB.ExecContext(ctx, `UPDATE evaluations SET remarks = $2, annotations = $3 WHERE block_hash = $1`, blockHash, remarks, annotations)
 	return err
 }
 
@@ -88,3 +101,92 @@ func (p *PostgresDB) RecordRelease(ctx context.Context, semester, academicYear,
 	err := p.DB.QueryRowContext(ctx, `INSERT INTO result_releases (semester, academic_year, released_by, block_hash, released_at) VALUES ($1,$2,$3,$4, now()) RETURNING id`, semester, academicYear, releasedBy, blockHash).Scan(&id)
 	return id, err
 }
+
+// FetchReleasedResultsByUSN returns every evaluation for a student across all
+// semesters and academic years that have a matching result_releases record.
+func (p *PostgresDB) FetchReleasedResultsByUSN(ctx context.Context, usn string) ([]EvaluationRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT e.id, e.script_id, e.student_usn, e.course_id, e.semester, e.academic_year, e.course_credits, e.evaluator_id, e.marks, e.total_marks, e.result, e.created_at
+		FROM evaluations e
+		JOIN result_releases rr ON rr.semester = e.semester AND rr.academic_year = e.academic_year
+		WHERE e.student_usn = $1
+		ORDER BY e.academic_year ASC, e.semester ASC, e.course_id ASC`, usn)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []EvaluationRow
+	for rows.Next() {
+		var r EvaluationRow
+		if err := rows.Scan(&r.ID, &r.ScriptID, &r.StudentUSN, &r.CourseID, &r.Semester, &r.AcademicYear, &r.CourseCredits, &r.Evaluator, &r.Marks, &r.TotalMarks, &r.Result, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// FetchReleasedResultsByUSNAndYear returns a student's evaluations for one
+// academic year, limited to semesters that have a result_releases record.
+func (p *PostgresDB) FetchReleasedResultsByUSNAndYear(ctx context.Context, usn string, academicYear string) ([]EvaluationRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT e.id, e.script_id, e.student_usn, e.course_id, e.semester, e.academic_year, e.course_credits, e.evaluator_id, e.marks, e.total_marks, e.result, e.created_at
+		FROM evaluations e
+		JOIN result_releases rr ON rr.semester = e.semester AND rr.academic_year = e.academic_year
+		WHERE e.student_usn = $1 AND e.academic_year = $2
+		ORDER BY e.created_at DESC`, usn, academicYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []EvaluationRow
+	for rows.Next() {
+		var r EvaluationRow
+		if err := rows.Scan(&r.ID, &r.ScriptID, &r.StudentUSN, &r.CourseID, &r.Semester, &r.AcademicYear, &r.CourseCredits, &r.Evaluator, &r.Marks, &r.TotalMarks, &r.Result, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type ReleaseRow struct {
+	ID           int64
+	Semester     string
+	AcademicYear string
+	ReleasedAt   time.Time
+	ReleasedBy   string
+	BlockHash    sql.NullString
+}
+
+// GetRelease returns the release record for a semester and academic year; nil, nil if not released.
+func (p *PostgresDB) GetRelease(ctx context.Context, semester, academicYear string) (*ReleaseRow, error) {
+	var r ReleaseRow
+	err := p.DB.QueryRowContext(ctx, `SELECT id, semester, academic_year, released_at, released_by, block_hash FROM result_releases WHERE semester = $1 AND academic_year = $2`, semester, academicYear).
+		Scan(&r.ID, &r.Semester, &r.AcademicYear, &r.ReleasedAt, &r.ReleasedBy, &r.BlockHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ListReleases returns every release record, oldest academic year and semester first.
+func (p *PostgresDB) ListReleases(ctx context.Context) ([]ReleaseRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `SELECT id, semester, academic_year, released_at, released_by, block_hash FROM result_releases ORDER BY academic_year ASC, semester ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []ReleaseRow
+	for rows.Next() {
+		var r ReleaseRow
+		if err := rows.Scan(&r.ID, &r.Semester, &r.AcademicYear, &r.ReleasedAt, &r.ReleasedBy, &r.BlockHash); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
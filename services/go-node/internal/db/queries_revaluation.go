@@ -0,0 +1,253 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Revaluation helpers
+
+type RevaluationRequestRow struct {
+	ID            int64         `json:"id"`
+	EvaluationID  int64         `json:"evaluation_id"`
+	StudentUSN    string        `json:"student_usn"`
+	CourseID      string        `json:"course_id"`
+	Semester      string        `json:"semester"`
+	AcademicYear  string        `json:"academic_year"`
+	Kind          string        `json:"kind"`
+	Reason        string        `json:"reason"`
+	Status        string        `json:"status"`
+	EvaluatorID   string        `json:"evaluator_id,omitempty"`
+	DecidedBy     string        `json:"decided_by,omitempty"`
+	DecidedAt     sql.NullTime  `json:"-"`
+	DecisionNote  string        `json:"decision_note,omitempty"`
+	CompletedBy   string        `json:"completed_by,omitempty"`
+	CompletedAt   sql.NullTime  `json:"-"`
+	PreviousScore sql.NullInt64 `json:"-"`
+	RevisedScore  sql.NullInt64 `json:"-"`
+	BlockHash     string        `json:"block_hash,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+const revaluationColumns = `id, evaluation_id, student_usn, course_id, semester, academic_year, kind, reason, status, evaluator_id,
+	decided_by, decided_at, decision_note, completed_by, completed_at, previous_score, revised_score, block_hash, created_at, updated_at`
+
+func scanRevaluation(sc interface{ Scan(...interface{}) error }) (*RevaluationRequestRow, error) {
+	var r RevaluationRequestRow
+	err := sc.Scan(&r.ID, &r.EvaluationID, &r.StudentUSN, &r.CourseID, &r.Semester, &r.AcademicYear, &r.Kind, &r.Reason, &r.Status, &r.EvaluatorID,
+		&r.DecidedBy, &r.DecidedAt, &r.DecisionNote, &r.CompletedBy, &r.CompletedAt, &r.PreviousScore, &r.RevisedScore, &r.BlockHash, &r.CreatedAt, &r.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// InsertRevaluationRequest files a pending request and returns its id.
+func (p *PostgresDB) InsertRevaluationRequest(ctx context.Context, r RevaluationRequestRow) (int64, error) {
+	var id int64
+	err := p.DB.QueryRowContext(ctx, `
+		INSERT INTO revaluation_requests (evaluation_id, student_usn, course_id, semester, academic_year, kind, reason, previous_score, status, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8, 'pending', now(), now()) RETURNING id`,
+		r.EvaluationID, r.StudentUSN, r.CourseID, r.Semester, r.AcademicYear, r.Kind, r.Reason, r.PreviousScore).Scan(&id)
+	return id, err
+}
+
+// GetRevaluationRequest returns a request by id; nil, nil if not found.
+func (p *PostgresDB) GetRevaluationRequest(ctx context.Context, id int64) (*RevaluationRequestRow, error) {
+	r, err := scanRevaluation(p.DB.QueryRowContext(ctx, `SELECT `+revaluationColumns+` FROM revaluation_requests WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+// ListRevaluationRequests returns requests filtered by status, student USN
+// and assigned evaluator (empty filter = any), newest first.
+func (p *PostgresDB) ListRevaluationRequests(ctx context.Context, status, usn, evaluatorID string) ([]RevaluationRequestRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT `+revaluationColumns+` FROM revaluation_requests
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR student_usn = $2) AND ($3 = '' OR evaluator_id = $3)
+		ORDER BY created_at DESC`, status, usn, evaluatorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []RevaluationRequestRow
+	for rows.Next() {
+		r, err := scanRevaluation(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *r)
+	}
+	return out, rows.Err()
+}
+
+// DecideRevaluationRequest approves or rejects a pending request. Returns
+// sql.ErrNoRows if the request is not pending.
+func (p *PostgresDB) DecideRevaluationRequest(ctx context.Context, id int64, status, evaluatorID, decidedBy, note string) error {
+	res, err := p.DB.ExecContext(ctx, `
+		UPDATE revaluation_requests
+		SET status = $2, evaluator_id = $3, decided_by = $4, decision_note = $5, decided_at = now(), updated_at = now()
+		WHERE id = $1 AND status = 'pending'`, id, status, evaluatorID, decidedBy, note)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CurrentEvaluationRow is an evaluation together with the chain transaction
+// and revision it currently reflects.
+type CurrentEvaluationRow struct {
+	EvaluationRow
+	BlockHash       string
+	Revision        int
+	AnnotatedCID    string // evaluator's annotated copy, "" if none was uploaded
+	AnnotatedSHA256 string
+	Remarks         json.RawMessage // per-question remarks (JSON array)
+	Annotations     json.RawMessage // page annotations (JSON array)
+}
+
+const currentEvaluationColumns = `id, script_id, student_usn, course_id, semester, academic_year, course_credits, evaluator_id, marks, total_marks, result, created_at, block_hash, revision, annotated_cid, annotated_sha256, remarks, annotations`
+
+func scanCurrentEvaluation(sc interface{ Scan(...interface{}) error }) (*CurrentEvaluationRow, error) {
+	var r CurrentEvaluationRow
+	err := sc.Scan(&r.ID, &r.ScriptID, &r.StudentUSN, &r.CourseID, &r.Semester, &r.AcademicYear, &r.CourseCredits, &r.Evaluator, &r.Marks, &r.TotalMarks, &r.Result, &r.CreatedAt, &r.BlockHash, &r.Revision, &r.AnnotatedCID, &r.AnnotatedSHA256, &r.Remarks, &r.Annotations)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetEvaluation returns an evaluation by id; nil, nil if not found.
+func (p *PostgresDB) GetEvaluation(ctx context.Context, id int64) (*CurrentEvaluationRow, error) {
+	return scanCurrentEvaluation(p.DB.QueryRowContext(ctx, `SELECT `+currentEvaluationColumns+` FROM evaluations WHERE id = $1`, id))
+}
+
+// GetStudentEvaluation returns a student's evaluation of a course in a
+// semester and academic year; nil, nil if not found.
+func (p *PostgresDB) GetStudentEvaluation(ctx context.Context, usn, courseID, semester, academicYear string) (*CurrentEvaluationRow, error) {
+	return scanCurrentEvaluation(p.DB.QueryRowContext(ctx, `
+		SELECT `+currentEvaluationColumns+` FROM evaluations
+		WHERE student_usn = $1 AND course_id = $2 AND semester = $3 AND academic_year = $4`, usn, courseID, semester, academicYear))
+}
+
+// ListScriptEvaluators returns everyone who has marked a script, as the
+// evaluator of record or in any valuation round.
+func (p *PostgresDB) ListScriptEvaluators(ctx context.Context, scriptID string) ([]string, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT evaluator_id FROM evaluations WHERE script_id = $1
+		UNION SELECT evaluator_id FROM valuations WHERE script_id = $1
+		UNION SELECT r.evaluator_id FROM revaluation_requests r JOIN evaluations e ON e.id = r.evaluation_id
+			WHERE e.script_id = $1 AND r.kind = 'revaluation' AND r.status = 'completed'`, scriptID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+// ApplyRevaluation supersedes the current version of an evaluation with the
+// revised marks and completes the request, in one transaction. The previous
+// version, whose marks carry its remarks and annotations, is kept in
+// evaluation_revisions; remarks and annotations are those of the revision
+// (nil clears them). A request already completed by blockHash is left as it
+// is.
+func (p *PostgresDB) ApplyRevaluation(ctx context.Context, requestID int64, cur CurrentEvaluationRow, marks []byte, result, blockHash, completedBy string, revisedScore int, remarks, annotations []byte) error {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var done bool
+	if err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM revaluation_requests WHERE id = $1 AND status = 'completed' AND block_hash = $2)`,
+		requestID, blockHash).Scan(&done); err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO evaluation_revisions (evaluation_id, revision, evaluator_id, marks, total_marks, result, block_hash, superseded_by_request)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
+		cur.ID, cur.Revision, cur.Evaluator, []byte(cur.Marks), cur.TotalMarks, cur.Result, cur.BlockHash, requestID); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, `
+		UPDATE evaluations SET marks = $2, result = $3, block_hash = $4, remarks = $6, annotations = $7, revision = revision + 1, updated_at = now()
+		WHERE id = $1 AND revision = $5`,
+		cur.ID, marks, result, blockHash, cur.Revision, remarks, annotations)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	res, err = tx.ExecContext(ctx, `
+		UPDATE revaluation_requests
+		SET status = 'completed', completed_by = $2, completed_at = now(), revised_score = $3, block_hash = $4, updated_at = now()
+		WHERE id = $1 AND status = 'approved'`,
+		requestID, completedBy, revisedScore, blockHash)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+type EvaluationRevisionRow struct {
+	Revision            int             `json:"revision"`
+	EvaluatorID         string          `json:"evaluator_id"`
+	Marks               json.RawMessage `json:"marks"`
+	TotalMarks          int             `json:"total_marks"`
+	Result              string          `json:"result"`
+	BlockHash           string          `json:"block_hash"`
+	SupersededByRequest sql.NullInt64   `json:"-"`
+	SupersededByMod     sql.NullInt64   `json:"-"`
+	SupersededAt        time.Time       `json:"superseded_at"`
+}
+
+// ListEvaluationRevisions returns the superseded versions of an evaluation, oldest first.
+func (p *PostgresDB) ListEvaluationRevisions(ctx context.Context, evaluationID int64) ([]EvaluationRevisionRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT revision, evaluator_id, marks, total_marks, result, block_hash, superseded_by_request, superseded_by_moderation, superseded_at
+		FROM evaluation_revisions WHERE evaluation_id = $1 ORDER BY revision ASC`, evaluationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []EvaluationRevisionRow
+	for rows.Next() {
+		var r EvaluationRevisionRow
+		if err := rows.Scan(&r.Revision, &r.EvaluatorID, &r.Marks, &r.TotalMarks, &r.Result, &r.BlockHash, &r.SupersededByRequest, &r.SupersededByMod, &r.SupersededAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
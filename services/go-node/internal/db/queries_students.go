@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Student account ↔ USN mapping helpers
+
+type StudentAccountRow struct {
+	UserID    string
+	USN       string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// GetUSNForUser returns the USN linked to a student login; "" if none is linked.
+func (p *PostgresDB) GetUSNForUser(ctx context.Context, userID string) (string, error) {
+	var usn string
+	err := p.DB.QueryRowContext(ctx, `SELECT usn FROM student_accounts WHERE user_id = $1`, userID).Scan(&usn)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+	return usn, nil
+}
+
+// ListStudentAccounts returns every student login with its USN.
+func (p *PostgresDB) ListStudentAccounts(ctx context.Context) ([]StudentAccountRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `SELECT user_id, usn, created_at, updated_at FROM student_accounts ORDER BY usn ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []StudentAccountRow
+	for rows.Next() {
+		var r StudentAccountRow
+		if err := rows.Scan(&r.UserID, &r.USN, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// LinkStudentAccount links a student login to a USN, replacing any previous link for that login.
+func (p *PostgresDB) LinkStudentAccount(ctx context.Context, userID, usn string) error {
+	_, err := p.DB.ExecContext(ctx, `
+		INSERT INTO student_accounts (user_id, usn, created_at, updated_at)
+		VALUES ($1, $2, now(), now())
+		ON CONFLICT (user_id) DO UPDATE SET usn = EXCLUDED.usn, updated_at = now()`, userID, usn)
+	return err
+}
+
+// UnlinkStudentAccount removes the USN link of a student login.
+func (p *PostgresDB) UnlinkStudentAccount(ctx context.Context, userID string) error {
+	_, err := p.DB.ExecContext(ctx, `DELETE FROM student_accounts WHERE user_id = $1`, userID)
+	return err
+}
@@ -0,0 +1,204 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Valuation policy and multi-valuation helpers
+
+type ValuationPolicyRow struct {
+	ID               int64
+	CourseID         string
+	Semester         string
+	Mode             string
+	Aggregation      string
+	ThresholdPercent float64
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+const valuationPolicyColumns = `id, course_id, semester, mode, aggregation, threshold_percent, created_at, updated_at`
+
+func scanValuationPolicy(sc interface{ Scan(...interface{}) error }) (*ValuationPolicyRow, error) {
+	var r ValuationPolicyRow
+	if err := sc.Scan(&r.ID, &r.CourseID, &r.Semester, &r.Mode, &r.Aggregation, &r.ThresholdPercent, &r.CreatedAt, &r.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ListValuationPolicies returns every stored policy.
+func (p *PostgresDB) ListValuationPolicies(ctx context.Context) ([]ValuationPolicyRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `SELECT `+valuationPolicyColumns+` FROM valuation_policies ORDER BY course_id ASC, semester ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []ValuationPolicyRow
+	for rows.Next() {
+		r, err := scanValuationPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *r)
+	}
+	return out, rows.Err()
+}
+
+// FindValuationPolicy returns the most specific policy for a course and
+// semester: course-scoped beats semester-scoped beats the global policy
+// (both empty). Returns nil, nil if nothing matches.
+func (p *PostgresDB) FindValuationPolicy(ctx context.Context, courseID, semester string) (*ValuationPolicyRow, error) {
+	r, err := scanValuationPolicy(p.DB.QueryRowContext(ctx, `
+		SELECT `+valuationPolicyColumns+` FROM valuation_policies
+		WHERE (course_id = $1 OR course_id = '') AND (semester = $2 OR semester = '')
+		ORDER BY (course_id <> '') DESC, (semester <> '') DESC
+		LIMIT 1`, courseID, semester))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+// UpsertValuationPolicy stores a policy, replacing the one with the same scope.
+func (p *PostgresDB) UpsertValuationPolicy(ctx context.Context, r ValuationPolicyRow) (int64, error) {
+	var id int64
+	err := p.DB.QueryRowContext(ctx, `
+		INSERT INTO valuation_policies (course_id, semester, mode, aggregation, threshold_percent, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5, now(), now())
+		ON CONFLICT (course_id, semester) DO UPDATE SET
+			mode = EXCLUDED.mode,
+			aggregation = EXCLUDED.aggregation,
+			threshold_percent = EXCLUDED.threshold_percent,
+			updated_at = now()
+		RETURNING id`,
+		r.CourseID, r.Semester, r.Mode, r.Aggregation, r.ThresholdPercent).Scan(&id)
+	return id, err
+}
+
+// DeleteValuationPolicy removes a policy by id.
+func (p *PostgresDB) DeleteValuationPolicy(ctx context.Context, id int64) error {
+	_, err := p.DB.ExecContext(ctx, `DELETE FROM valuation_policies WHERE id = $1`, id)
+	return err
+}
+
+type ValuationRow struct {
+	ID             int64           `json:"id"`
+	ScriptID       string          `json:"script_id"`
+	AssignmentID   sql.NullInt64   `json:"-"`
+	EvaluatorID    string          `json:"evaluator_id"`
+	ValuationRound int             `json:"valuation_round"`
+	CourseID       string          `json:"course_id"`
+	Semester       string          `json:"semester"`
+	AcademicYear   string          `json:"academic_year"`
+	Marks          json.RawMessage `json:"marks"`
+	Score          int             `json:"score"`
+	TotalMarks     int             `json:"total_marks"`
+	BlockHash      string          `json:"block_hash"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// InsertValuation records one valuation of a script. It is idempotent per
+// block: the id of a row already written for v.BlockHash is returned.
+func (p *PostgresDB) InsertValuation(ctx context.Context, v ValuationRow) (int64, error) {
+	var id int64
+	err := p.DB.QueryRowContext(ctx, `
+		INSERT INTO valuations (script_id, assignment_id, evaluator_id, valuation_round, course_id, semester, academic_year, marks, score, total_marks, block_hash)
+		SELECT $1::text, $2::int, $3::text, $4::int, $5::text, $6::text, $7::text, $8::jsonb, $9::int, $10::int, $11::text
+		WHERE NOT EXISTS (SELECT 1 FROM valuations WHERE block_hash = $11)
+		RETURNING id`,
+		v.ScriptID, v.AssignmentID, v.EvaluatorID, v.ValuationRound, v.CourseID, v.Semester, v.AcademicYear, []byte(v.Marks), v.Score, v.TotalMarks, v.BlockHash).Scan(&id)
+	if err == sql.ErrNoRows {
+		err = p.DB.QueryRowContext(ctx, `SELECT id FROM valuations WHERE block_hash = $1`, v.BlockHash).Scan(&id)
+	}
+	return id, err
+}
+
+// ListValuationsByScript returns the valuations of a script ordered by round.
+func (p *PostgresDB) ListValuationsByScript(ctx context.Context, scriptID string) ([]ValuationRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT id, script_id, assignment_id, evaluator_id, valuation_round, course_id, semester, academic_year, marks, score, total_marks, block_hash, created_at
+		FROM valuations WHERE script_id = $1 ORDER BY valuation_round ASC`, scriptID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []ValuationRow
+	for rows.Next() {
+		var v ValuationRow
+		if err := rows.Scan(&v.ID, &v.ScriptID, &v.AssignmentID, &v.EvaluatorID, &v.ValuationRound, &v.CourseID, &v.Semester, &v.AcademicYear, &v.Marks, &v.Score, &v.TotalMarks, &v.BlockHash, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+type PendingThirdValuationRow struct {
+	ScriptID     string    `json:"script_id"`
+	CourseID     string    `json:"course_id"`
+	Semester     string    `json:"semester"`
+	AcademicYear string    `json:"academic_year"`
+	SecondAt     time.Time `json:"second_valuation_at"`
+}
+
+// ListPendingThirdValuations returns scripts whose two valuations disagreed
+// (no final evaluation was written) and that have no live third assignment.
+func (p *PostgresDB) ListPendingThirdValuations(ctx context.Context) ([]PendingThirdValuationRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT v.script_id, v.course_id, v.semester, v.academic_year, v.created_at
+		FROM valuations v
+		WHERE v.valuation_round = 2
+		  AND NOT EXISTS (SELECT 1 FROM evaluations e WHERE e.script_id = v.script_id)
+		  AND NOT EXISTS (SELECT 1 FROM assigned_scripts a WHERE a.script_id = v.script_id AND a.valuation_round = 3 AND a.status <> 'revoked')
+		ORDER BY v.created_at ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []PendingThirdValuationRow
+	for rows.Next() {
+		var r PendingThirdValuationRow
+		if err := rows.Scan(&r.ScriptID, &r.CourseID, &r.Semester, &r.AcademicYear, &r.SecondAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// PickValuationEvaluator returns the evaluator approved for (or already
+// assigned to) the course and semester with the fewest open assignments,
+// excluding anyone who already holds an assignment of the script or is listed
+// in exclude. Returns "" when no such evaluator exists.
+func (p *PostgresDB) PickValuationEvaluator(ctx context.Context, courseID, semester, scriptID string, exclude []string) (string, error) {
+	if exclude == nil {
+		exclude = []string{} // a nil array is NULL and would match nobody
+	}
+	var evaluatorID string
+	err := p.DB.QueryRowContext(ctx, `
+		SELECT c.evaluator_id
+		FROM (
+			SELECT evaluator_id FROM evaluation_requests WHERE course_id = $1 AND semester = $2 AND status = 'approved'
+			UNION
+			SELECT evaluator_id FROM assigned_scripts WHERE course_id = $1 AND semester = $2
+		) c
+		LEFT JOIN assigned_scripts a ON a.evaluator_id = c.evaluator_id AND a.status IN ('assigned', 'in_progress')
+		WHERE c.evaluator_id NOT IN (SELECT evaluator_id FROM assigned_scripts WHERE script_id = $3)
+			AND NOT (c.evaluator_id = ANY($4))
+		GROUP BY c.evaluator_id
+		ORDER BY count(a.id) ASC, c.evaluator_id ASC
+		LIMIT 1`, courseID, semester, scriptID, pq.Array(exclude)).Scan(&evaluatorID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return evaluatorID, err
+}
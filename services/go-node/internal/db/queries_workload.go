@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Workload dashboard helpers
+
+// WorkloadRow is the timing of one assignment.
+type WorkloadRow struct {
+	ID           int64      `json:"id"`
+	EvaluatorID  string     `json:"evaluator_id"`
+	CourseID     string     `json:"course_id"`
+	Semester     string     `json:"semester"`
+	AcademicYear string     `json:"academic_year"`
+	Round        int        `json:"valuation_round"`
+	Status       string     `json:"status"`
+	AssignedAt   time.Time  `json:"assigned_at"`
+	DueAt        *time.Time `json:"due_at,omitempty"`
+	EvaluatedAt  *time.Time `json:"evaluated_at,omitempty"`
+}
+
+// ListWorkload returns the assignments of a semester, academic year and
+// course (empty filter = any).
+func (p *PostgresDB) ListWorkload(ctx context.Context, semester, academicYear, courseID string) ([]WorkloadRow, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT id, evaluator_id, course_id, semester, COALESCE(academic_year, ''), valuation_round, status, assigned_at, due_at, evaluated_at
+		FROM assigned_scripts
+		WHERE ($1 = '' OR semester = $1) AND ($2 = '' OR academic_year = $2) AND ($3 = '' OR course_id = $3)
+		ORDER BY assigned_at ASC, id ASC`, semester, academicYear, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []WorkloadRow
+	for rows.Next() {
+		var r WorkloadRow
+		var due, evaluated sql.NullTime
+		if err := rows.Scan(&r.ID, &r.EvaluatorID, &r.CourseID, &r.Semester, &r.AcademicYear, &r.Round, &r.Status, &r.AssignedAt, &due, &evaluated); err != nil {
+			return nil, err
+		}
+		if due.Valid {
+			r.DueAt = &due.Time
+		}
+		if evaluated.Valid {
+			r.EvaluatedAt = &evaluated.Time
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// CountEvaluatedScripts returns the number of scripts with a final
+// evaluation per course and semester, keyed "course|semester".
+func (p *PostgresDB) CountEvaluatedScripts(ctx context.Context, semester, academicYear, courseID string) (map[string]int, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT course_id, semester, COUNT(DISTINCT script_id)
+		FROM evaluations
+		WHERE ($1 = '' OR semester = $1) AND ($2 = '' OR academic_year = $2) AND ($3 = '' OR course_id = $3)
+		GROUP BY course_id, semester`, semester, academicYear, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[string]int{}
+	for rows.Next() {
+		var course, sem string
+		var n int
+		if err := rows.Scan(&course, &sem, &n); err != nil {
+			return nil, err
+		}
+		out[course+"|"+sem] = n
+	}
+	return out, rows.Err()
+}
+
+// CountPendingThirdValuations returns the number of scripts valued twice
+// without a final evaluation (their valuations disagreed and a third is
+// assigned or awaited) per course and semester, keyed "course|semester".
+func (p *PostgresDB) CountPendingThirdValuations(ctx context.Context, semester, academicYear, courseID string) (map[string]int, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT v.course_id, v.semester, COUNT(DISTINCT v.script_id)
+		FROM valuations v
+		WHERE v.valuation_round = 2
+		  AND ($1 = '' OR v.semester = $1) AND ($2 = '' OR v.academic_year = $2) AND ($3 = '' OR v.course_id = $3)
+		  AND NOT EXISTS (SELECT 1 FROM evaluations e WHERE e.script_id = v.script_id)
+		GROUP BY v.course_id, v.semester`, semester, academicYear, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[string]int{}
+	for rows.Next() {
+		var course, sem string
+		var n int
+		if err := rows.Scan(&course, &sem, &n); err != nil {
+			return nil, err
+		}
+		out[course+"|"+sem] = n
+	}
+	return out, rows.Err()
+}
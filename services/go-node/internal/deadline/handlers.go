@@ -0,0 +1,158 @@
+package deadline
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler exposes assignment deadline endpoints.
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GET /api/v1/authority/assignments?status=&course_id=&evaluator_id=&overdue=true
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	rows, err := h.svc.List(r.Context(), q.Get("status"), q.Get("course_id"), q.Get("evaluator_id"), q.Get("overdue") == "true")
+	if err != nil {
+		http.Error(w, "failed to load assignments", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rows, http.StatusOK)
+}
+
+// GET /api/v1/authority/assignments/pool
+// Revoked scripts waiting for another evaluator.
+func (h *Handler) Pool(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.svc.Pool(r.Context())
+	if err != nil {
+		http.Error(w, "failed to load pool", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rows, http.StatusOK)
+}
+
+// GET /api/v1/authority/assignments/policy
+func (h *Handler) Policy(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.svc.Policy(), http.StatusOK)
+}
+
+// GET /api/v1/authority/assignments/events?assignment_id=&evaluator_id=
+func (h *Handler) Events(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	id, _ := strconv.ParseInt(q.Get("assignment_id"), 10, 64)
+	rows, err := h.svc.Events(r.Context(), id, q.Get("evaluator_id"))
+	if err != nil {
+		http.Error(w, "failed to load events", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rows, http.StatusOK)
+}
+
+// POST /api/v1/authority/assignments/sweep
+// Runs the reminder / revocation / reassignment sweep now.
+func (h *Handler) Sweep(w http.ResponseWriter, r *http.Request) {
+	rep, err := h.svc.Sweep(r.Context())
+	if err != nil {
+		http.Error(w, "sweep failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rep, http.StatusOK)
+}
+
+// POST /api/v1/authority/assignments/{id}/extend
+// Body: {"due_at":"2025-06-30T17:00:00Z","authority_id":"..."}
+func (h *Handler) Extend(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	var body struct {
+		DueAt       time.Time `json:"due_at"`
+		AuthorityID string    `json:"authority_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.Extend(r.Context(), id, body.DueAt, body.AuthorityID); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"id": id, "due_at": body.DueAt}, http.StatusOK)
+}
+
+// POST /api/v1/authority/assignments/{id}/revoke
+// Body: {"reason":"...","authority_id":"..."}
+func (h *Handler) Revoke(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	var body struct {
+		Reason      string `json:"reason"`
+		AuthorityID string `json:"authority_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	re, err := h.svc.Revoke(r.Context(), id, body.AuthorityID, body.Reason)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"status": "revoked", "reassigned": re}, http.StatusOK)
+}
+
+// GET /api/v1/evaluator/reminders?evaluator_id=...
+func (h *Handler) EvaluatorEvents(w http.ResponseWriter, r *http.Request) {
+	eid := r.URL.Query().Get("evaluator_id")
+	if eid == "" {
+		http.Error(w, "missing evaluator_id", http.StatusBadRequest)
+		return
+	}
+	rows, err := h.svc.Events(r.Context(), 0, eid)
+	if err != nil {
+		http.Error(w, "failed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rows, http.StatusOK)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrNotOpen) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func RegisterDeadlineRoutes(r *mux.Router, svc *Service) {
+	h := NewHandler(svc)
+	r.HandleFunc("/authority/assignments", h.List).Methods("GET")
+	r.HandleFunc("/authority/assignments/pool", h.Pool).Methods("GET")
+	r.HandleFunc("/authority/assignments/policy", h.Policy).Methods("GET")
+	r.HandleFunc("/authority/assignments/events", h.Events).Methods("GET")
+	r.HandleFunc("/authority/assignments/sweep", h.Sweep).Methods("POST")
+	r.HandleFunc("/authority/assignments/{id}/extend", h.Extend).Methods("POST")
+	r.HandleFunc("/authority/assignments/{id}/revoke", h.Revoke).Methods("POST")
+	r.HandleFunc("/evaluator/reminders", h.EvaluatorEvents).Methods("GET")
+}
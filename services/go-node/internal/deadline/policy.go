@@ -0,0 +1,84 @@
+package deadline
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Policy sets how long evaluators have for an assignment, when they are
+// reminded and how often overdue assignments are swept.
+type Policy struct {
+	DueDays       int   `yaml:"due_days" json:"due_days"`             // 0: assignments never expire
+	ReminderHours []int `yaml:"reminder_hours" json:"reminder_hours"` // hours before the deadline
+	SweepMinutes  int   `yaml:"sweep_interval_minutes" json:"sweep_interval_minutes"`
+}
+
+// Default gives evaluators a week, reminds them two days and one day ahead
+// and sweeps every 15 minutes.
+func Default() Policy {
+	return Policy{DueDays: 7, ReminderHours: []int{48, 24}, SweepMinutes: 15}
+}
+
+// Normalize fills unset fields from Default and orders reminders from the
+// latest (closest to the deadline) to the earliest.
+func (p *Policy) Normalize() {
+	def := Default()
+	if p.DueDays == 0 && len(p.ReminderHours) == 0 && p.SweepMinutes == 0 {
+		*p = def
+	}
+	if p.SweepMinutes <= 0 {
+		p.SweepMinutes = def.SweepMinutes
+	}
+	sort.Ints(p.ReminderHours)
+}
+
+// Validate checks the policy.
+func (p Policy) Validate() error {
+	if p.DueDays < 0 {
+		return fmt.Errorf("due_days must be >= 0")
+	}
+	for _, h := range p.ReminderHours {
+		if h <= 0 {
+			return fmt.Errorf("reminder_hours must be > 0")
+		}
+	}
+	return nil
+}
+
+// DueAt returns the deadline of an assignment made at from, or nil when
+// assignments do not expire. days > 0 overrides the policy.
+func (p Policy) DueAt(from time.Time, days int) *time.Time {
+	if days <= 0 {
+		days = p.DueDays
+	}
+	if days <= 0 {
+		return nil
+	}
+	t := from.AddDate(0, 0, days)
+	return &t
+}
+
+// reminderDue returns the reminder offset (in hours) an assignment due at
+// dueAt has reached at now: the smallest offset not yet past. ok is false
+// when no reminder applies yet or the deadline has passed.
+func (p Policy) reminderDue(dueAt, now time.Time) (hours int, ok bool) {
+	left := dueAt.Sub(now)
+	if left <= 0 {
+		return 0, false
+	}
+	for _, h := range p.ReminderHours {
+		if left <= time.Duration(h)*time.Hour {
+			return h, true
+		}
+	}
+	return 0, false
+}
+
+// maxReminder is the earliest reminder offset.
+func (p Policy) maxReminder() time.Duration {
+	if len(p.ReminderHours) == 0 {
+		return 0
+	}
+	return time.Duration(p.ReminderHours[len(p.ReminderHours)-1]) * time.Hour
+}
@@ -0,0 +1,300 @@
+package deadline
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"digital-eval-system/services/go-node/internal/conflict"
+	"digital-eval-system/services/go-node/internal/db"
+)
+
+// Assignment event kinds.
+const (
+	EventReminder   = "reminder"
+	EventRevoked    = "revoked"
+	EventReassigned = "reassigned"
+	EventExtended   = "extended"
+)
+
+// ErrNotOpen is returned when revoking or extending an assignment that is
+// already evaluated or revoked.
+var ErrNotOpen = errors.New("assignment is not open")
+
+// Assignment is an assigned script as shown to the authority.
+type Assignment struct {
+	ID             int64      `json:"id"`
+	ScriptID       string     `json:"script_id"`
+	EvaluatorID    string     `json:"evaluator_id"`
+	CourseID       string     `json:"course_id"`
+	Semester       string     `json:"semester"`
+	AcademicYear   string     `json:"academic_year"`
+	ValuationRound int        `json:"valuation_round"`
+	Status         string     `json:"status"`
+	AssignedAt     time.Time  `json:"assigned_at"`
+	DueAt          *time.Time `json:"due_at,omitempty"`
+	Overdue        bool       `json:"overdue"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	RevokeReason   string     `json:"revoke_reason,omitempty"`
+	ReassignedFrom *int64     `json:"reassigned_from,omitempty"`
+}
+
+// Reassignment records a revoked script round handed to another evaluator.
+type Reassignment struct {
+	ScriptID       string `json:"script_id"`
+	ValuationRound int    `json:"valuation_round"`
+	From           string `json:"from_evaluator"`
+	To             string `json:"to_evaluator"`
+	AssignmentID   int64  `json:"assignment_id"`
+}
+
+// SweepReport summarises one sweep.
+type SweepReport struct {
+	Reminders   int            `json:"reminders"`
+	Revoked     []int64        `json:"revoked"`
+	Reassigned  []Reassignment `json:"reassigned"`
+	WaitingPool []string       `json:"waiting_pool"` // scripts with no evaluator available
+}
+
+// Service enforces assignment deadlines: reminders, revocation of overdue
+// assignments and reassignment of revoked scripts.
+type Service struct {
+	pg        *db.PostgresDB
+	policy    Policy
+	conflicts *conflict.Service
+	mu        sync.Mutex // one sweep at a time
+}
+
+func NewService(pg *db.PostgresDB, policy Policy, conflictSvc *conflict.Service) *Service {
+	policy.Normalize()
+	return &Service{pg: pg, policy: policy, conflicts: conflictSvc}
+}
+
+// Policy returns the deadline policy in force.
+func (s *Service) Policy() Policy {
+	if s == nil {
+		return Default()
+	}
+	return s.policy
+}
+
+// DueAt returns the deadline for an assignment made now; days > 0 overrides
+// the policy. Nil when assignments do not expire.
+func (s *Service) DueAt(days int) *time.Time {
+	return s.Policy().DueAt(time.Now(), days)
+}
+
+// Run sweeps on the policy interval until ctx is cancelled.
+func (s *Service) Run(ctx context.Context) {
+	t := time.NewTicker(time.Duration(s.policy.SweepMinutes) * time.Minute)
+	defer t.Stop()
+	for {
+		if rep, err := s.Sweep(ctx); err != nil {
+			logrus.Warnf("assignment deadline sweep failed: %v", err)
+		} else if rep.Reminders > 0 || len(rep.Revoked) > 0 || len(rep.Reassigned) > 0 {
+			logrus.Infof("assignment deadline sweep: %d reminders, %d revoked, %d reassigned, %d waiting",
+				rep.Reminders, len(rep.Revoked), len(rep.Reassigned), len(rep.WaitingPool))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Sweep sends due reminders, revokes overdue open assignments and reassigns
+// every revoked script round still waiting in the pool.
+func (s *Service) Sweep(ctx context.Context) (*SweepReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	rep := &SweepReport{}
+
+	due, err := s.pg.ListOpenAssignmentsDueBefore(ctx, now.Add(s.policy.maxReminder()))
+	if err != nil {
+		return nil, fmt.Errorf("load due assignments: %w", err)
+	}
+	for _, a := range due {
+		if a.DueAt == nil {
+			continue
+		}
+		if !a.DueAt.After(now) {
+			if err := s.pg.RevokeAssignment(ctx, a.ID, "overdue"); err != nil {
+				if err != sql.ErrNoRows {
+					logrus.Warnf("revoke overdue assignment %d: %v", a.ID, err)
+				}
+				continue
+			}
+			s.event(ctx, a, EventRevoked, "overdue")
+			rep.Revoked = append(rep.Revoked, a.ID)
+			continue
+		}
+		if h, ok := s.policy.reminderDue(*a.DueAt, now); ok {
+			sent, err := s.pg.InsertAssignmentEvent(ctx, db.AssignmentEventRow{AssignmentID: a.ID, EvaluatorID: a.Evaluator, Kind: EventReminder, Detail: fmt.Sprintf("%dh", h)})
+			if err != nil {
+				logrus.Warnf("record reminder for assignment %d: %v", a.ID, err)
+				continue
+			}
+			if sent {
+				logrus.Infof("reminder: evaluator %s, script %s due %s", a.Evaluator, a.ScriptID, a.DueAt.Format(time.RFC3339))
+				rep.Reminders++
+			}
+		}
+	}
+
+	pool, err := s.pg.ListUnreplacedRevocations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load revoked scripts: %w", err)
+	}
+	for _, a := range pool {
+		r, err := s.reassign(ctx, a)
+		if err != nil {
+			logrus.Warnf("reassign script %s: %v", a.ScriptID, err)
+			rep.WaitingPool = append(rep.WaitingPool, a.ScriptID)
+			continue
+		}
+		if r == nil {
+			rep.WaitingPool = append(rep.WaitingPool, a.ScriptID)
+			continue
+		}
+		rep.Reassigned = append(rep.Reassigned, *r)
+	}
+	return rep, nil
+}
+
+// reassign hands a revoked script round to the least loaded evaluator of the
+// course who has never held the script and has no conflict of interest with
+// it. Returns nil, nil if nobody is available.
+func (s *Service) reassign(ctx context.Context, revoked db.AssignedScriptRow) (*Reassignment, error) {
+	to, err := s.conflicts.PickEvaluator(ctx, revoked.CourseID, revoked.Semester, revoked.ScriptID)
+	if err != nil || to == "" {
+		return nil, err
+	}
+	id, err := s.pg.Reassign(ctx, revoked, to, s.DueAt(0))
+	if err != nil {
+		return nil, err
+	}
+	s.event(ctx, revoked, EventReassigned, to)
+	return &Reassignment{ScriptID: revoked.ScriptID, ValuationRound: revoked.ValuationRound, From: revoked.Evaluator, To: to, AssignmentID: id}, nil
+}
+
+// List returns assignments for the authority view.
+func (s *Service) List(ctx context.Context, status, courseID, evaluatorID string, overdueOnly bool) ([]Assignment, error) {
+	rows, err := s.pg.ListAssignments(ctx, status, strings.ToUpper(strings.TrimSpace(courseID)), evaluatorID, overdueOnly)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	out := make([]Assignment, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, toAssignment(r, now))
+	}
+	return out, nil
+}
+
+// Pool returns revoked script rounds still waiting for an evaluator.
+func (s *Service) Pool(ctx context.Context) ([]Assignment, error) {
+	rows, err := s.pg.ListUnreplacedRevocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	out := make([]Assignment, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, toAssignment(r, now))
+	}
+	return out, nil
+}
+
+// Extend moves the deadline of an open assignment.
+func (s *Service) Extend(ctx context.Context, id int64, dueAt time.Time, by string) error {
+	if !dueAt.After(time.Now()) {
+		return fmt.Errorf("new deadline must be in the future")
+	}
+	a, err := s.open(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.pg.SetAssignmentDue(ctx, id, dueAt); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotOpen
+		}
+		return err
+	}
+	s.event(ctx, *a, EventExtended, fmt.Sprintf("%s by %s", dueAt.UTC().Format(time.RFC3339), by))
+	return nil
+}
+
+// Revoke withdraws an open assignment on the authority's decision and
+// reassigns the script straight away when an evaluator is available.
+func (s *Service) Revoke(ctx context.Context, id int64, by, reason string) (*Reassignment, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+	a, err := s.open(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.pg.RevokeAssignment(ctx, id, reason); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotOpen
+		}
+		return nil, err
+	}
+	s.event(ctx, *a, EventRevoked, fmt.Sprintf("%s (by %s)", reason, by))
+	return s.reassign(ctx, *a)
+}
+
+// Events returns assignment events filtered by assignment and evaluator.
+func (s *Service) Events(ctx context.Context, assignmentID int64, evaluatorID string) ([]db.AssignmentEventRow, error) {
+	return s.pg.ListAssignmentEvents(ctx, assignmentID, evaluatorID, 200)
+}
+
+func (s *Service) open(ctx context.Context, id int64) (*db.AssignedScriptRow, error) {
+	a, err := s.pg.GetAssignment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, fmt.Errorf("assignment %d not found", id)
+	}
+	if a.Status != "assigned" && a.Status != "in_progress" {
+		return nil, ErrNotOpen
+	}
+	return a, nil
+}
+
+// event records an assignment event (best-effort).
+func (s *Service) event(ctx context.Context, a db.AssignedScriptRow, kind, detail string) {
+	if _, err := s.pg.InsertAssignmentEvent(ctx, db.AssignmentEventRow{AssignmentID: a.ID, EvaluatorID: a.Evaluator, Kind: kind, Detail: detail}); err != nil {
+		logrus.Warnf("record %s event for assignment %d: %v", kind, a.ID, err)
+	}
+}
+
+func toAssignment(r db.AssignedScriptRow, now time.Time) Assignment {
+	open := r.Status == "assigned" || r.Status == "in_progress"
+	return Assignment{
+		ID:             r.ID,
+		ScriptID:       r.ScriptID,
+		EvaluatorID:    r.Evaluator,
+		CourseID:       r.CourseID,
+		Semester:       r.Semester,
+		AcademicYear:   r.AcademicYear,
+		ValuationRound: r.ValuationRound,
+		Status:         r.Status,
+		AssignedAt:     r.AssignedAt,
+		DueAt:          r.DueAt,
+		Overdue:        open && r.DueAt != nil && r.DueAt.Before(now),
+		RevokedAt:      r.RevokedAt,
+		RevokeReason:   r.RevokeReason,
+		ReassignedFrom: r.ReassignedFrom,
+	}
+}
@@ -0,0 +1,106 @@
+package eligibility
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler exposes the eligibility registry.
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GET /api/v1/admin/eligibility?evaluator_id=
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.svc.List(r.Context(), r.URL.Query().Get("evaluator_id"))
+	if err != nil {
+		http.Error(w, "failed to load eligibility", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, entries, http.StatusOK)
+}
+
+// POST /api/v1/admin/eligibility
+// Body: an Entry. id 0 creates it, otherwise the entry is updated.
+func (h *Handler) Save(w http.ResponseWriter, r *http.Request) {
+	var e Entry
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if _, err := h.svc.Save(r.Context(), &e); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "entry not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, e, http.StatusOK)
+}
+
+// DELETE /api/v1/admin/eligibility/{id}
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		http.Error(w, "delete failed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "deleted"}, http.StatusOK)
+}
+
+// GET /api/v1/authority/eligibility/check?evaluator_id=&course_id=
+// GET /api/v1/evaluator/eligibility/check?evaluator_id=&course_id=
+// Whether the evaluator may evaluate the course today, and why.
+func (h *Handler) Check(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("evaluator_id") == "" || q.Get("course_id") == "" {
+		http.Error(w, "evaluator_id and course_id are required", http.StatusBadRequest)
+		return
+	}
+	d, err := h.svc.Check(r.Context(), q.Get("evaluator_id"), q.Get("course_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, d, http.StatusOK)
+}
+
+// GET /api/v1/evaluator/eligibility?evaluator_id=
+// The evaluator's own registrations, to pick courses to request.
+func (h *Handler) Mine(w http.ResponseWriter, r *http.Request) {
+	eid := r.URL.Query().Get("evaluator_id")
+	if eid == "" {
+		http.Error(w, "missing evaluator_id", http.StatusBadRequest)
+		return
+	}
+	h.List(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func RegisterEligibilityRoutes(r *mux.Router, svc *Service) {
+	h := NewHandler(svc)
+	r.HandleFunc("/admin/eligibility", h.List).Methods("GET")
+	r.HandleFunc("/admin/eligibility", h.Save).Methods("POST")
+	r.HandleFunc("/admin/eligibility/{id}", h.Delete).Methods("DELETE")
+	r.HandleFunc("/authority/eligibility/check", h.Check).Methods("GET")
+	r.HandleFunc("/evaluator/eligibility", h.Mine).Methods("GET")
+	r.HandleFunc("/evaluator/eligibility/check", h.Check).Methods("GET")
+}
@@ -0,0 +1,169 @@
+package eligibility
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Experience levels, lowest first.
+const (
+	LevelJunior = "junior"
+	LevelSenior = "senior"
+	LevelChief  = "chief"
+)
+
+// dateLayout is the format of validity dates in the API.
+const dateLayout = "2006-01-02"
+
+// Entry records that an evaluator is qualified for one course or for every
+// course of a department, from ValidFrom through ValidUntil (empty = no end).
+type Entry struct {
+	ID              int64  `json:"id"`
+	EvaluatorID     string `json:"evaluator_id"`
+	CourseID        string `json:"course_id,omitempty"`
+	Department      string `json:"department,omitempty"`
+	ExperienceLevel string `json:"experience_level"`
+	ExperienceYears int    `json:"experience_years"`
+	ValidFrom       string `json:"valid_from"`            // YYYY-MM-DD; today when empty
+	ValidUntil      string `json:"valid_until,omitempty"` // YYYY-MM-DD, inclusive
+	Note            string `json:"note,omitempty"`
+	CreatedBy       string `json:"created_by,omitempty"`
+}
+
+// Normalize trims the entry, upper-cases the course and fills defaults.
+func (e *Entry) Normalize() {
+	e.EvaluatorID = strings.TrimSpace(e.EvaluatorID)
+	e.CourseID = strings.ToUpper(strings.TrimSpace(e.CourseID))
+	e.Department = strings.TrimSpace(e.Department)
+	e.ExperienceLevel = strings.ToLower(strings.TrimSpace(e.ExperienceLevel))
+	e.ValidFrom = strings.TrimSpace(e.ValidFrom)
+	e.ValidUntil = strings.TrimSpace(e.ValidUntil)
+	e.Note = strings.TrimSpace(e.Note)
+	e.CreatedBy = strings.TrimSpace(e.CreatedBy)
+	if e.ExperienceLevel == "" {
+		e.ExperienceLevel = LevelJunior
+	}
+	if e.ValidFrom == "" {
+		e.ValidFrom = time.Now().Format(dateLayout)
+	}
+}
+
+// Validate checks the scope, level and validity period.
+func (e *Entry) Validate() error {
+	if e.EvaluatorID == "" {
+		return fmt.Errorf("evaluator_id is required")
+	}
+	if (e.CourseID == "") == (e.Department == "") {
+		return fmt.Errorf("exactly one of course_id and department is required")
+	}
+	if levelRank(e.ExperienceLevel) == 0 {
+		return fmt.Errorf("experience_level must be %q, %q or %q", LevelJunior, LevelSenior, LevelChief)
+	}
+	if e.ExperienceYears < 0 {
+		return fmt.Errorf("experience_years must be >= 0")
+	}
+	from, err := time.Parse(dateLayout, e.ValidFrom)
+	if err != nil {
+		return fmt.Errorf("valid_from must be a date (YYYY-MM-DD)")
+	}
+	if e.ValidUntil != "" {
+		until, err := time.Parse(dateLayout, e.ValidUntil)
+		if err != nil {
+			return fmt.Errorf("valid_until must be a date (YYYY-MM-DD)")
+		}
+		if until.Before(from) {
+			return fmt.Errorf("valid_until is before valid_from")
+		}
+	}
+	return nil
+}
+
+// covers reports whether the entry's scope includes the course.
+func (e *Entry) covers(courseID, department string) bool {
+	if e.CourseID != "" {
+		return strings.EqualFold(e.CourseID, courseID)
+	}
+	return department != "" && strings.EqualFold(e.Department, department)
+}
+
+// validOn reports whether day (YYYY-MM-DD) falls in the validity period.
+// The layout sorts lexically, so strings compare as dates.
+func (e *Entry) validOn(day string) bool {
+	return e.ValidFrom <= day && (e.ValidUntil == "" || day <= e.ValidUntil)
+}
+
+func levelRank(level string) int {
+	switch level {
+	case LevelJunior:
+		return 1
+	case LevelSenior:
+		return 2
+	case LevelChief:
+		return 3
+	}
+	return 0
+}
+
+// Decision is whether an evaluator may evaluate a course, and why.
+type Decision struct {
+	EvaluatorID string `json:"evaluator_id"`
+	CourseID    string `json:"course_id"`
+	Eligible    bool   `json:"eligible"`
+	Reason      string `json:"reason"`
+	Entry       *Entry `json:"entry,omitempty"` // the entry that qualifies, or the closest lapsed one
+}
+
+// decide picks the entry that qualifies the evaluator for the course on the
+// given day: a course entry beats a department entry, then the higher level.
+// Without one it explains the nearest miss: an entry not yet valid or lapsed.
+func decide(entries []Entry, evaluatorID, courseID, department string, now time.Time) *Decision {
+	d := &Decision{EvaluatorID: evaluatorID, CourseID: courseID}
+	day := now.Format(dateLayout)
+	var best, miss *Entry
+	for i := range entries {
+		e := &entries[i]
+		if e.EvaluatorID != evaluatorID || !e.covers(courseID, department) {
+			continue
+		}
+		if !e.validOn(day) {
+			if miss == nil {
+				miss = e
+			}
+			continue
+		}
+		if best == nil || better(e, best) {
+			best = e
+		}
+	}
+	switch {
+	case best != nil:
+		d.Eligible, d.Entry = true, best
+		scope := "course " + best.CourseID
+		if best.CourseID == "" {
+			scope = "department " + best.Department
+		}
+		d.Reason = fmt.Sprintf("registered for %s as %s", scope, best.ExperienceLevel)
+	case miss != nil && miss.ValidFrom > day:
+		d.Entry = miss
+		d.Reason = fmt.Sprintf("registration for %s starts on %s", courseID, miss.ValidFrom)
+	case miss != nil:
+		d.Entry = miss
+		d.Reason = fmt.Sprintf("registration for %s expired on %s", courseID, miss.ValidUntil)
+	case department != "":
+		d.Reason = fmt.Sprintf("not registered for course %s or department %s", courseID, department)
+	default:
+		d.Reason = fmt.Sprintf("not registered for course %s", courseID)
+	}
+	return d
+}
+
+func better(a, b *Entry) bool {
+	if (a.CourseID != "") != (b.CourseID != "") {
+		return a.CourseID != ""
+	}
+	if levelRank(a.ExperienceLevel) != levelRank(b.ExperienceLevel) {
+		return levelRank(a.ExperienceLevel) > levelRank(b.ExperienceLevel)
+	}
+	return a.ExperienceYears > b.ExperienceYears
+}
@@ -0,0 +1,122 @@
+package eligibility
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"digital-eval-system/services/go-node/internal/course"
+	"digital-eval-system/services/go-node/internal/db"
+)
+
+// ErrIneligible is returned when an evaluator is not registered for a course.
+var ErrIneligible = errors.New("evaluator is not eligible for this course")
+
+// Service keeps the registry of which evaluators may evaluate which courses.
+type Service struct {
+	pg      *db.PostgresDB
+	courses *course.Service
+}
+
+func NewService(pg *db.PostgresDB, courseSvc *course.Service) *Service {
+	return &Service{pg: pg, courses: courseSvc}
+}
+
+// List returns the registry, or with an evaluator only their entries.
+func (s *Service) List(ctx context.Context, evaluatorID string) ([]Entry, error) {
+	rows, err := s.pg.ListEligibility(ctx, strings.TrimSpace(evaluatorID))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Entry, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, fromRow(r))
+	}
+	return out, nil
+}
+
+// Save validates and stores an entry; ID 0 creates it.
+func (s *Service) Save(ctx context.Context, e *Entry) (int64, error) {
+	e.Normalize()
+	if err := e.Validate(); err != nil {
+		return 0, err
+	}
+	from, _ := time.Parse(dateLayout, e.ValidFrom)
+	row := db.EligibilityRow{
+		ID:              e.ID,
+		EvaluatorID:     e.EvaluatorID,
+		CourseID:        e.CourseID,
+		Department:      e.Department,
+		ExperienceLevel: e.ExperienceLevel,
+		ExperienceYears: e.ExperienceYears,
+		ValidFrom:       from,
+		Note:            e.Note,
+		CreatedBy:       e.CreatedBy,
+	}
+	if e.ValidUntil != "" {
+		until, _ := time.Parse(dateLayout, e.ValidUntil)
+		row.ValidUntil = sql.NullTime{Time: until, Valid: true}
+	}
+	id, err := s.pg.SaveEligibility(ctx, row)
+	if err != nil {
+		return 0, err
+	}
+	e.ID = id
+	return id, nil
+}
+
+// Delete removes an entry.
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	return s.pg.DeleteEligibility(ctx, id)
+}
+
+// Check decides whether an evaluator may evaluate a course today. Department
+// entries apply through the course's department in the catalog.
+func (s *Service) Check(ctx context.Context, evaluatorID, courseID string) (*Decision, error) {
+	evaluatorID, courseID = strings.TrimSpace(evaluatorID), strings.ToUpper(strings.TrimSpace(courseID))
+	entries, err := s.List(ctx, evaluatorID)
+	if err != nil {
+		return nil, fmt.Errorf("load eligibility: %w", err)
+	}
+	department := ""
+	if co, err := s.courses.Get(ctx, courseID); err != nil {
+		return nil, fmt.Errorf("load course: %w", err)
+	} else if co != nil {
+		department = co.Department
+	}
+	return decide(entries, evaluatorID, courseID, department, time.Now()), nil
+}
+
+// Require returns ErrIneligible, with the reason, unless the evaluator may
+// evaluate the course today.
+func (s *Service) Require(ctx context.Context, evaluatorID, courseID string) error {
+	d, err := s.Check(ctx, evaluatorID, courseID)
+	if err != nil {
+		return err
+	}
+	if !d.Eligible {
+		return fmt.Errorf("%w: %s", ErrIneligible, d.Reason)
+	}
+	return nil
+}
+
+func fromRow(r db.EligibilityRow) Entry {
+	e := Entry{
+		ID:              r.ID,
+		EvaluatorID:     r.EvaluatorID,
+		CourseID:        r.CourseID,
+		Department:      r.Department,
+		ExperienceLevel: r.ExperienceLevel,
+		ExperienceYears: r.ExperienceYears,
+		ValidFrom:       r.ValidFrom.Format(dateLayout),
+		Note:            r.Note,
+		CreatedBy:       r.CreatedBy,
+	}
+	if r.ValidUntil.Valid {
+		e.ValidUntil = r.ValidUntil.Time.Format(dateLayout)
+	}
+	return e
+}
@@ -0,0 +1,206 @@
+package evaluator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"digital-eval-system/services/go-node/internal/db"
+)
+
+// ErrNoDraft is returned when an assignment has no saved draft.
+var ErrNoDraft = errors.New("no draft saved for this script")
+
+// Draft is an evaluator's partially entered marks for an assigned script.
+type Draft struct {
+	AssignmentID int64         `json:"assignment_id"`
+	ScriptID     string        `json:"script_id"`
+	CourseID     string        `json:"course_id"`
+	Semester     string        `json:"semester"`
+	AcademicYear string        `json:"academic_year"`
+	Payload      SubmitPayload `json:"payload"`
+	Version      int           `json:"version"`
+	SavedAt      time.Time     `json:"saved_at"`
+}
+
+// DraftInput is the body of a draft save. Version is the draft version the
+// client last loaded (0 to overwrite unconditionally).
+type DraftInput struct {
+	SubmitPayload
+	Version int `json:"version"`
+}
+
+// assignmentOpen reports whether an assignment still accepts marks.
+func assignmentOpen(status string) bool {
+	return status == "assigned" || status == "in_progress"
+}
+
+// openAssignment returns the evaluator's assignment of a script if it still
+// accepts marks.
+func (s *SubmitService) openAssignment(ctx context.Context, scriptID, evaluatorID string) (*db.AssignedScriptRow, error) {
+	if scriptID == "" || evaluatorID == "" {
+		return nil, fmt.Errorf("script_id and evaluator_id are required")
+	}
+	a, err := s.pg.GetAssignmentForEvaluator(ctx, scriptID, evaluatorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch assignment: %w", err)
+	}
+	if a == nil || !assignmentOpen(a.Status) {
+		return nil, fmt.Errorf("script not assigned to evaluator or already evaluated")
+	}
+	return a, nil
+}
+
+// SaveDraft autosaves partial marks for an assigned script and moves the
+// assignment to in_progress. Marks are only checked for shape here; the full
+// exam pattern rules apply on submit.
+func (s *SubmitService) SaveDraft(ctx context.Context, in DraftInput) (*Draft, error) {
+	scriptID, err := s.blind.Resolve(ctx, in.ScriptID)
+	if err != nil {
+		return nil, err
+	}
+	in.ScriptID = scriptID
+	a, err := s.openAssignment(ctx, in.ScriptID, in.EvaluatorID)
+	if err != nil {
+		return nil, err
+	}
+	p := in.SubmitPayload
+	p.CourseID = a.CourseID
+	p.Semester = a.Semester
+	p.AcademicYear = a.AcademicYear
+	if err := s.checkDraftMarks(ctx, a.CourseID, p.MarksScored); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.pg.SaveDraft(ctx, *a, raw, in.Version); err != nil {
+		return nil, err
+	}
+	return s.GetDraft(ctx, in.ScriptID, in.EvaluatorID)
+}
+
+// checkDraftMarks rejects marks that can never become valid under the
+// course's exam pattern: too many entries, negative or above a maximum.
+func (s *SubmitService) checkDraftMarks(ctx context.Context, courseID string, marks []int) error {
+	co, err := s.courses.Get(ctx, courseID)
+	if err != nil || co == nil {
+		return err
+	}
+	pattern, err := s.patterns.Resolve(ctx, co.ExamPattern)
+	if err != nil {
+		return err
+	}
+	leaves := pattern.Leaves()
+	if len(marks) > len(leaves) {
+		return fmt.Errorf("pattern %s has %d questions, got %d marks", pattern.Code, len(leaves), len(marks))
+	}
+	for i, m := range marks {
+		if m < 0 || (leaves[i].MaxMarks > 0 && m > leaves[i].MaxMarks) {
+			return fmt.Errorf("%s: marks %d out of range 0..%d", leaves[i].Label, m, leaves[i].MaxMarks)
+		}
+	}
+	return nil
+}
+
+// GetDraft returns the saved draft for an assigned script (ErrNoDraft if none).
+// scriptID may be the script's dummy number.
+func (s *SubmitService) GetDraft(ctx context.Context, scriptID, evaluatorID string) (*Draft, error) {
+	scriptID, err := s.blind.Resolve(ctx, scriptID)
+	if err != nil {
+		return nil, err
+	}
+	a, err := s.pg.GetAssignmentForEvaluator(ctx, scriptID, evaluatorID)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, ErrNoDraft
+	}
+	row, err := s.pg.GetDraft(ctx, a.ID)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return nil, ErrNoDraft
+	}
+	return s.draftFromRow(ctx, row)
+}
+
+// ListDrafts returns an evaluator's saved drafts, most recent first.
+func (s *SubmitService) ListDrafts(ctx context.Context, evaluatorID string) ([]Draft, error) {
+	rows, err := s.pg.ListDraftsByEvaluator(ctx, evaluatorID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Draft, 0, len(rows))
+	for i := range rows {
+		d, err := s.draftFromRow(ctx, &rows[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *d)
+	}
+	return out, nil
+}
+
+// DiscardDraft deletes a draft and returns the assignment to assigned.
+func (s *SubmitService) DiscardDraft(ctx context.Context, scriptID, evaluatorID string) error {
+	scriptID, err := s.blind.Resolve(ctx, scriptID)
+	if err != nil {
+		return err
+	}
+	a, err := s.openAssignment(ctx, scriptID, evaluatorID)
+	if err != nil {
+		return err
+	}
+	return s.pg.DeleteDraft(ctx, a.ID, true)
+}
+
+// SubmitDraft submits the saved draft as the evaluation.
+func (s *SubmitService) SubmitDraft(ctx context.Context, scriptID, evaluatorID string) (string, error) {
+	d, err := s.GetDraft(ctx, scriptID, evaluatorID)
+	if err != nil {
+		return "", err
+	}
+	p := d.Payload
+	p.ScriptID = scriptID
+	p.EvaluatorID = evaluatorID
+	return s.SubmitEvaluation(ctx, p)
+}
+
+// clearDraft removes the draft of a submitted assignment (best-effort).
+func (s *SubmitService) clearDraft(ctx context.Context, assignmentID int64) {
+	if err := s.pg.DeleteDraft(ctx, assignmentID, false); err != nil {
+		logrus.Warnf("failed to clear draft of assignment %d: %v", assignmentID, err)
+	}
+}
+
+// draftFromRow builds the evaluator's view of a draft, naming the script by
+// its dummy number in blind mode.
+func (s *SubmitService) draftFromRow(ctx context.Context, r *db.DraftRow) (*Draft, error) {
+	d := &Draft{
+		AssignmentID: r.AssignmentID,
+		ScriptID:     r.ScriptID,
+		CourseID:     r.CourseID,
+		Semester:     r.Semester,
+		AcademicYear: r.AcademicYear,
+		Version:      r.Version,
+		SavedAt:      r.SavedAt,
+	}
+	if err := json.Unmarshal(r.Payload, &d.Payload); err != nil {
+		return nil, fmt.Errorf("draft of assignment %d unreadable: %w", r.AssignmentID, err)
+	}
+	label, err := s.blind.Label(ctx, r.ScriptID, r.CourseID, r.Semester)
+	if err != nil {
+		return nil, err
+	}
+	d.ScriptID = label
+	d.Payload.ScriptID = label
+	return d, nil
+}
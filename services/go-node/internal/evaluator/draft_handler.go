@@ -0,0 +1,90 @@
+package evaluator
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"digital-eval-system/services/go-node/internal/db"
+)
+
+// GET /api/v1/evaluator/drafts?evaluator_id=...
+func (h *SubmitHandler) ListDrafts(w http.ResponseWriter, r *http.Request) {
+	eid := r.URL.Query().Get("evaluator_id")
+	if eid == "" {
+		http.Error(w, "missing evaluator_id", http.StatusBadRequest)
+		return
+	}
+	drafts, err := h.svc.ListDrafts(r.Context(), eid)
+	if err != nil {
+		http.Error(w, "failed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, drafts, http.StatusOK)
+}
+
+// GET /api/v1/evaluator/drafts/{script_id}?evaluator_id=...
+func (h *SubmitHandler) GetDraft(w http.ResponseWriter, r *http.Request) {
+	d, err := h.svc.GetDraft(r.Context(), mux.Vars(r)["script_id"], r.URL.Query().Get("evaluator_id"))
+	if err != nil {
+		writeDraftError(w, err)
+		return
+	}
+	writeJSON(w, d, http.StatusOK)
+}
+
+// PUT /api/v1/evaluator/drafts/{script_id}
+// Body: submit payload plus "version" (the draft version last loaded, 0 for none).
+func (h *SubmitHandler) SaveDraft(w http.ResponseWriter, r *http.Request) {
+	var in DraftInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	in.ScriptID = mux.Vars(r)["script_id"]
+	d, err := h.svc.SaveDraft(r.Context(), in)
+	if err != nil {
+		writeDraftError(w, err)
+		return
+	}
+	writeJSON(w, d, http.StatusOK)
+}
+
+// DELETE /api/v1/evaluator/drafts/{script_id}?evaluator_id=...
+func (h *SubmitHandler) DiscardDraft(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.DiscardDraft(r.Context(), mux.Vars(r)["script_id"], r.URL.Query().Get("evaluator_id")); err != nil {
+		writeDraftError(w, err)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "discarded"}, http.StatusOK)
+}
+
+// POST /api/v1/evaluator/drafts/{script_id}/submit?evaluator_id=...
+// Submits the saved draft as the evaluation.
+func (h *SubmitHandler) SubmitDraft(w http.ResponseWriter, r *http.Request) {
+	blockHash, err := h.svc.SubmitDraft(r.Context(), mux.Vars(r)["script_id"], r.URL.Query().Get("evaluator_id"))
+	if err != nil {
+		if errors.Is(err, ErrNoDraft) {
+			writeDraftError(w, err)
+			return
+		}
+		logrus.Warnf("submit draft failed: %v", err)
+		http.Error(w, "submit failed: "+err.Error(), submitStatus(err))
+		return
+	}
+	writeJSON(w, map[string]string{"block_hash": blockHash}, http.StatusOK)
+}
+
+func writeDraftError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNoDraft):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, db.ErrDraftConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
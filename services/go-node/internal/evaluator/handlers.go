@@ -2,11 +2,14 @@ package evaluator
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
+
+	"digital-eval-system/services/go-node/internal/eligibility"
 )
 
 // Handler exposes evaluator endpoints
@@ -27,10 +30,15 @@ func (h *Handler) CreateRequest(w http.ResponseWriter, r *http.Request) {
 	id, err := h.svc.CreateRequest(r.Context(), payload.EvaluatorID, payload.CourseID, payload.Semester, payload.AcademicYear, payload.Description)
 	if err != nil {
 		logrus.Warnf("create request failed: %v", err)
-		if strings.Contains(err.Error(), "uq_eval_request") {
+		if strings.Contains(err.Error(), "uq_eval_request") || strings.Contains(err.Error(), "pending request exists") {
 			http.Error(w, "request already exists", http.StatusConflict)
 			return
 		}
+		if errors.Is(err, eligibility.ErrIneligible) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		http.Error(w, "create request failed: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 	writeJSON(w, map[string]interface{}{"request_id": id}, http.StatusCreated)
@@ -69,6 +77,7 @@ func (h *Handler) ListAssigned(w http.ResponseWriter, r *http.Request) {
 }
 
 // GET /api/v1/evaluator/script/{script_id}
+// script_id may be the dummy number shown in blind mode.
 func (h *Handler) GetScript(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	sid := vars["script_id"]
@@ -76,7 +85,7 @@ func (h *Handler) GetScript(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "missing script_id", http.StatusBadRequest)
 		return
 	}
-	meta, err := h.svc.GetScriptMetadata(r.Context(), sid)
+	meta, err := h.svc.GetScript(r.Context(), sid)
 	if err != nil {
 		http.Error(w, "not found", http.StatusNotFound)
 		return
@@ -93,7 +102,7 @@ func (h *Handler) Submit(w http.ResponseWriter, r *http.Request) {
 	}
 	blockHash, err := h.svc.SubmitEvaluation(r.Context(), payload)
 	if err != nil {
-		http.Error(w, "submit failed: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "submit failed: "+err.Error(), submitStatus(err))
 		return
 	}
 	writeJSON(w, map[string]string{"block_hash": blockHash}, http.StatusOK)
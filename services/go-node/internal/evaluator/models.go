@@ -12,10 +12,20 @@ type SubmitPayload struct {
 	CourseID           string                 `json:"course_id"`
 	Semester           string                 `json:"semester,omitempty"`
 	AcademicYear       string                 `json:"academic_year,omitempty"`
-	CourseCredits      int                    `json:"course_credits"`
+	Attendance         string                 `json:"attendance,omitempty"`    // present (default) | absent | withheld
+	AnnotatedCID       string                 `json:"annotated_cid,omitempty"` // CID returned by /evaluator/upload
+	Remarks            []QuestionRemark       `json:"remarks,omitempty"`       // per-question explanation of the marks
+	Annotations        []Annotation           `json:"annotations,omitempty"`   // ticks, crosses and comments on pages
 	AdditionalMetadata map[string]interface{} `json:"additional_metadata"`
 }
 
+// Attendance values accepted on SubmitPayload.
+const (
+	AttendancePresent  = "present"
+	AttendanceAbsent   = "absent"
+	AttendanceWithheld = "withheld"
+)
+
 // you may use []byte or json.RawMessage depending on your code
 type RequestCreate struct {
 	EvaluatorID  string `json:"evaluator_id"`
@@ -0,0 +1,138 @@
+package evaluator
+
+import (
+	"context"
+	"fmt"
+
+	"digital-eval-system/services/go-node/internal/course"
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/exampattern"
+	"digital-eval-system/services/go-node/internal/grading"
+)
+
+// Stage names of the default submit pipeline, in order.
+const (
+	StageSchema      = "schema"
+	StageAssignment  = "assignment"
+	StageAnnotation  = "annotation"
+	StageDuplicate   = "duplicate"
+	StageValidator   = "validator"
+	StageScoring     = "scoring"
+	StageValuation   = "valuation"
+	StageChain       = "chain"
+	StagePersist     = "persist"
+	StageCloseRounds = "close_rounds"
+)
+
+// Submission carries one evaluation through the submit pipeline. Each stage
+// reads what earlier stages resolved and adds its own results.
+type Submission struct {
+	Payload    SubmitPayload
+	Course     *course.Course
+	Pattern    *exampattern.Pattern
+	Assignment *db.AssignedScriptRow
+	Annotated  *db.AnnotatedScriptRow // evaluator's annotated copy, nil if none was uploaded
+	StudentUSN string
+	Scheme     *grading.Scheme
+	Present    bool // marks were given: not absent or withheld
+	Score      int
+	Result     string
+	Marks      map[string]interface{} // the marks document written to the block
+	MarksJSON  []byte
+	BlockHash  string
+	Pending    []string // outbox items of the block, applied by the persist stage
+	// Done is set by a stage that completed the submission itself, such as
+	// a valuation round under double valuation; the remaining stages are skipped.
+	Done bool
+}
+
+// Stage is one step of the submit pipeline.
+type Stage interface {
+	Name() string
+	Run(ctx context.Context, sub *Submission) error
+}
+
+type stageFunc struct {
+	name string
+	fn   func(context.Context, *Submission) error
+}
+
+func (s stageFunc) Name() string                                   { return s.name }
+func (s stageFunc) Run(ctx context.Context, sub *Submission) error { return s.fn(ctx, sub) }
+
+// StageFunc turns a function into a Stage.
+func StageFunc(name string, fn func(context.Context, *Submission) error) Stage {
+	return stageFunc{name: name, fn: fn}
+}
+
+// StageError reports the stage that rejected a submission. Its message is
+// the stage's own.
+type StageError struct {
+	Stage string
+	Err   error
+}
+
+func (e *StageError) Error() string { return e.Err.Error() }
+func (e *StageError) Unwrap() error { return e.Err }
+
+// Pipeline runs submit stages in order.
+type Pipeline struct {
+	stages []Stage
+}
+
+func NewPipeline(stages ...Stage) *Pipeline {
+	return &Pipeline{stages: stages}
+}
+
+// Stages lists the stage names in order.
+func (p *Pipeline) Stages() []string {
+	out := make([]string, 0, len(p.stages))
+	for _, st := range p.stages {
+		out = append(out, st.Name())
+	}
+	return out
+}
+
+func (p *Pipeline) index(name string) (int, error) {
+	for i, st := range p.stages {
+		if st.Name() == name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("submit pipeline has no stage %q", name)
+}
+
+// Replace swaps the stage with the given name for st.
+func (p *Pipeline) Replace(name string, st Stage) error {
+	i, err := p.index(name)
+	if err != nil {
+		return err
+	}
+	p.stages[i] = st
+	return nil
+}
+
+// InsertBefore adds st in front of the stage with the given name.
+func (p *Pipeline) InsertBefore(name string, st Stage) error {
+	i, err := p.index(name)
+	if err != nil {
+		return err
+	}
+	p.stages = append(p.stages[:i], append([]Stage{st}, p.stages[i:]...)...)
+	return nil
+}
+
+// Run passes a payload through every stage, stopping at the first error or
+// once a stage marks the submission done.
+func (p *Pipeline) Run(ctx context.Context, payload SubmitPayload) (*Submission, error) {
+	sub := &Submission{Payload: payload}
+	for _, st := range p.stages {
+		if sub.Done {
+			break
+		}
+		if err := st.Run(ctx, sub); err != nil {
+			return sub, &StageError{Stage: st.Name(), Err: err}
+		}
+	}
+	return sub, nil
+}
@@ -0,0 +1,752 @@
+package evaluator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"digital-eval-system/services/go-node/internal/block"
+	"digital-eval-system/services/go-node/internal/course"
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/exampattern"
+	"digital-eval-system/services/go-node/internal/grading"
+	"digital-eval-system/services/go-node/internal/outbox"
+	"digital-eval-system/services/go-node/internal/pybridge"
+	"digital-eval-system/services/go-node/internal/storage"
+)
+
+// In-memory fakes of the stores the stages depend on.
+
+type fakeScripts map[string]string // dummy number -> script id
+
+func (f fakeScripts) Resolve(_ context.Context, ref string) (string, error) {
+	if ref == "DUMMY-REVOKED" {
+		return "", errors.New("unknown dummy number")
+	}
+	if id, ok := f[ref]; ok {
+		return id, nil
+	}
+	return ref, nil
+}
+
+type fakeCourses map[string]*course.Course
+
+func (f fakeCourses) Get(_ context.Context, code string) (*course.Course, error) {
+	if code == "ERR" {
+		return nil, errors.New("connection refused")
+	}
+	return f[strings.ToUpper(code)], nil
+}
+
+type fakePatterns struct{}
+
+func (fakePatterns) Resolve(_ context.Context, code string) (*exampattern.Pattern, error) {
+	if code != exampattern.DefaultCode {
+		return nil, errors.New("unknown exam pattern " + code)
+	}
+	return exampattern.Default(), nil
+}
+
+type fakeAssignments map[string]*db.AssignedScriptRow // script|evaluator
+
+func (f fakeAssignments) GetAssignmentForEvaluator(_ context.Context, scriptID, evaluatorID string) (*db.AssignedScriptRow, error) {
+	if scriptID == "S-ERR" {
+		return nil, errors.New("connection refused")
+	}
+	return f[scriptID+"|"+evaluatorID], nil
+}
+
+type fakeEvaluations struct {
+	scripts  map[string]bool
+	students map[string]bool // usn|semester|academic year|course
+	err      error
+}
+
+func (f *fakeEvaluations) EvaluationExistsForScript(_ context.Context, scriptID string) (bool, error) {
+	return f.scripts[scriptID], f.err
+}
+
+func (f *fakeEvaluations) EvaluationExistsForStudentSemesterCourse(_ context.Context, usn, semester, academicYear, courseID string) (bool, error) {
+	return f.students[usn+"|"+semester+"|"+academicYear+"|"+courseID], f.err
+}
+
+type fakeGrading struct{}
+
+func (fakeGrading) Resolve(context.Context, string, string) *grading.Scheme { return grading.Default() }
+
+type fakeValidator struct {
+	resp *pybridge.ValidateResponse
+	err  error
+	got  pybridge.EvalValidationInput
+}
+
+func (f *fakeValidator) ValidateEvaluation(_ context.Context, in pybridge.EvalValidationInput) (*pybridge.ValidateResponse, error) {
+	f.got = in
+	return f.resp, f.err
+}
+
+type fakeAnnotations map[int64]*db.AnnotatedScriptRow // assignment id -> upload
+
+func (f fakeAnnotations) GetAnnotatedScript(_ context.Context, assignmentID int64) (*db.AnnotatedScriptRow, error) {
+	if assignmentID == 99 {
+		return nil, errors.New("connection refused")
+	}
+	return f[assignmentID], nil
+}
+
+// memStore keeps the chain and the outbox in memory.
+type memStore struct {
+	storage.Storage
+	mu     sync.Mutex
+	blocks map[string]*block.Block
+	head   string
+	items  map[string][]byte
+}
+
+func newMemStore() *memStore {
+	return &memStore{blocks: map[string]*block.Block{}, items: map[string][]byte{}}
+}
+
+func (m *memStore) Head() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.head, nil
+}
+
+func (m *memStore) GetBlock(hash string) (*block.Block, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if b, ok := m.blocks[hash]; ok {
+		return b, nil
+	}
+	return nil, fmt.Errorf("block %s not found", hash)
+}
+
+func (m *memStore) PutOutbox(id string, v []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[id] = v
+	return nil
+}
+
+func (m *memStore) GetOutbox(id string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.items[id], nil
+}
+
+func (m *memStore) DeleteOutbox(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.items, id)
+	return nil
+}
+
+func (m *memStore) ForEachOutbox(fn func(id string, v []byte) error) error {
+	m.mu.Lock()
+	items := make(map[string][]byte, len(m.items))
+	for id, v := range m.items {
+		items[id] = v
+	}
+	m.mu.Unlock()
+	for id, v := range items {
+		if err := fn(id, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// memChain appends to a memStore after delay, or fails with err.
+type memChain struct {
+	store *memStore
+	err   error
+	delay time.Duration
+}
+
+func (c *memChain) AppendBlock(b *block.Block) (string, error) {
+	time.Sleep(c.delay)
+	if c.err != nil {
+		return "", c.err
+	}
+	h, err := block.BlockHash(b)
+	if err != nil {
+		return "", err
+	}
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	c.store.blocks[h] = b
+	c.store.head = h
+	return h, nil
+}
+
+// applied records the outbox items of one kind it is registered for, and
+// fails while err is set.
+type applied struct {
+	items []*outbox.Item
+	err   error
+}
+
+func (a *applied) apply(_ context.Context, it *outbox.Item) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.items = append(a.items, it)
+	return nil
+}
+
+// newOutbox returns an outbox over an in-memory chain whose items of kind
+// are handed to the returned recorder.
+func newOutbox(kind string) (*outbox.Service, *memStore, *memChain, *applied) {
+	store := newMemStore()
+	ob := outbox.NewService(nil, store, outbox.Default())
+	a := &applied{}
+	ob.Register(kind, a.apply)
+	return ob, store, &memChain{store: store}, a
+}
+
+type fakeAssignmentStore struct {
+	status  map[int64]string
+	drafts  []int64
+	err     error
+	reopens bool
+}
+
+func (f *fakeAssignmentStore) UpdateAssignmentStatus(_ context.Context, id int64, status string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.status[id] = status
+	return nil
+}
+
+func (f *fakeAssignmentStore) DeleteDraft(_ context.Context, assignmentID int64, reopen bool) error {
+	f.drafts = append(f.drafts, assignmentID)
+	f.reopens = f.reopens || reopen
+	return nil
+}
+
+func catalog() fakeCourses {
+	return fakeCourses{
+		"CS501": {CourseCode: "CS501", CourseName: "Compilers", Credits: 4, Semester: "5", Regulation: "R2021", ExamPattern: exampattern.DefaultCode},
+		"CS502": {CourseCode: "CS502", CourseName: "Networks", Credits: 3, Semester: "5", ExamPattern: "NOPE"},
+	}
+}
+
+// passing marks under the default pattern: one question per module, 60/100
+var passMarks = []int{15, 0, 12, 0, 18, 0, 10, 0, 5, 0}
+
+func TestSchemaStage(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload SubmitPayload
+		wantErr string
+		check   func(t *testing.T, sub *Submission)
+	}{
+		{
+			name:    "missing evaluator",
+			payload: SubmitPayload{ScriptID: "S1", CourseID: "CS501"},
+			wantErr: "missing fields",
+		},
+		{
+			name:    "dummy number not resolved",
+			payload: SubmitPayload{ScriptID: "DUMMY-REVOKED", EvaluatorID: "E1", CourseID: "CS501"},
+			wantErr: "unknown dummy number",
+		},
+		{
+			name:    "unknown attendance",
+			payload: SubmitPayload{ScriptID: "S1", EvaluatorID: "E1", CourseID: "CS501", Attendance: "late"},
+			wantErr: `unknown attendance "late"`,
+		},
+		{
+			name:    "negative marks",
+			payload: SubmitPayload{ScriptID: "S1", EvaluatorID: "E1", CourseID: "CS501", MarksScored: []int{3, -1}},
+			wantErr: "marks_scored[1] negative",
+		},
+		{
+			name:    "course not in catalog",
+			payload: SubmitPayload{ScriptID: "S1", EvaluatorID: "E1", CourseID: "XX999"},
+			wantErr: "not in the course catalog",
+		},
+		{
+			name:    "course lookup fails",
+			payload: SubmitPayload{ScriptID: "S1", EvaluatorID: "E1", CourseID: "ERR"},
+			wantErr: "course lookup failed",
+		},
+		{
+			name:    "exam pattern missing",
+			payload: SubmitPayload{ScriptID: "S1", EvaluatorID: "E1", CourseID: "CS502"},
+			wantErr: "unknown exam pattern NOPE",
+		},
+		{
+			name:    "total marks differ from pattern",
+			payload: SubmitPayload{ScriptID: "S1", EvaluatorID: "E1", CourseID: "CS501", TotalMarks: 80},
+			wantErr: "total marks 80 do not match exam pattern",
+		},
+		{
+			name:    "remark on unknown question",
+			payload: SubmitPayload{ScriptID: "S1", EvaluatorID: "E1", CourseID: "CS501", Remarks: []QuestionRemark{{Question: 11, Remark: "?"}}},
+			wantErr: "question 11 out of range 1..10",
+		},
+		{
+			name:    "resolves script, course and pattern",
+			payload: SubmitPayload{ScriptID: "D-0042", EvaluatorID: "E1", CourseID: "cs501", MarksScored: passMarks},
+			check: func(t *testing.T, sub *Submission) {
+				p := sub.Payload
+				if p.ScriptID != "S42" {
+					t.Errorf("script = %q, want S42", p.ScriptID)
+				}
+				if p.CourseID != "CS501" || p.Semester != "5" {
+					t.Errorf("course/semester = %s/%s, want CS501/5", p.CourseID, p.Semester)
+				}
+				if p.TotalMarks != 100 || p.TotalQuestions != 10 || p.MarksPerQuestion != 20 || len(p.MarksAllotted) != 10 {
+					t.Errorf("pattern not applied: %+v", p)
+				}
+				if sub.Course == nil || sub.Pattern == nil || sub.Pattern.Code != exampattern.DefaultCode {
+					t.Errorf("course/pattern not set: %+v %+v", sub.Course, sub.Pattern)
+				}
+			},
+		},
+	}
+	st := &SchemaStage{Scripts: fakeScripts{"D-0042": "S42"}, Courses: catalog(), Patterns: fakePatterns{}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sub := &Submission{Payload: tc.payload}
+			err := st.Run(context.Background(), sub)
+			checkErr(t, err, tc.wantErr)
+			if err == nil && tc.check != nil {
+				tc.check(t, sub)
+			}
+		})
+	}
+}
+
+func TestAssignmentStage(t *testing.T) {
+	st := &AssignmentStage{Assignments: fakeAssignments{
+		"S1|E1": {ID: 1, ScriptID: "S1", Evaluator: "E1", AcademicYear: "2025-26", Status: "assigned"},
+		"S2|E1": {ID: 2, ScriptID: "S2", Evaluator: "E1", AcademicYear: "2025-26", Status: "in_progress"},
+		"S3|E1": {ID: 3, ScriptID: "S3", Evaluator: "E1", Status: "revoked"},
+		"S4|E1": {ID: 4, ScriptID: "S4", Evaluator: "E1", Status: "evaluated"},
+	}}
+	tests := []struct {
+		name     string
+		payload  SubmitPayload
+		wantErr  string
+		wantID   int64
+		wantYear string
+	}{
+		{name: "open assignment fills academic year", payload: SubmitPayload{ScriptID: "S1", EvaluatorID: "E1"}, wantID: 1, wantYear: "2025-26"},
+		{name: "in progress is open", payload: SubmitPayload{ScriptID: "S2", EvaluatorID: "E1", AcademicYear: "2025-26"}, wantID: 2, wantYear: "2025-26"},
+		{name: "other evaluator", payload: SubmitPayload{ScriptID: "S1", EvaluatorID: "E2"}, wantErr: "script not assigned to evaluator"},
+		{name: "revoked", payload: SubmitPayload{ScriptID: "S3", EvaluatorID: "E1"}, wantErr: "assignment of script S3 was revoked"},
+		{name: "already evaluated", payload: SubmitPayload{ScriptID: "S4", EvaluatorID: "E1"}, wantErr: "already evaluated"},
+		{name: "academic year mismatch", payload: SubmitPayload{ScriptID: "S1", EvaluatorID: "E1", AcademicYear: "2024-25"}, wantErr: "academic_year mismatch"},
+		{name: "lookup fails", payload: SubmitPayload{ScriptID: "S-ERR", EvaluatorID: "E1"}, wantErr: "failed to fetch assignment"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sub := &Submission{Payload: tc.payload}
+			err := st.Run(context.Background(), sub)
+			checkErr(t, err, tc.wantErr)
+			if err != nil {
+				return
+			}
+			if sub.Assignment == nil || sub.Assignment.ID != tc.wantID {
+				t.Errorf("assignment = %+v, want id %d", sub.Assignment, tc.wantID)
+			}
+			if sub.Payload.AcademicYear != tc.wantYear {
+				t.Errorf("academic year = %q, want %q", sub.Payload.AcademicYear, tc.wantYear)
+			}
+		})
+	}
+}
+
+func TestDuplicateStage(t *testing.T) {
+	usns := map[string]string{"S1": "1BI21CS001", "S2": "1BI21CS002"}
+	payload := SubmitPayload{CourseID: "CS501", Semester: "5", AcademicYear: "2025-26"}
+	with := func(scriptID string) SubmitPayload { p := payload; p.ScriptID = scriptID; return p }
+	tests := []struct {
+		name    string
+		evals   *fakeEvaluations
+		payload SubmitPayload
+		wantErr string
+		wantUSN string
+	}{
+		{name: "first evaluation", evals: &fakeEvaluations{}, payload: with("S1"), wantUSN: "1BI21CS001"},
+		{name: "script already evaluated", evals: &fakeEvaluations{scripts: map[string]bool{"S1": true}}, payload: with("S1"), wantErr: "already submitted for this script"},
+		{
+			name:    "student already evaluated in course",
+			evals:   &fakeEvaluations{students: map[string]bool{"1BI21CS002|5|2025-26|CS501": true}},
+			payload: with("S2"),
+			wantErr: "an evaluation of this student already exists for course CS501 semester 5",
+		},
+		{
+			name:    "no upload record skips the student check",
+			evals:   &fakeEvaluations{students: map[string]bool{"|5|2025-26|CS501": true}},
+			payload: with("S9"),
+		},
+		{name: "db error", evals: &fakeEvaluations{err: errors.New("timeout")}, payload: with("S1"), wantErr: "db check failed"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			st := &DuplicateStage{Evaluations: tc.evals, USN: func(id string) string { return usns[id] }}
+			sub := &Submission{Payload: tc.payload}
+			err := st.Run(context.Background(), sub)
+			checkErr(t, err, tc.wantErr)
+			if err == nil && sub.StudentUSN != tc.wantUSN {
+				t.Errorf("student usn = %q, want %q", sub.StudentUSN, tc.wantUSN)
+			}
+		})
+	}
+}
+
+func TestScoringStage(t *testing.T) {
+	tests := []struct {
+		name        string
+		attendance  string
+		marks       []int
+		annotated   *db.AnnotatedScriptRow
+		wantErr     string
+		wantScore   int
+		wantResult  string
+		wantPresent bool
+	}{
+		{name: "pass", marks: passMarks, wantScore: 60, wantResult: grading.ResultPass, wantPresent: true},
+		{name: "fail", marks: []int{5, 0, 5, 0, 5, 0, 5, 0, 5, 0}, wantScore: 25, wantResult: grading.ResultFail, wantPresent: true},
+		{name: "absent", attendance: AttendanceAbsent, wantResult: "AB"},
+		{name: "withheld", attendance: AttendanceWithheld, wantResult: "WH"},
+		{name: "marks above question maximum", marks: []int{25, 0, 12, 0, 18, 0, 10, 0, 5, 0}, wantErr: "exceeds maximum 20"},
+		{name: "too few questions attempted", marks: []int{20, 0, 20, 0, 0, 0, 0, 0, 0, 0}, wantErr: "minimum 5 questions"},
+		{
+			name:        "annotated copy recorded",
+			marks:       passMarks,
+			annotated:   &db.AnnotatedScriptRow{CID: "bafy1", SHA256: "abc"},
+			wantScore:   60,
+			wantResult:  grading.ResultPass,
+			wantPresent: true,
+		},
+	}
+	st := &ScoringStage{Grading: fakeGrading{}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sub := &Submission{
+				Payload:   SubmitPayload{ScriptID: "S1", CourseID: "CS501", TotalMarks: 100, Attendance: tc.attendance, MarksScored: tc.marks},
+				Course:    catalog()["CS501"],
+				Pattern:   exampattern.Default(),
+				Annotated: tc.annotated,
+			}
+			err := st.Run(context.Background(), sub)
+			checkErr(t, err, tc.wantErr)
+			if err != nil {
+				return
+			}
+			if sub.Score != tc.wantScore || sub.Result != tc.wantResult || sub.Present != tc.wantPresent {
+				t.Errorf("score/result/present = %d/%s/%v, want %d/%s/%v",
+					sub.Score, sub.Result, sub.Present, tc.wantScore, tc.wantResult, tc.wantPresent)
+			}
+			if sub.Scheme == nil || sub.Marks["course_name"] != "Compilers" {
+				t.Errorf("scheme or marks document missing: %v", sub.Marks)
+			}
+			_, has := sub.Marks["annotated_script"]
+			if has != (tc.annotated != nil) {
+				t.Errorf("annotated_script in marks = %v, want %v", has, tc.annotated != nil)
+			}
+		})
+	}
+}
+
+func TestPipelineRun(t *testing.T) {
+	record := func(ran *[]string, name string, fn func(*Submission) error) Stage {
+		return StageFunc(name, func(_ context.Context, sub *Submission) error {
+			*ran = append(*ran, name)
+			if fn != nil {
+				return fn(sub)
+			}
+			return nil
+		})
+	}
+	failed := errors.New("rejected")
+	tests := []struct {
+		name      string
+		build     func(ran *[]string) *Pipeline
+		wantRan   []string
+		wantStage string // failing stage, empty for success
+	}{
+		{
+			name: "runs every stage",
+			build: func(ran *[]string) *Pipeline {
+				return NewPipeline(record(ran, "a", nil), record(ran, "b", nil), record(ran, "c", nil))
+			},
+			wantRan: []string{"a", "b", "c"},
+		},
+		{
+			name: "stops at the first error",
+			build: func(ran *[]string) *Pipeline {
+				return NewPipeline(record(ran, "a", nil), record(ran, "b", func(*Submission) error { return failed }), record(ran, "c", nil))
+			},
+			wantRan:   []string{"a", "b"},
+			wantStage: "b",
+		},
+		{
+			name: "stops once a stage is done",
+			build: func(ran *[]string) *Pipeline {
+				return NewPipeline(record(ran, "a", func(sub *Submission) error { sub.Done = true; return nil }), record(ran, "b", nil))
+			},
+			wantRan: []string{"a"},
+		},
+		{
+			name: "inserted and replaced stages run in place",
+			build: func(ran *[]string) *Pipeline {
+				p := NewPipeline(record(ran, "a", nil), record(ran, "c", nil))
+				if err := p.InsertBefore("c", record(ran, "b", nil)); err != nil {
+					t.Fatal(err)
+				}
+				if err := p.Replace("a", record(ran, "a2", nil)); err != nil {
+					t.Fatal(err)
+				}
+				if err := p.Replace("missing", record(ran, "x", nil)); err == nil {
+					t.Fatal("replacing an unknown stage should fail")
+				}
+				return p
+			},
+			wantRan: []string{"a2", "b", "c"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var ran []string
+			sub, err := tc.build(&ran).Run(context.Background(), SubmitPayload{ScriptID: "S1"})
+			if sub == nil || sub.Payload.ScriptID != "S1" {
+				t.Fatalf("submission not returned: %+v", sub)
+			}
+			if !reflect.DeepEqual(ran, tc.wantRan) {
+				t.Errorf("ran %v, want %v", ran, tc.wantRan)
+			}
+			var se *StageError
+			switch {
+			case tc.wantStage == "" && err != nil:
+				t.Errorf("unexpected error %v", err)
+			case tc.wantStage != "" && !errors.As(err, &se):
+				t.Errorf("error %v is not a StageError", err)
+			case tc.wantStage != "" && (se.Stage != tc.wantStage || !errors.Is(err, failed)):
+				t.Errorf("stage error %q (%v), want stage %q", se.Stage, err, tc.wantStage)
+			}
+		})
+	}
+}
+
+func TestAnnotationStage(t *testing.T) {
+	st := &AnnotationStage{Annotations: fakeAnnotations{1: {AssignmentID: 1, CID: "bafy1", SHA256: "abc"}}}
+	tests := []struct {
+		name       string
+		assignment int64
+		cid        string
+		wantErr    string
+		wantCID    string
+	}{
+		{name: "upload attached", assignment: 1, wantCID: "bafy1"},
+		{name: "named upload matches", assignment: 1, cid: "bafy1", wantCID: "bafy1"},
+		{name: "no upload", assignment: 2},
+		{name: "named upload belongs elsewhere", assignment: 1, cid: "bafy2", wantErr: "annotated script bafy2 was not uploaded"},
+		{name: "named upload missing", assignment: 2, cid: "bafy1", wantErr: "annotated script bafy1 was not uploaded"},
+		{name: "lookup fails", assignment: 99, wantErr: "annotated script lookup failed"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sub := &Submission{Payload: SubmitPayload{AnnotatedCID: tc.cid}, Assignment: &db.AssignedScriptRow{ID: tc.assignment}}
+			err := st.Run(context.Background(), sub)
+			checkErr(t, err, tc.wantErr)
+			if err != nil {
+				return
+			}
+			got := ""
+			if sub.Annotated != nil {
+				got = sub.Annotated.CID
+			}
+			if got != tc.wantCID {
+				t.Errorf("annotated = %q, want %q", got, tc.wantCID)
+			}
+		})
+	}
+}
+
+func TestValidatorStage(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       *fakeValidator
+		wantErr string
+	}{
+		{name: "valid", v: &fakeValidator{resp: &pybridge.ValidateResponse{Valid: true}}},
+		{name: "invalid", v: &fakeValidator{resp: &pybridge.ValidateResponse{Errors: []string{"q3 over maximum"}}}, wantErr: "validation failed: [q3 over maximum]"},
+		{name: "unreachable validator is skipped", v: &fakeValidator{err: errors.New("connection refused")}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sub := &Submission{
+				Payload: SubmitPayload{ScriptID: "S1", EvaluatorID: "E1", CourseID: "CS501", Semester: "5", TotalMarks: 100, MarksScored: passMarks},
+				Course:  catalog()["CS501"],
+			}
+			checkErr(t, (&ValidatorStage{Validator: tc.v}).Run(context.Background(), sub), tc.wantErr)
+			if in := tc.v.got; in.ScriptID != "S1" || in.CourseCredits != 4 || !reflect.DeepEqual(in.MarksScored, passMarks) {
+				t.Errorf("validator got %+v", in)
+			}
+		})
+	}
+}
+
+// chainSubmission is a scored submission ready for the chain stage.
+func chainSubmission() *Submission {
+	return &Submission{
+		Payload: SubmitPayload{
+			ScriptID: "S1", EvaluatorID: "E1", CourseID: "CS501", Semester: "5", AcademicYear: "2025-26", TotalMarks: 100,
+			MarksScored: passMarks,
+			Remarks:     []QuestionRemark{{Question: 1, Remark: "good"}},
+		},
+		Course:     catalog()["CS501"],
+		Assignment: &db.AssignedScriptRow{ID: 7, ScriptID: "S1", Evaluator: "E1", ValuationRound: 1},
+		Annotated:  &db.AnnotatedScriptRow{CID: "bafy1", SHA256: "abc"},
+		StudentUSN: "1BI21CS001",
+		Score:      60,
+		Result:     grading.ResultPass,
+		Present:    true,
+		Marks:      map[string]interface{}{"marks_scored": passMarks},
+	}
+}
+
+func TestChainStage(t *testing.T) {
+	t.Run("appends the evaluation block with its outbox item", func(t *testing.T) {
+		ob, store, ch, _ := newOutbox(outbox.KindEvaluationResult)
+		store.head = "prev"
+		sub := chainSubmission()
+		if err := (&ChainStage{Chain: ch, Head: store, Outbox: ob}).Run(context.Background(), sub); err != nil {
+			t.Fatal(err)
+		}
+		blk, err := store.GetBlock(sub.BlockHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx := blk.Transactions[0]
+		if blk.Header.PrevHash != "prev" || tx.USN != "" || tx.Meta["_evaluation"] != string(sub.MarksJSON) {
+			t.Errorf("block = %+v", blk)
+		}
+		if len(sub.Pending) != 1 {
+			t.Fatalf("pending = %v, want one item", sub.Pending)
+		}
+		it, err := ob.Get(sub.Pending[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var e outbox.EvaluationResult
+		if err := json.Unmarshal(it.Payload, &e); err != nil {
+			t.Fatal(err)
+		}
+		if it.Kind != outbox.KindEvaluationResult || it.BlockHash != sub.BlockHash {
+			t.Errorf("item = %+v", it)
+		}
+		if e.StudentUSN != "1BI21CS001" || e.CourseCredits != 4 || e.Result != grading.ResultPass || e.AnnotatedCID != "bafy1" || len(e.Remarks) == 0 {
+			t.Errorf("evaluation row = %+v", e)
+		}
+	})
+	t.Run("failed append leaves nothing queued", func(t *testing.T) {
+		ob, store, ch, _ := newOutbox(outbox.KindEvaluationResult)
+		ch.err = errors.New("disk full")
+		sub := chainSubmission()
+		checkErr(t, (&ChainStage{Chain: ch, Head: store, Outbox: ob}).Run(context.Background(), sub), "append block failed: disk full")
+		if len(store.items) != 0 || sub.BlockHash != "" {
+			t.Errorf("outbox = %v, block = %q", store.items, sub.BlockHash)
+		}
+	})
+}
+
+func TestPersistStage(t *testing.T) {
+	tests := []struct {
+		name      string
+		applyErr  error
+		wantRows  int
+		wantQueue int
+	}{
+		{name: "evaluation row delivered", wantRows: 1},
+		{name: "row stays queued when postgres fails", applyErr: errors.New("postgres down"), wantQueue: 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			ob, store, ch, rows := newOutbox(outbox.KindEvaluationResult)
+			rows.err = tc.applyErr
+			sub := chainSubmission()
+			if err := (&ChainStage{Chain: ch, Head: store, Outbox: ob}).Run(ctx, sub); err != nil {
+				t.Fatal(err)
+			}
+			as := &fakeAssignmentStore{status: map[int64]string{}}
+			if err := (&PersistStage{Store: as, Outbox: ob}).Run(ctx, sub); err != nil {
+				t.Fatalf("persist failed: %v", err)
+			}
+			if len(rows.items) != tc.wantRows || len(store.items) != tc.wantQueue {
+				t.Errorf("rows %d, queued %d; want %d, %d", len(rows.items), len(store.items), tc.wantRows, tc.wantQueue)
+			}
+			if tc.wantQueue > 0 {
+				it, _ := ob.Get(sub.Pending[0])
+				if it.Attempts != 1 || it.LastError != "postgres down" {
+					t.Errorf("queued item = %+v", it)
+				}
+			}
+			if as.status[7] != "evaluated" || !reflect.DeepEqual(as.drafts, []int64{7}) || as.reopens {
+				t.Errorf("assignment %v, drafts %v (reopen %v)", as.status, as.drafts, as.reopens)
+			}
+		})
+	}
+}
+
+func TestAppendLinkedSerialisesHead(t *testing.T) {
+	ob, store, ch, _ := newOutbox(outbox.KindEvaluationResult)
+	ch.delay = time.Millisecond // widen the window between reading the head and appending
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tx := block.Transaction{ScriptID: fmt.Sprintf("S%d", i), SignerID: "E1"}
+			if _, _, err := appendLinked(context.Background(), ob, ch, store, tx); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+	// every block links to a different predecessor, so the chain is one line
+	seen := map[string]bool{}
+	for h := store.head; h != ""; {
+		blk, err := store.GetBlock(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen[h] = true
+		h = blk.Header.PrevHash
+	}
+	if len(seen) != n || len(store.blocks) != n {
+		t.Fatalf("chain from head has %d of %d blocks", len(seen), len(store.blocks))
+	}
+}
+
+func checkErr(t *testing.T, err error, want string) {
+	t.Helper()
+	switch {
+	case want == "" && err != nil:
+		t.Fatalf("unexpected error: %v", err)
+	case want != "" && err == nil:
+		t.Fatalf("expected error containing %q", want)
+	case want != "" && !strings.Contains(err.Error(), want):
+		t.Fatalf("error %q does not contain %q", err, want)
+	}
+}
@@ -0,0 +1,130 @@
+package evaluator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"digital-eval-system/services/go-node/internal/exampattern"
+)
+
+// Annotation kinds.
+const (
+	AnnotationTick    = "tick"
+	AnnotationCross   = "cross"
+	AnnotationComment = "comment"
+)
+
+// Limits on what an evaluator can attach to one script.
+const (
+	maxRemarkLen   = 1000
+	maxCommentLen  = 500
+	maxAnnotations = 500
+)
+
+// QuestionRemark is the evaluator's explanation of the marks given for one
+// question. Question is 1-based over the exam pattern's marked questions;
+// Label is filled in from the pattern.
+type QuestionRemark struct {
+	Question int    `json:"question"`
+	Label    string `json:"label,omitempty"`
+	Remark   string `json:"remark"`
+}
+
+// Annotation is a mark placed on a page of the script. X and Y are fractions
+// of the page width and height from its top-left corner, so they hold for any
+// rendering size. Question optionally ties it to a marked question.
+type Annotation struct {
+	Page     int     `json:"page"`
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+	Kind     string  `json:"kind"` // tick | cross | comment
+	Text     string  `json:"text,omitempty"`
+	Question int     `json:"question,omitempty"`
+}
+
+// checkRemarks validates remarks and annotations against the exam pattern,
+// trimming text and labelling remarks with their question.
+func checkRemarks(p *SubmitPayload, pattern *exampattern.Pattern) error {
+	leaves := pattern.Leaves()
+	question := func(q int) error {
+		if q < 1 || q > len(leaves) {
+			return fmt.Errorf("question %d out of range 1..%d", q, len(leaves))
+		}
+		return nil
+	}
+
+	seen := make(map[int]bool, len(p.Remarks))
+	for i := range p.Remarks {
+		r := &p.Remarks[i]
+		if err := question(r.Question); err != nil {
+			return fmt.Errorf("remarks[%d]: %w", i, err)
+		}
+		if seen[r.Question] {
+			return fmt.Errorf("remarks[%d]: question %d has more than one remark", i, r.Question)
+		}
+		seen[r.Question] = true
+		r.Remark = strings.TrimSpace(r.Remark)
+		if r.Remark == "" {
+			return fmt.Errorf("remarks[%d]: remark is empty", i)
+		}
+		if utf8.RuneCountInString(r.Remark) > maxRemarkLen {
+			return fmt.Errorf("remarks[%d]: remark longer than %d characters", i, maxRemarkLen)
+		}
+		r.Label = leaves[r.Question-1].Label
+	}
+
+	if len(p.Annotations) > maxAnnotations {
+		return fmt.Errorf("at most %d annotations per script, got %d", maxAnnotations, len(p.Annotations))
+	}
+	for i := range p.Annotations {
+		a := &p.Annotations[i]
+		if a.Page < 1 {
+			return fmt.Errorf("annotations[%d]: page must be >= 1", i)
+		}
+		if a.X < 0 || a.X > 1 || a.Y < 0 || a.Y > 1 {
+			return fmt.Errorf("annotations[%d]: x and y must be fractions of the page (0..1)", i)
+		}
+		if a.Question != 0 {
+			if err := question(a.Question); err != nil {
+				return fmt.Errorf("annotations[%d]: %w", i, err)
+			}
+		}
+		a.Text = strings.TrimSpace(a.Text)
+		switch a.Kind {
+		case AnnotationTick, AnnotationCross:
+		case AnnotationComment:
+			if a.Text == "" {
+				return fmt.Errorf("annotations[%d]: comment has no text", i)
+			}
+		default:
+			return fmt.Errorf("annotations[%d]: unknown kind %q", i, a.Kind)
+		}
+		if utf8.RuneCountInString(a.Text) > maxCommentLen {
+			return fmt.Errorf("annotations[%d]: text longer than %d characters", i, maxCommentLen)
+		}
+	}
+	return nil
+}
+
+// reviewJSON returns the remarks and annotations as stored in Postgres and
+// the SHA-256 of both, which the evaluation block carries so the stored copy
+// can be checked against the chain.
+func reviewJSON(p SubmitPayload) (remarks, annotations []byte, digest string) {
+	if p.Remarks == nil {
+		p.Remarks = []QuestionRemark{}
+	}
+	if p.Annotations == nil {
+		p.Annotations = []Annotation{}
+	}
+	remarks, _ = json.Marshal(p.Remarks)
+	annotations, _ = json.Marshal(p.Annotations)
+	sum := sha256.New()
+	sum.Write(remarks)
+	sum.Write([]byte{'\n'})
+	sum.Write(annotations)
+	return remarks, annotations, hex.EncodeToString(sum.Sum(nil))
+}
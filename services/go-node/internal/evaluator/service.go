@@ -2,37 +2,39 @@ package evaluator
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
-	"time"
-
-	"github.com/sirupsen/logrus"
 
+	"digital-eval-system/services/go-node/internal/blind"
 	"digital-eval-system/services/go-node/internal/block"
 	"digital-eval-system/services/go-node/internal/db"
-	"digital-eval-system/services/go-node/internal/pybridge"
+	"digital-eval-system/services/go-node/internal/eligibility"
 	"digital-eval-system/services/go-node/internal/storage"
 )
 
 // Service orchestrates evaluator actions.
 type Service struct {
-	pg    *db.PostgresDB
-	store storage.Storage
-	py    *pybridge.Client
-	chain interface {
-		AppendBlock(*block.Block) (string, error)
-	}
+	pg     *db.PostgresDB
+	store  storage.Storage
+	submit *SubmitService
+	blind  *blind.Service
+	elig   *eligibility.Service
 }
 
 // NewService creates evaluator service
-func NewService(pg *db.PostgresDB, st storage.Storage, py *pybridge.Client, chain interface {
-	AppendBlock(*block.Block) (string, error)
-}) *Service {
-	return &Service{pg: pg, store: st, py: py, chain: chain}
+func NewService(pg *db.PostgresDB, st storage.Storage, submitSvc *SubmitService, blindSvc *blind.Service, eligibilitySvc *eligibility.Service) *Service {
+	return &Service{pg: pg, store: st, submit: submitSvc, blind: blindSvc, elig: eligibilitySvc}
 }
 
+// CreateRequest records an evaluator's request to evaluate a course. The
+// evaluator must be registered for the course or its department today.
 func (s *Service) CreateRequest(ctx context.Context, evaluatorID, courseID, semester, academicYear, desc string) (int64, error) {
+	if evaluatorID == "" || courseID == "" || semester == "" {
+		return 0, fmt.Errorf("evaluator_id, course_id and semester are required")
+	}
+	if err := s.elig.Require(ctx, evaluatorID, courseID); err != nil {
+		return 0, err
+	}
 	return s.pg.InsertEvaluationRequest(ctx, evaluatorID, courseID, semester, academicYear, desc)
 }
 
@@ -40,9 +42,34 @@ func (s *Service) ListRequests(ctx context.Context, evaluatorID string) ([]db.Ev
 	return s.pg.ListRequestsByEvaluator(ctx, evaluatorID)
 }
 
-// ListAssigned returns assigned scripts for evaluator
+// ListAssigned returns assigned scripts for evaluator. In blind mode each
+// script is listed under its dummy number.
 func (s *Service) ListAssigned(ctx context.Context, evaluatorID string) ([]db.AssignedScriptRow, error) {
-	return s.pg.ListAssignedByEvaluator(ctx, evaluatorID)
+	rows, err := s.pg.ListAssignedByEvaluator(ctx, evaluatorID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		if rows[i].ScriptID, err = s.blind.Label(ctx, rows[i].ScriptID, rows[i].CourseID, rows[i].Semester); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+// GetScript returns the metadata an evaluator sees for a script, referenced
+// by script id or dummy number. In blind mode student identifiers are
+// stripped and the PDF is the masked copy.
+func (s *Service) GetScript(ctx context.Context, ref string) (map[string]string, error) {
+	scriptID, err := s.blind.Resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := s.GetScriptMetadata(ctx, scriptID)
+	if err != nil || !s.blind.Enabled() {
+		return meta, err
+	}
+	return s.blind.Mask(ctx, scriptID, meta)
 }
 
 // GetScriptMetadata fetches metadata for a script from BoltDB
@@ -84,141 +111,8 @@ func (s *Service) GetScriptMetadata(ctx context.Context, scriptID string) (map[s
 	return result, nil
 }
 
-// SubmitEvaluation validates marks via Python and stores record on blockchain + Postgres
+// SubmitEvaluation runs the payload through the same submit pipeline as
+// SubmitService, so the outcome never depends on which handler is routed.
 func (s *Service) SubmitEvaluation(ctx context.Context, payload SubmitPayload) (string, error) {
-	// basic validation
-	if payload.ScriptID == "" || payload.EvaluatorID == "" {
-		return "", fmt.Errorf("missing fields")
-	}
-
-	// local strict validation
-	if payload.TotalMarks <= 0 {
-		return "", fmt.Errorf("total_marks must be > 0")
-	}
-	if payload.TotalQuestions <= 0 {
-		return "", fmt.Errorf("total_questions must be > 0")
-	}
-	if len(payload.MarksScored) == 0 {
-		return "", fmt.Errorf("marks_scored cannot be empty")
-	}
-	if payload.QuestionsAnswered != len(payload.MarksScored) {
-		return "", fmt.Errorf("questions_answered mismatch: expected %d marks, got %d", payload.QuestionsAnswered, len(payload.MarksScored))
-	}
-	if len(payload.MarksAllotted) != 0 && len(payload.MarksAllotted) != len(payload.MarksScored) {
-		return "", fmt.Errorf("marks_allotted_per_question length mismatch")
-	}
-	sumScored := 0
-	for i, sc := range payload.MarksScored {
-		if sc < 0 {
-			return "", fmt.Errorf("marks_scored[%d] negative", i)
-		}
-		if len(payload.MarksAllotted) == len(payload.MarksScored) {
-			if sc > payload.MarksAllotted[i] {
-				return "", fmt.Errorf("marks_scored[%d] out of range: got %d, max %d", i, sc, payload.MarksAllotted[i])
-			}
-		}
-		sumScored += sc
-	}
-	if sumScored > payload.TotalMarks {
-		return "", fmt.Errorf("sum of marks_scored (%d) exceeds total_marks (%d)", sumScored, payload.TotalMarks)
-	}
-
-	// fetch original metadata to get USN/course/semester
-	meta, err := s.GetScriptMetadata(ctx, payload.ScriptID)
-	if err != nil {
-		return "", fmt.Errorf("cannot fetch script metadata: %w", err)
-	}
-	usn := strings.TrimSpace(meta["USN"])
-	course := payload.CourseID
-	if course == "" {
-		course = strings.TrimSpace(meta["CourseID"])
-	}
-	semester := payload.Semester
-	if semester == "" {
-		semester = strings.TrimSpace(meta["Semester"])
-	}
-
-	academicYear := payload.AcademicYear
-	if academicYear == "" {
-		academicYear = strings.TrimSpace(meta["AcademicYear"])
-	}
-
-	// ensure script is assigned to evaluator and not already evaluated
-	assigned, err := s.pg.GetAssignedScriptByScriptID(ctx, payload.ScriptID)
-	if err != nil {
-		return "", fmt.Errorf("failed to fetch assignment: %w", err)
-	}
-	if assigned == nil || assigned.Evaluator != payload.EvaluatorID || assigned.Status != "assigned" {
-		return "", fmt.Errorf("script not assigned to evaluator or not in assigned state")
-	}
-	// assignedScripts retrieved via GetAssignedScript must include academic_year
-	if assigned.AcademicYear != payload.AcademicYear {
-		return "", fmt.Errorf("academic_year mismatch: assigned=%s submitted=%s",
-			assigned.AcademicYear, payload.AcademicYear)
-	}
-
-	// ensure no existing evaluation for this script
-	exists, err := s.pg.EvaluationExistsForScript(ctx, payload.ScriptID)
-	if err != nil {
-		return "", fmt.Errorf("db check failed: %w", err)
-	}
-	if exists {
-		return "", fmt.Errorf("evaluation already submitted for this script")
-	}
-
-	// ensure no existing evaluation for this student+semester+course (enforce single row per subject)
-	dup, err := s.pg.EvaluationExistsForStudentSemesterCourse(ctx, usn, semester, academicYear, course)
-	if err != nil {
-		return "", fmt.Errorf("db check failed: %w", err)
-	}
-	if dup {
-		return "", fmt.Errorf("evaluation already exists for student %s semester %s course %s", usn, semester, course)
-	}
-
-	// create marks JSON
-	marksStruct := map[string]interface{}{
-		"total_questions":     payload.TotalQuestions,
-		"marks_per_question":  payload.MarksPerQuestion,
-		"total_marks":         payload.TotalMarks,
-		"course_id":           course,
-		"semester":            semester,
-		"academic_year":       payload.AcademicYear,
-		"questions_answered":  payload.QuestionsAnswered,
-		"marks_allotted":      payload.MarksAllotted,
-		"marks_scored":        payload.MarksScored,
-		"additional_metadata": payload.AdditionalMetadata,
-		"course_credits":      payload.CourseCredits,
-	}
-	marksJSON, _ := json.Marshal(marksStruct)
-
-	// create evaluation transaction and append to chain
-	tx := block.Transaction{
-		ScriptID:     payload.ScriptID,
-		USN:          usn,
-		CourseID:     course,
-		Semester:     semester,
-		AcademicYear: payload.AcademicYear,
-		CID:          "",
-		Meta:         map[string]string{"_evaluation": string(marksJSON)},
-		CreatedAt:    int64(time.Now().Unix()),
-		SignerID:     payload.EvaluatorID,
-	}
-	prevHash := ""
-	if h, herr := s.store.Head(); herr == nil {
-		prevHash = h
-	}
-	newBlock := block.NewBlock(prevHash, []block.Transaction{tx}, payload.EvaluatorID)
-	blockHash, err := s.chain.AppendBlock(newBlock)
-	if err != nil {
-		return "", fmt.Errorf("append block failed: %w", err)
-	}
-
-	// persist evaluation: use corrected InsertEvaluationResult signature
-	if err := s.pg.InsertEvaluationResult(ctx, payload.ScriptID, usn, course, semester, payload.AcademicYear, payload.CourseCredits, payload.EvaluatorID, marksJSON, payload.TotalMarks, "PASS", blockHash); err != nil {
-		logrus.Warnf("failed to insert evaluation to pg: %v", err)
-		// block appended; return error to caller
-		return "", fmt.Errorf("insert evaluation failed: %w", err)
-	}
-
-	return blockHash, nil
+	return s.submit.SubmitEvaluation(ctx, payload)
 }
@@ -0,0 +1,391 @@
+package evaluator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"digital-eval-system/services/go-node/internal/block"
+	"digital-eval-system/services/go-node/internal/course"
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/exampattern"
+	"digital-eval-system/services/go-node/internal/grading"
+	"digital-eval-system/services/go-node/internal/outbox"
+	"digital-eval-system/services/go-node/internal/pybridge"
+)
+
+// SchemaStage checks the payload's shape and resolves the course and the
+// exam pattern that defines its marks layout. Credits and regulation come
+// from the course catalog, never the client.
+type SchemaStage struct {
+	Scripts interface {
+		Resolve(ctx context.Context, ref string) (string, error)
+	} // dummy numbers in blind mode
+	Courses interface {
+		Get(ctx context.Context, code string) (*course.Course, error)
+	}
+	Patterns interface {
+		Resolve(ctx context.Context, code string) (*exampattern.Pattern, error)
+	}
+}
+
+func (st *SchemaStage) Name() string { return StageSchema }
+
+func (st *SchemaStage) Run(ctx context.Context, sub *Submission) error {
+	p := &sub.Payload
+	if p.ScriptID == "" || p.EvaluatorID == "" {
+		return fmt.Errorf("missing fields")
+	}
+	scriptID, err := st.Scripts.Resolve(ctx, p.ScriptID)
+	if err != nil {
+		return err
+	}
+	p.ScriptID = scriptID
+
+	switch p.Attendance {
+	case "", AttendancePresent, AttendanceAbsent, AttendanceWithheld:
+	default:
+		return fmt.Errorf("unknown attendance %q", p.Attendance)
+	}
+	for i, m := range p.MarksScored {
+		if m < 0 {
+			return fmt.Errorf("marks_scored[%d] negative", i)
+		}
+	}
+
+	co, err := st.Courses.Get(ctx, p.CourseID)
+	if err != nil {
+		return fmt.Errorf("course lookup failed: %w", err)
+	}
+	if co == nil {
+		return fmt.Errorf("course %s is not in the course catalog", p.CourseID)
+	}
+	p.CourseID = co.CourseCode
+	if p.Semester == "" {
+		p.Semester = co.Semester
+	}
+
+	pattern, err := st.Patterns.Resolve(ctx, co.ExamPattern)
+	if err != nil {
+		return err
+	}
+	if err := applyPattern(p, pattern); err != nil {
+		return err
+	}
+	if err := checkRemarks(p, pattern); err != nil {
+		return err
+	}
+	sub.Course = co
+	sub.Pattern = pattern
+	return nil
+}
+
+// AssignmentStage accepts marks only from the evaluator the script is
+// assigned to, while the assignment is open.
+type AssignmentStage struct {
+	Assignments interface {
+		GetAssignmentForEvaluator(ctx context.Context, scriptID, evaluatorID string) (*db.AssignedScriptRow, error)
+	}
+}
+
+func (st *AssignmentStage) Name() string { return StageAssignment }
+
+func (st *AssignmentStage) Run(ctx context.Context, sub *Submission) error {
+	p := &sub.Payload
+	a, err := st.Assignments.GetAssignmentForEvaluator(ctx, p.ScriptID, p.EvaluatorID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch assignment: %w", err)
+	}
+	switch {
+	case a == nil:
+		return fmt.Errorf("script not assigned to evaluator or already evaluated")
+	case a.Status == "revoked":
+		// overdue or withdrawn assignments no longer accept marks
+		return fmt.Errorf("assignment of script %s was revoked", p.ScriptID)
+	case !assignmentOpen(a.Status):
+		return fmt.Errorf("script not assigned to evaluator or already evaluated")
+	}
+	if p.AcademicYear == "" {
+		p.AcademicYear = a.AcademicYear
+	} else if a.AcademicYear != "" && a.AcademicYear != p.AcademicYear {
+		return fmt.Errorf("academic_year mismatch: assigned=%s submitted=%s", a.AcademicYear, p.AcademicYear)
+	}
+	sub.Assignment = a
+	return nil
+}
+
+// AnnotationStage attaches the annotated copy the evaluator uploaded for
+// the assignment. A CID given with the marks must be that upload.
+type AnnotationStage struct {
+	Annotations interface {
+		GetAnnotatedScript(ctx context.Context, assignmentID int64) (*db.AnnotatedScriptRow, error)
+	}
+}
+
+func (st *AnnotationStage) Name() string { return StageAnnotation }
+
+func (st *AnnotationStage) Run(ctx context.Context, sub *Submission) error {
+	a, err := st.Annotations.GetAnnotatedScript(ctx, sub.Assignment.ID)
+	if err != nil {
+		return fmt.Errorf("annotated script lookup failed: %w", err)
+	}
+	if cid := sub.Payload.AnnotatedCID; cid != "" && (a == nil || a.CID != cid) {
+		return fmt.Errorf("annotated script %s was not uploaded for this assignment", cid)
+	}
+	sub.Annotated = a
+	return nil
+}
+
+// DuplicateStage rejects a second evaluation of a script, or of the same
+// student in the same course, semester and academic year.
+type DuplicateStage struct {
+	Evaluations interface {
+		EvaluationExistsForScript(ctx context.Context, scriptID string) (bool, error)
+		EvaluationExistsForStudentSemesterCourse(ctx context.Context, studentUSN, semester, academicYear, courseID string) (bool, error)
+	}
+	USN func(scriptID string) string // student USN from the upload record
+}
+
+func (st *DuplicateStage) Name() string { return StageDuplicate }
+
+func (st *DuplicateStage) Run(ctx context.Context, sub *Submission) error {
+	p := &sub.Payload
+	exists, err := st.Evaluations.EvaluationExistsForScript(ctx, p.ScriptID)
+	if err != nil {
+		return fmt.Errorf("db check failed: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("evaluation already submitted for this script")
+	}
+	sub.StudentUSN = st.USN(p.ScriptID)
+	if sub.StudentUSN == "" {
+		return nil
+	}
+	dup, err := st.Evaluations.EvaluationExistsForStudentSemesterCourse(ctx, sub.StudentUSN, p.Semester, p.AcademicYear, p.CourseID)
+	if err != nil {
+		return fmt.Errorf("db check failed: %w", err)
+	}
+	if dup {
+		// the message names no student: the evaluator may be marking blind
+		return fmt.Errorf("an evaluation of this student already exists for course %s semester %s", p.CourseID, p.Semester)
+	}
+	return nil
+}
+
+// ValidatorStage asks the external Python validator for a second opinion.
+// An unreachable validator is logged and skipped; a negative answer rejects.
+type ValidatorStage struct {
+	Validator interface {
+		ValidateEvaluation(ctx context.Context, in pybridge.EvalValidationInput) (*pybridge.ValidateResponse, error)
+	}
+}
+
+func (st *ValidatorStage) Name() string { return StageValidator }
+
+func (st *ValidatorStage) Run(ctx context.Context, sub *Submission) error {
+	p := sub.Payload
+	resp, err := st.Validator.ValidateEvaluation(ctx, pybridge.EvalValidationInput{
+		ScriptID:          p.ScriptID,
+		EvaluatorID:       p.EvaluatorID,
+		TotalQuestions:    p.TotalQuestions,
+		MarksPerQuestion:  p.MarksPerQuestion,
+		TotalMarks:        p.TotalMarks,
+		QuestionsAnswered: p.QuestionsAnswered,
+		MarksAllotted:     p.MarksAllotted,
+		MarksScored:       p.MarksScored,
+		CourseID:          p.CourseID,
+		Semester:          p.Semester,
+		CourseCredits:     sub.Course.Credits,
+	})
+	if err != nil {
+		logrus.Warnf("python validator call failed: %v", err)
+		return nil
+	}
+	if !resp.Valid {
+		return fmt.Errorf("validation failed: %v", resp.Errors)
+	}
+	return nil
+}
+
+// ScoringStage checks the marks against the exam pattern, computes the
+// result under the grading scheme for the course's regulation and academic
+// year and builds the marks document. Absent / withheld scripts carry the
+// scheme's code instead of a score.
+type ScoringStage struct {
+	Grading interface {
+		Resolve(ctx context.Context, regulation, academicYear string) *grading.Scheme
+	}
+}
+
+func (st *ScoringStage) Name() string { return StageScoring }
+
+func (st *ScoringStage) Run(ctx context.Context, sub *Submission) error {
+	p := sub.Payload
+	scheme := st.Grading.Resolve(ctx, sub.Course.Regulation, p.AcademicYear)
+	switch p.Attendance {
+	case AttendanceAbsent:
+		sub.Result = scheme.AbsentCode
+	case AttendanceWithheld:
+		sub.Result = scheme.WithheldCode
+	default:
+		score, err := sub.Pattern.Check(p.MarksScored)
+		if err != nil {
+			return err
+		}
+		sub.Score = score
+		sub.Result = scheme.Result(score, p.TotalMarks)
+		sub.Present = true
+	}
+	sub.Scheme = scheme
+	sub.Marks = map[string]interface{}{
+		"total_questions":    p.TotalQuestions,
+		"marks_per_question": p.MarksPerQuestion,
+		"total_marks":        p.TotalMarks,
+		"course_id":          p.CourseID,
+		"course_name":        sub.Course.CourseName,
+		"semester":           p.Semester,
+		"academic_year":      p.AcademicYear,
+		"course_credits":     sub.Course.Credits,
+		"questions_answered": p.QuestionsAnswered,
+		"marks_allotted":     p.MarksAllotted,
+		"marks_scored":       p.MarksScored,
+		"attendance":         p.Attendance,
+		"additional":         p.AdditionalMetadata,
+		"exam_pattern":       sub.Pattern,
+	}
+	if len(p.Remarks) > 0 || len(p.Annotations) > 0 {
+		_, _, digest := reviewJSON(p)
+		sub.Marks["remarks"] = p.Remarks
+		sub.Marks["annotations"] = p.Annotations
+		sub.Marks["remarks_sha256"] = digest
+	}
+	if a := sub.Annotated; a != nil {
+		sub.Marks["annotated_script"] = map[string]string{"cid": a.CID, "sha256": a.SHA256}
+	}
+	return nil
+}
+
+// Outbox records the Postgres writes implied by a block before the block
+// is appended, and applies them afterwards (see outbox.Service).
+type Outbox interface {
+	Append(ctx context.Context, chain interface {
+		AppendBlock(*block.Block) (string, error)
+	}, blk *block.Block, effects ...outbox.Effect) (string, []string, error)
+	Deliver(ctx context.Context, ids ...string) error
+}
+
+// ChainStage writes the evaluation transaction (Meta "_evaluation") in a
+// block linked to the current head of the chain, recording the evaluation
+// row it implies in the outbox.
+type ChainStage struct {
+	Chain interface {
+		AppendBlock(*block.Block) (string, error)
+	}
+	Head   interface{ Head() (string, error) }
+	Outbox Outbox
+}
+
+func (st *ChainStage) Name() string { return StageChain }
+
+func (st *ChainStage) Run(ctx context.Context, sub *Submission) error {
+	p := sub.Payload
+	marksJSON, err := json.Marshal(sub.Marks)
+	if err != nil {
+		return err
+	}
+	tx := block.Transaction{
+		ScriptID:     p.ScriptID,
+		USN:          "", // kept off the evaluation block; the upload record links the student
+		CourseID:     p.CourseID,
+		Semester:     p.Semester,
+		AcademicYear: p.AcademicYear,
+		Meta:         map[string]string{"_evaluation": string(marksJSON)},
+		CreatedAt:    time.Now().Unix(),
+		SignerID:     p.EvaluatorID,
+	}
+	blockHash, pending, err := appendLinked(ctx, st.Outbox, st.Chain, st.Head, tx, evaluationEffect(sub, marksJSON, sub.Result))
+	if err != nil {
+		return fmt.Errorf("append block failed: %w", err)
+	}
+	sub.MarksJSON = marksJSON
+	sub.BlockHash = blockHash
+	sub.Pending = pending
+	return nil
+}
+
+// evaluationEffect is the evaluations row written for an evaluation block.
+func evaluationEffect(sub *Submission, marksJSON []byte, result string) outbox.Effect {
+	p := sub.Payload
+	e := outbox.EvaluationResult{
+		ScriptID:      p.ScriptID,
+		StudentUSN:    sub.StudentUSN,
+		CourseID:      p.CourseID,
+		Semester:      p.Semester,
+		AcademicYear:  p.AcademicYear,
+		CourseCredits: sub.Course.Credits,
+		EvaluatorID:   p.EvaluatorID,
+		Marks:         marksJSON,
+		TotalMarks:    p.TotalMarks,
+		Result:        result,
+	}
+	if a := sub.Annotated; a != nil {
+		e.AnnotatedCID, e.AnnotatedSHA256 = a.CID, a.SHA256
+	}
+	if len(p.Remarks) > 0 || len(p.Annotations) > 0 {
+		e.Remarks, e.Annotations, _ = reviewJSON(p)
+	}
+	return outbox.Effect{Kind: outbox.KindEvaluationResult, Payload: e}
+}
+
+// appendMu serialises appendLinked, so no two blocks link to the same head.
+var appendMu sync.Mutex
+
+// appendLinked appends a single-transaction block whose previous hash is
+// the chain head (empty when the head cannot be read), recording its
+// effects in the outbox first. Returns the block hash and the pending
+// outbox items.
+func appendLinked(ctx context.Context, ob Outbox, chain interface {
+	AppendBlock(*block.Block) (string, error)
+}, head interface{ Head() (string, error) }, tx block.Transaction, effects ...outbox.Effect) (string, []string, error) {
+	appendMu.Lock()
+	defer appendMu.Unlock()
+	prevHash := ""
+	if h, err := head.Head(); err == nil {
+		prevHash = h
+	}
+	return ob.Append(ctx, chain, block.NewBlock(prevHash, []block.Transaction{tx}, tx.SignerID), effects...)
+}
+
+// PersistStage applies the outbox items of the evaluation block, closes the
+// assignment and clears its draft. An evaluation row that cannot be written
+// now stays in the outbox and is retried until it is.
+type PersistStage struct {
+	Store interface {
+		UpdateAssignmentStatus(ctx context.Context, id int64, status string) error
+		DeleteDraft(ctx context.Context, assignmentID int64, reopen bool) error
+	}
+	Outbox Outbox
+}
+
+func (st *PersistStage) Name() string { return StagePersist }
+
+func (st *PersistStage) Run(ctx context.Context, sub *Submission) error {
+	p := sub.Payload
+	if err := st.Outbox.Deliver(ctx, sub.Pending...); err != nil {
+		logrus.Warnf("evaluation result of script %s queued for retry: %v", p.ScriptID, err)
+	}
+	a := sub.Assignment
+	if err := st.Store.UpdateAssignmentStatus(ctx, a.ID, "evaluated"); err != nil {
+		logrus.Warnf("failed to update assignment status: %v", err)
+	} else {
+		logrus.Infof("updated assignment status to evaluated for script %s", p.ScriptID)
+	}
+	if err := st.Store.DeleteDraft(ctx, a.ID, false); err != nil {
+		logrus.Warnf("failed to clear draft of assignment %d: %v", a.ID, err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package evaluator
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/sirupsen/logrus"
@@ -26,15 +27,10 @@ func (h *SubmitHandler) Submit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// ensure numeric course credits default 0
-	if in.CourseCredits < 0 {
-		in.CourseCredits = 0
-	}
-
 	blockHash, err := h.svc.SubmitEvaluation(ctx, in)
 	if err != nil {
 		logrus.Warnf("submit evaluation failed: %v", err)
-		http.Error(w, "submit failed: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "submit failed: "+err.Error(), submitStatus(err))
 		return
 	}
 
@@ -46,6 +42,29 @@ func (h *SubmitHandler) Submit(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(resp)
 }
 
+// submitStatus maps a pipeline error to an HTTP status: a submission the
+// checking stages reject is the client's error, a failed write is ours.
+func submitStatus(err error) int {
+	var se *StageError
+	if errors.As(err, &se) {
+		switch se.Stage {
+		case StageAssignment, StageDuplicate:
+			return http.StatusConflict
+		case StageSchema, StageValidator, StageScoring:
+			return http.StatusBadRequest
+		}
+	}
+	return http.StatusInternalServerError
+}
+
 func RegisterSubmitRoutes(r *mux.Router, svc *SubmitService) {
-	r.HandleFunc("/evaluator/submit", NewSubmitHandler(svc).Submit).Methods("POST")
+	h := NewSubmitHandler(svc)
+	r.HandleFunc("/evaluator/submit", h.Submit).Methods("POST")
+
+	// drafts: autosave, resume and submit partially entered marks
+	r.HandleFunc("/evaluator/drafts", h.ListDrafts).Methods("GET")
+	r.HandleFunc("/evaluator/drafts/{script_id}", h.GetDraft).Methods("GET")
+	r.HandleFunc("/evaluator/drafts/{script_id}", h.SaveDraft).Methods("PUT")
+	r.HandleFunc("/evaluator/drafts/{script_id}", h.DiscardDraft).Methods("DELETE")
+	r.HandleFunc("/evaluator/drafts/{script_id}/submit", h.SubmitDraft).Methods("POST")
 }
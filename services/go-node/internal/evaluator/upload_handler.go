@@ -2,7 +2,10 @@ package evaluator
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,9 +13,11 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 
 	"digital-eval-system/services/go-node/internal/core"
+	"digital-eval-system/services/go-node/internal/db"
 	"digital-eval-system/services/go-node/internal/rootdir"
 )
 
@@ -35,7 +40,8 @@ func NewUploadHandler(svc *UploadService, uploadDir string) *UploadHandler {
 }
 
 // ServeHTTP handles POST /api/v1/evaluator/upload
-// Expects multipart/form-data with "file" field.
+// Expects multipart/form-data with "file", "script_id" (or dummy number) and
+// "evaluator_id" fields. The file must be a script assigned to the evaluator.
 func (h *UploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	trace := core.TraceIDFromContext(r.Context())
 
@@ -54,7 +60,13 @@ func (h *UploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	defer file.Close()
 
-	// 2. Save to temp local file
+	scriptID, evaluatorID := r.FormValue("script_id"), r.FormValue("evaluator_id")
+	if scriptID == "" || evaluatorID == "" {
+		http.Error(w, "script_id and evaluator_id are required", http.StatusBadRequest)
+		return
+	}
+
+	// 2. Save to temp local file, hashing it on the way
 	filename := filepath.Base(header.Filename)
 	savedPath := filepath.Join(h.uploadDir, fmt.Sprintf("eval_%d_%s", time.Now().UnixNano(), filename))
 
@@ -65,7 +77,8 @@ func (h *UploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, err := io.Copy(dst, file); err != nil {
+	sum := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(dst, sum), file); err != nil {
 		dst.Close()
 		http.Error(w, "failed to save file", http.StatusInternalServerError)
 		return
@@ -79,19 +92,49 @@ func (h *UploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 300*time.Second)
 	defer cancel()
 
-	cid, pdfPath, err := h.service.UploadEvaluatedScript(ctx, savedPath)
+	row, err := h.service.UploadAnnotatedScript(ctx, AnnotatedUpload{
+		ScriptID:    scriptID,
+		EvaluatorID: evaluatorID,
+		FilePath:    savedPath,
+		FileName:    filename,
+		SHA256:      hex.EncodeToString(sum.Sum(nil)),
+	})
 	if err != nil {
 		logrus.WithField("trace", trace).Warnf("evaluator upload failed: %v", err)
-		http.Error(w, err.Error(), http.StatusBadGateway)
+		switch {
+		case errors.Is(err, ErrNotAssigned):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, ErrWrongScript):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, err.Error(), http.StatusBadGateway)
+		}
 		return
 	}
 
 	// 4. Response
-	resp := map[string]string{
-		"cid":      cid,
-		"pdf_path": pdfPath,
-		"status":   "uploaded",
+	resp := map[string]interface{}{
+		"cid":           row.CID,
+		"sha256":        row.SHA256,
+		"script_id":     row.ScriptID,
+		"assignment_id": row.AssignmentID,
+		"status":        "uploaded",
 	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
+
+// ListAnnotated handles GET /api/v1/authority/scripts/{script_id}/annotated
+// Every annotated copy evaluators uploaded for a script.
+func (h *UploadHandler) ListAnnotated(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.service.AnnotatedScripts(r.Context(), mux.Vars(r)["script_id"])
+	if err != nil {
+		http.Error(w, "failed to load annotated scripts", http.StatusInternalServerError)
+		return
+	}
+	if rows == nil {
+		rows = []db.AnnotatedScriptRow{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rows)
+}
@@ -2,45 +2,173 @@ package evaluator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"digital-eval-system/services/go-node/internal/blind"
+	"digital-eval-system/services/go-node/internal/db"
 	"digital-eval-system/services/go-node/internal/pybridge"
+	"digital-eval-system/services/go-node/internal/storage"
+)
+
+var (
+	// ErrNotAssigned is returned when the uploader has no open assignment of the script.
+	ErrNotAssigned = errors.New("script not assigned to evaluator or already evaluated")
+	// ErrWrongScript is returned when the uploaded file is another script.
+	ErrWrongScript = errors.New("uploaded file does not belong to this script")
 )
 
 // UploadService handles uploading evaluated scripts to IPFS via pybridge.
 type UploadService struct {
 	pyClient *pybridge.Client
+	pg       *db.PostgresDB
+	store    storage.Storage
+	blind    *blind.Service
 }
 
 // NewUploadService creates a new upload service.
-func NewUploadService(py *pybridge.Client) *UploadService {
+func NewUploadService(py *pybridge.Client, pg *db.PostgresDB, store storage.Storage, blindSvc *blind.Service) *UploadService {
 	if py == nil {
 		// fallback default
 		py = pybridge.NewClient("http://127.0.0.1:8081", 300*time.Second)
 	}
 	return &UploadService{
 		pyClient: py,
+		pg:       pg,
+		store:    store,
+		blind:    blindSvc,
+	}
+}
+
+// AnnotatedUpload is an evaluator's annotated copy of an assigned script.
+type AnnotatedUpload struct {
+	ScriptID    string // script id or, in blind mode, its dummy number
+	EvaluatorID string
+	FilePath    string
+	FileName    string
+	SHA256      string // hex digest of the uploaded file
+}
+
+// UploadAnnotatedScript checks that the script is open on the uploader's
+// assignment and that the file is that script, adds it to IPFS and records
+// its CID and SHA-256 against the assignment. A later upload replaces it
+// until the evaluation is submitted.
+func (s *UploadService) UploadAnnotatedScript(ctx context.Context, in AnnotatedUpload) (*db.AnnotatedScriptRow, error) {
+	if in.ScriptID == "" || in.EvaluatorID == "" {
+		return nil, fmt.Errorf("script_id and evaluator_id are required")
+	}
+	ref := in.ScriptID
+	scriptID, err := s.blind.Resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	a, err := s.pg.GetAssignmentForEvaluator(ctx, scriptID, in.EvaluatorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch assignment: %w", err)
+	}
+	if a == nil || !assignmentOpen(a.Status) {
+		return nil, ErrNotAssigned
+	}
+
+	cid, meta, err := s.upload(ctx, in.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkBelongs(ctx, a, meta); err != nil {
+		logrus.Warnf("evaluator %s uploaded file %s (%s) for script %s: %v", in.EvaluatorID, in.FileName, cid, scriptID, err)
+		return nil, err
 	}
+
+	row, err := s.pg.UpsertAnnotatedScript(ctx, db.AnnotatedScriptRow{
+		AssignmentID: a.ID,
+		ScriptID:     scriptID,
+		EvaluatorID:  in.EvaluatorID,
+		CID:          cid,
+		SHA256:       in.SHA256,
+		FileName:     in.FileName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("annotated script %s uploaded but not recorded: %w", cid, err)
+	}
+	row.ScriptID = ref // never hand the real id back in blind mode
+	return row, nil
+}
+
+// checkBelongs rejects a file that cannot be matched to the script. Outside
+// blind mode its header must name the script's course or student, and no
+// readable field may name another; fields the extractor could not read (a
+// handwritten cover) are not held against the upload. In blind mode the
+// header is masked, so the copy must instead carry the dummy number stamped
+// on the masked script.
+func (s *UploadService) checkBelongs(ctx context.Context, a *db.AssignedScriptRow, meta map[string]string) error {
+	stamp, err := s.blind.Stamp(ctx, a.ScriptID)
+	if err != nil {
+		return fmt.Errorf("dummy number lookup failed: %w", err)
+	}
+	matched := false
+	if stamp != "" {
+		switch d := extracted(meta["DummyNumber"]); {
+		case d == "":
+			return fmt.Errorf("%w: dummy number %s is not readable on the file", ErrWrongScript, stamp)
+		case !strings.EqualFold(d, stamp):
+			return fmt.Errorf("%w: file is script %s, not %s", ErrWrongScript, d, stamp)
+		}
+		matched = true
+	}
+	if c := extracted(meta["CourseID"]); c != "" {
+		if !strings.EqualFold(c, a.CourseID) {
+			return fmt.Errorf("%w: file is for course %s, script is for %s", ErrWrongScript, c, a.CourseID)
+		}
+		matched = true
+	}
+	if u := extracted(meta["USN"]); u != "" {
+		if want := uploadedUSN(s.store, a.ScriptID); want != "" {
+			if !strings.EqualFold(u, want) {
+				return fmt.Errorf("%w: file is another student's script", ErrWrongScript)
+			}
+			matched = true
+		}
+	}
+	if !matched {
+		return fmt.Errorf("%w: neither the course nor the student is readable on the file", ErrWrongScript)
+	}
+	return nil
+}
+
+// extracted returns an extractor field, "" when it could not be read.
+func extracted(v string) string {
+	v = strings.TrimSpace(v)
+	if strings.HasPrefix(v, "UNKNOWN") {
+		return ""
+	}
+	return v
 }
 
-// UploadEvaluatedScript sends the local file to Python service for IPFS upload.
-// Returns the IPFS CID and the PDF path (if returned by extractor).
-func (s *UploadService) UploadEvaluatedScript(ctx context.Context, filePath string) (string, string, error) {
-	// We reuse pybridge.Extract because it handles the IPFS upload logic.
-	// Even though we might not need the metadata extraction, this is the established path for "File -> IPFS".
+// upload sends the local file to the Python service for IPFS upload and
+// returns its CID and the metadata read from its header.
+func (s *UploadService) upload(ctx context.Context, filePath string) (string, map[string]string, error) {
+	// pybridge.Extract is the established path for "File -> IPFS"; the header
+	// metadata it reads lets checkBelongs match the file to the script.
 	resp, err := s.pyClient.Extract(ctx, filePath)
 	if err != nil {
 		logrus.Warnf("evaluator upload: pybridge extract failed: %v", err)
-		return "", "", fmt.Errorf("upload failed: %w", err)
+		return "", nil, fmt.Errorf("upload failed: %w", err)
 	}
 
 	if resp.Status != "success" {
-		return "", "", fmt.Errorf("upload returned non-success status: %s (error: %s)", resp.Status, resp.Error)
+		return "", nil, fmt.Errorf("upload returned non-success status: %s (error: %s)", resp.Status, resp.Error)
 	}
+	if resp.PDFCid == "" {
+		return "", nil, fmt.Errorf("upload returned no CID")
+	}
+	return resp.PDFCid, resp.Metadata, nil
+}
 
-	// Return CID and Path
-	return resp.PDFCid, resp.PDFPath, nil
+// AnnotatedScripts returns every annotated copy uploaded for a script.
+func (s *UploadService) AnnotatedScripts(ctx context.Context, scriptID string) ([]db.AnnotatedScriptRow, error) {
+	return s.pg.ListAnnotatedScripts(ctx, scriptID)
 }
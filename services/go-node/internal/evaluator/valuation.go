@@ -0,0 +1,228 @@
+package evaluator
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"digital-eval-system/services/go-node/internal/block"
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/outbox"
+	"digital-eval-system/services/go-node/internal/valuation"
+)
+
+// valuationStage hands present scripts of courses under a double valuation
+// policy to submitValuation, which completes the submission. Absent and
+// withheld scripts are finalised by the first valuation (see
+// closeRoundsStage).
+func (s *SubmitService) valuationStage(ctx context.Context, sub *Submission) error {
+	if !sub.Present {
+		return nil
+	}
+	policy := s.valuation.Resolve(ctx, sub.Payload.CourseID, sub.Payload.Semester)
+	if !policy.Double() {
+		return nil
+	}
+	blockHash, err := s.submitValuation(ctx, sub)
+	if err != nil {
+		return err
+	}
+	sub.BlockHash = blockHash
+	sub.Done = true
+	return nil
+}
+
+// closeRoundsStage runs once a script under a double valuation policy has
+// been finalised as absent or withheld by one evaluator: there is nothing
+// for the other rounds to value, so their open assignments are revoked
+// rather than left to expire and be reassigned.
+func (s *SubmitService) closeRoundsStage(ctx context.Context, sub *Submission) error {
+	if sub.Present || !s.valuation.Resolve(ctx, sub.Payload.CourseID, sub.Payload.Semester).Double() {
+		return nil
+	}
+	reason := fmt.Sprintf("script finalised as %s", sub.Result)
+	if n, err := s.valuation.CloseRounds(ctx, sub.Payload.ScriptID, sub.Assignment.ID, reason); err != nil {
+		logrus.Warnf("failed to close the other valuation rounds of script %s: %v", sub.Payload.ScriptID, err)
+	} else if n > 0 {
+		logrus.Infof("script %s %s: revoked %d other valuation assignment(s)", sub.Payload.ScriptID, reason, n)
+	}
+	return nil
+}
+
+// submitValuation records one independent valuation of a script under a
+// double valuation policy. Every valuation is its own block (Meta
+// "_valuation") whose valuations row is written through the outbox; once
+// stored, storeValuation settles the script. Returns the hash of the
+// valuation block.
+func (s *SubmitService) submitValuation(ctx context.Context, sub *Submission) (string, error) {
+	payload, assigned, marksStruct := sub.Payload, sub.Assignment, sub.Marks
+	marksStruct["valuation_round"] = assigned.ValuationRound
+	marksJSON, _ := json.Marshal(marksStruct)
+
+	tx := block.Transaction{
+		ScriptID:     payload.ScriptID,
+		CourseID:     payload.CourseID,
+		Semester:     payload.Semester,
+		AcademicYear: payload.AcademicYear,
+		Meta:         map[string]string{"_valuation": string(marksJSON)},
+		CreatedAt:    time.Now().Unix(),
+		SignerID:     payload.EvaluatorID,
+	}
+	blockHash, pending, err := appendLinked(ctx, s.outbox, s.chain, s.store, tx, valuationEffect(sub, marksJSON))
+	if err != nil {
+		return "", fmt.Errorf("append block failed: %w", err)
+	}
+	if err := s.outbox.Deliver(ctx, pending...); err != nil {
+		logrus.Warnf("valuation of script %s queued for retry: %v", payload.ScriptID, err)
+	}
+	return blockHash, nil
+}
+
+// valuationEffect is the valuations row written for a valuation block.
+func valuationEffect(sub *Submission, marksJSON []byte) outbox.Effect {
+	p := sub.Payload
+	v := outbox.Valuation{
+		ScriptID:       p.ScriptID,
+		AssignmentID:   sub.Assignment.ID,
+		EvaluatorID:    p.EvaluatorID,
+		ValuationRound: sub.Assignment.ValuationRound,
+		CourseID:       p.CourseID,
+		Semester:       p.Semester,
+		AcademicYear:   p.AcademicYear,
+		Marks:          marksJSON,
+		Score:          sub.Score,
+		TotalMarks:     p.TotalMarks,
+		StudentUSN:     sub.StudentUSN,
+		CourseCredits:  sub.Course.Credits,
+		Regulation:     sub.Course.Regulation,
+	}
+	if a := sub.Annotated; a != nil {
+		v.AnnotatedCID, v.AnnotatedSHA256 = a.CID, a.SHA256
+	}
+	if len(p.Remarks) > 0 || len(p.Annotations) > 0 {
+		v.Remarks, v.Annotations, _ = reviewJSON(p)
+	}
+	return outbox.Effect{Kind: outbox.KindValuation, Payload: v}
+}
+
+// storeValuation is the outbox applier of valuation blocks: it writes the
+// valuations row, closes the assignment and settles the script. Replays
+// find the row and the final evaluation already written.
+func (s *SubmitService) storeValuation(ctx context.Context, it *outbox.Item) error {
+	var v outbox.Valuation
+	if err := json.Unmarshal(it.Payload, &v); err != nil {
+		return err
+	}
+	if _, err := s.pg.InsertValuation(ctx, db.ValuationRow{
+		ScriptID:       v.ScriptID,
+		AssignmentID:   sql.NullInt64{Int64: v.AssignmentID, Valid: v.AssignmentID != 0},
+		EvaluatorID:    v.EvaluatorID,
+		ValuationRound: v.ValuationRound,
+		CourseID:       v.CourseID,
+		Semester:       v.Semester,
+		AcademicYear:   v.AcademicYear,
+		Marks:          v.Marks,
+		Score:          v.Score,
+		TotalMarks:     v.TotalMarks,
+		BlockHash:      it.BlockHash,
+	}); err != nil {
+		return err
+	}
+	if v.AssignmentID != 0 {
+		if err := s.pg.UpdateAssignmentStatus(ctx, v.AssignmentID, "evaluated"); err != nil {
+			return err
+		}
+		s.clearDraft(ctx, v.AssignmentID)
+	}
+	return s.settleValuations(ctx, &v)
+}
+
+// settleValuations applies the course policy to the valuations stored so
+// far: a discrepancy above the threshold assigns a third valuation, settled
+// marks are written as the final evaluation.
+func (s *SubmitService) settleValuations(ctx context.Context, v *outbox.Valuation) error {
+	s.settleMu.Lock()
+	defer s.settleMu.Unlock()
+	done, err := s.pg.EvaluationExistsForScript(ctx, v.ScriptID)
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+	policy := s.valuation.Resolve(ctx, v.CourseID, v.Semester)
+	vals, outcome, err := s.valuation.Outcome(ctx, policy, v.ScriptID, v.TotalMarks)
+	if err != nil {
+		return fmt.Errorf("valuation outcome failed: %w", err)
+	}
+	switch outcome.Status {
+	case valuation.StatusThirdRequired:
+		evaluatorID, err := s.valuation.AssignThird(ctx, v.ScriptID, "")
+		if err != nil {
+			// stays on the authority's pending third valuation list
+			logrus.Warnf("script %s needs a third valuation (discrepancy %d > %.2f): %v", v.ScriptID, outcome.Discrepancy, outcome.Threshold, err)
+		} else {
+			logrus.Infof("script %s: discrepancy %d > %.2f, third valuation assigned to %s", v.ScriptID, outcome.Discrepancy, outcome.Threshold, evaluatorID)
+		}
+	case valuation.StatusFinal:
+		return s.finalizeValuations(ctx, v, policy, vals, outcome)
+	}
+	return nil
+}
+
+// finalizeValuations appends the final evaluation block carrying the
+// aggregated marks and the valuations it was derived from, then stores the
+// evaluation row through the outbox. v is the valuation that settled the
+// script; its marks document is the base of the final one.
+func (s *SubmitService) finalizeValuations(ctx context.Context, v *outbox.Valuation, policy *valuation.Policy, vals []valuation.Valuation, outcome valuation.Outcome) error {
+	final := map[string]interface{}{}
+	if err := json.Unmarshal(v.Marks, &final); err != nil {
+		return fmt.Errorf("valuation marks unreadable: %w", err)
+	}
+	delete(final, "valuation_round")
+	final["marks_scored"] = outcome.MarksScored
+	final["final_score"] = outcome.FinalScore
+	final["aggregation"] = policy.Aggregation
+	final["rounds_used"] = outcome.Rounds
+	final["valuations"] = vals
+	marksJSON, _ := json.Marshal(final)
+
+	tx := block.Transaction{
+		ScriptID:     v.ScriptID,
+		CourseID:     v.CourseID,
+		Semester:     v.Semester,
+		AcademicYear: v.AcademicYear,
+		Meta:         map[string]string{"_evaluation": string(marksJSON)},
+		CreatedAt:    time.Now().Unix(),
+		SignerID:     v.EvaluatorID,
+	}
+	result := s.grading.Resolve(ctx, v.Regulation, v.AcademicYear).Result(outcome.FinalScore, v.TotalMarks)
+	_, pending, err := appendLinked(ctx, s.outbox, s.chain, s.store, tx, outbox.Effect{Kind: outbox.KindEvaluationResult, Payload: outbox.EvaluationResult{
+		ScriptID:        v.ScriptID,
+		StudentUSN:      v.StudentUSN,
+		CourseID:        v.CourseID,
+		Semester:        v.Semester,
+		AcademicYear:    v.AcademicYear,
+		CourseCredits:   v.CourseCredits,
+		EvaluatorID:     v.EvaluatorID,
+		Marks:           marksJSON,
+		TotalMarks:      v.TotalMarks,
+		Result:          result,
+		AnnotatedCID:    v.AnnotatedCID,
+		AnnotatedSHA256: v.AnnotatedSHA256,
+		Remarks:         v.Remarks,
+		Annotations:     v.Annotations,
+	}})
+	if err != nil {
+		return fmt.Errorf("append final evaluation block failed: %w", err)
+	}
+	if err := s.outbox.Deliver(ctx, pending...); err != nil {
+		logrus.Warnf("evaluation result of script %s queued for retry: %v", v.ScriptID, err)
+	}
+	logrus.Infof("script %s finalised from valuations %v: %d/%d (%s)", v.ScriptID, outcome.Rounds, outcome.FinalScore, v.TotalMarks, result)
+	return nil
+}
@@ -0,0 +1,133 @@
+package evaluator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"digital-eval-system/services/go-node/internal/outbox"
+	"digital-eval-system/services/go-node/internal/valuation"
+)
+
+// fakeValuations applies one policy to every course and records the
+// rounds it is asked to close.
+type fakeValuations struct {
+	policy *valuation.Policy
+	closed *[]string
+}
+
+func (f fakeValuations) Resolve(context.Context, string, string) *valuation.Policy { return f.policy }
+
+func (f fakeValuations) Outcome(context.Context, *valuation.Policy, string, int) ([]valuation.Valuation, valuation.Outcome, error) {
+	return nil, valuation.Outcome{Status: valuation.StatusAwaiting}, nil
+}
+
+func (f fakeValuations) AssignThird(context.Context, string, string) (string, error) {
+	return "", errors.New("not expected")
+}
+
+func (f fakeValuations) CloseRounds(_ context.Context, scriptID string, keep int64, reason string) (int, error) {
+	*f.closed = append(*f.closed, fmt.Sprintf("%s keep %d: %s", scriptID, keep, reason))
+	return 1, nil
+}
+
+func doublePolicy() *valuation.Policy {
+	p := valuation.Default()
+	p.Mode = valuation.ModeDouble
+	return p
+}
+
+func TestValuationStage(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    *valuation.Policy
+		chainErr  error
+		applyErr  error
+		wantErr   string
+		wantDone  bool
+		wantRows  int
+		wantQueue int
+	}{
+		{name: "single valuation continues the pipeline", policy: valuation.Default()},
+		{name: "valuation recorded", policy: doublePolicy(), wantDone: true, wantRows: 1},
+		{name: "valuation row queued when postgres fails", policy: doublePolicy(), applyErr: errors.New("postgres down"), wantDone: true, wantQueue: 1},
+		{name: "failed append", policy: doublePolicy(), chainErr: errors.New("disk full"), wantErr: "append block failed: disk full"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ob, store, ch, rows := newOutbox(outbox.KindValuation)
+			ch.err, rows.err = tc.chainErr, tc.applyErr
+			s := &SubmitService{store: store, chain: ch, outbox: ob, valuation: fakeValuations{policy: tc.policy, closed: new([]string)}}
+
+			sub := chainSubmission()
+			sub.Assignment.ValuationRound = 2
+			err := StageFunc(StageValuation, s.valuationStage).Run(context.Background(), sub)
+			checkErr(t, err, tc.wantErr)
+			if err != nil {
+				return
+			}
+			if sub.Done != tc.wantDone || len(rows.items) != tc.wantRows || len(store.items) != tc.wantQueue {
+				t.Fatalf("done %v, rows %d, queued %d; want %v, %d, %d", sub.Done, len(rows.items), len(store.items), tc.wantDone, tc.wantRows, tc.wantQueue)
+			}
+			if !tc.wantDone {
+				if sub.BlockHash != "" || len(store.blocks) != 0 {
+					t.Fatalf("block written: %q", sub.BlockHash)
+				}
+				return
+			}
+			blk, err := store.GetBlock(sub.BlockHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var marks map[string]interface{}
+			if err := json.Unmarshal([]byte(blk.Transactions[0].Meta["_valuation"]), &marks); err != nil {
+				t.Fatal(err)
+			}
+			if marks["valuation_round"] != float64(2) {
+				t.Errorf("valuation block marks = %v", marks)
+			}
+			if tc.wantRows == 0 {
+				return
+			}
+			var v outbox.Valuation
+			if err := json.Unmarshal(rows.items[0].Payload, &v); err != nil {
+				t.Fatal(err)
+			}
+			if v.AssignmentID != 7 || v.ValuationRound != 2 || v.Score != 60 || v.StudentUSN != "1BI21CS001" || v.Regulation != "R2021" || v.AnnotatedCID != "bafy1" {
+				t.Errorf("valuation row = %+v", v)
+			}
+		})
+	}
+}
+
+func TestCloseRoundsStage(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *valuation.Policy
+		result string
+		want   []string
+	}{
+		{name: "absent under double valuation", policy: doublePolicy(), result: "AB", want: []string{"S1 keep 7: script finalised as AB"}},
+		{name: "withheld under double valuation", policy: doublePolicy(), result: "WH", want: []string{"S1 keep 7: script finalised as WH"}},
+		{name: "present scripts settle through their valuations", policy: doublePolicy()},
+		{name: "single valuation has no other rounds", policy: valuation.Default(), result: "AB"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			closed := []string{}
+			s := &SubmitService{valuation: fakeValuations{policy: tc.policy, closed: &closed}}
+			sub := chainSubmission()
+			if tc.result != "" {
+				sub.Present, sub.Result = false, tc.result
+			}
+			if err := StageFunc(StageCloseRounds, s.closeRoundsStage).Run(context.Background(), sub); err != nil {
+				t.Fatal(err)
+			}
+			if len(closed) != len(tc.want) || (len(tc.want) > 0 && closed[0] != tc.want[0]) {
+				t.Errorf("closed %v, want %v", closed, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package exampattern
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler exposes exam pattern administration endpoints.
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GET /api/v1/admin/exam-patterns
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	patterns, err := h.svc.List(r.Context())
+	if err != nil {
+		http.Error(w, "failed to load exam patterns", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, patterns, http.StatusOK)
+}
+
+// GET /api/v1/admin/exam-patterns/{code}
+// Returns the stored pattern, or the built-in one for DEFAULT.
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	p, err := h.svc.Get(r.Context(), mux.Vars(r)["code"])
+	if err != nil {
+		http.Error(w, "failed to load exam pattern", http.StatusInternalServerError)
+		return
+	}
+	if p == nil {
+		http.Error(w, "exam pattern not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"pattern": p, "leaves": p.Leaves(), "max_total": p.MaxTotal()}, http.StatusOK)
+}
+
+// POST /api/v1/admin/exam-patterns
+// Creates the pattern or replaces the one stored under the same code.
+func (h *Handler) Save(w http.ResponseWriter, r *http.Request) {
+	var p Pattern
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	p.Normalize()
+	if err := p.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.Save(r.Context(), &p); err != nil {
+		http.Error(w, "save failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"code": p.Code, "leaves": p.Leaves()}, http.StatusOK)
+}
+
+// DELETE /api/v1/admin/exam-patterns/{code}
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.Delete(r.Context(), mux.Vars(r)["code"]); err != nil {
+		if err == ErrInUse {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "delete failed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "deleted"}, http.StatusOK)
+}
+
+// POST /api/v1/admin/exam-patterns/{code}/check
+// Body: {"marks_scored":[...]}. Validates marks and previews the total.
+func (h *Handler) Check(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		MarksScored []int `json:"marks_scored"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	p, err := h.svc.Get(r.Context(), mux.Vars(r)["code"])
+	if err != nil {
+		http.Error(w, "failed to load exam pattern", http.StatusInternalServerError)
+		return
+	}
+	if p == nil {
+		http.Error(w, "exam pattern not found", http.StatusNotFound)
+		return
+	}
+	total, err := p.Check(body.MarksScored)
+	if err != nil {
+		writeJSON(w, map[string]interface{}{"valid": false, "error": err.Error()}, http.StatusOK)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"valid": true, "total": total, "out_of": p.TotalMarks}, http.StatusOK)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func RegisterExamPatternRoutes(r *mux.Router, svc *Service) {
+	h := NewHandler(svc)
+	r.HandleFunc("/admin/exam-patterns", h.List).Methods("GET")
+	r.HandleFunc("/admin/exam-patterns", h.Save).Methods("POST")
+	r.HandleFunc("/admin/exam-patterns/{code}", h.Get).Methods("GET")
+	r.HandleFunc("/admin/exam-patterns/{code}", h.Delete).Methods("DELETE")
+	r.HandleFunc("/admin/exam-patterns/{code}/check", h.Check).Methods("POST")
+}
@@ -0,0 +1,343 @@
+package exampattern
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultCode names the built-in pattern used by courses without one.
+const DefaultCode = "DEFAULT"
+
+// Pattern describes how a paper is marked: sections of questions, optional
+// sub-questions, choice rules (any k of n) and maximum marks. Marks are
+// entered as one flat list in Leaves order.
+type Pattern struct {
+	Code         string    `json:"code"`
+	Name         string    `json:"name,omitempty"`
+	TotalMarks   int       `json:"total_marks"`
+	MinAttempted int       `json:"min_attempted,omitempty"` // questions that must carry marks
+	Sections     []Section `json:"sections"`
+}
+
+// Section groups questions; with Choose > 0 only the best Choose question
+// scores count. MaxMarks, when set, caps the section total.
+type Section struct {
+	Name      string     `json:"name"`
+	Choose    int        `json:"choose,omitempty"`
+	MaxMarks  int        `json:"max_marks,omitempty"`
+	Questions []Question `json:"questions"`
+}
+
+// Question carries marks directly or through sub-questions; with Choose > 0
+// only the best Choose sub-questions count. MaxMarks caps the question.
+type Question struct {
+	Label        string        `json:"label"`
+	MaxMarks     int           `json:"max_marks,omitempty"`
+	Choose       int           `json:"choose,omitempty"`
+	SubQuestions []SubQuestion `json:"sub_questions,omitempty"`
+}
+
+// SubQuestion is a marked part of a question.
+type SubQuestion struct {
+	Label    string `json:"label"`
+	MaxMarks int    `json:"max_marks"`
+}
+
+// Leaf is one entry of the flat marks list.
+type Leaf struct {
+	Label    string `json:"label"`
+	MaxMarks int    `json:"max_marks"`
+}
+
+// Default is the university's theory paper: 5 modules of 2 questions (20
+// marks each), best of 2 per module, at least 5 questions attempted, out of 100.
+func Default() *Pattern {
+	p := Pairs(10)
+	p.Code = DefaultCode
+	p.Name = "5 modules, best of 2"
+	p.TotalMarks = 100
+	p.MinAttempted = 5
+	for i := range p.Sections {
+		for j := range p.Sections[i].Questions {
+			p.Sections[i].Questions[j].MaxMarks = 20
+		}
+	}
+	return p
+}
+
+// Pairs is the legacy best-of-2 reading of n marks with no maximums; it
+// scores evaluations recorded before patterns existed.
+func Pairs(n int) *Pattern {
+	p := &Pattern{Code: "PAIRS"}
+	for i := 0; i < n; i += 2 {
+		sec := Section{Name: fmt.Sprintf("Module %d", i/2+1), Choose: 1}
+		for q := i; q < n && q < i+2; q++ {
+			sec.Questions = append(sec.Questions, Question{Label: fmt.Sprintf("Q%d", q+1)})
+		}
+		p.Sections = append(p.Sections, sec)
+	}
+	return p
+}
+
+// Normalize trims labels, upper-cases the code and fills TotalMarks from the
+// structure when unset.
+func (p *Pattern) Normalize() {
+	p.Code = strings.ToUpper(strings.TrimSpace(p.Code))
+	p.Name = strings.TrimSpace(p.Name)
+	for i := range p.Sections {
+		s := &p.Sections[i]
+		s.Name = strings.TrimSpace(s.Name)
+		for j := range s.Questions {
+			q := &s.Questions[j]
+			q.Label = strings.TrimSpace(q.Label)
+			if q.Label == "" {
+				q.Label = fmt.Sprintf("Q%d", j+1)
+			}
+			for k := range q.SubQuestions {
+				q.SubQuestions[k].Label = strings.TrimSpace(q.SubQuestions[k].Label)
+				if q.SubQuestions[k].Label == "" {
+					q.SubQuestions[k].Label = string(rune('a' + k))
+				}
+			}
+		}
+	}
+	if p.TotalMarks == 0 {
+		p.TotalMarks = p.MaxTotal()
+	}
+}
+
+// Validate checks that the definition is usable for marking.
+func (p *Pattern) Validate() error {
+	if p.Code == "" {
+		return fmt.Errorf("code is required")
+	}
+	if len(p.Sections) == 0 {
+		return fmt.Errorf("at least one section is required")
+	}
+	questions := 0
+	for i, s := range p.Sections {
+		if len(s.Questions) == 0 {
+			return fmt.Errorf("section %d has no questions", i+1)
+		}
+		if s.Choose < 0 || s.Choose > len(s.Questions) {
+			return fmt.Errorf("section %d: choose must be between 0 and %d", i+1, len(s.Questions))
+		}
+		if s.MaxMarks < 0 {
+			return fmt.Errorf("section %d: max_marks must be >= 0", i+1)
+		}
+		for _, q := range s.Questions {
+			questions++
+			if q.MaxMarks < 0 {
+				return fmt.Errorf("question %s: max_marks must be >= 0", q.Label)
+			}
+			if len(q.SubQuestions) == 0 {
+				if q.MaxMarks == 0 {
+					return fmt.Errorf("question %s needs max_marks or sub_questions", q.Label)
+				}
+				if q.Choose != 0 {
+					return fmt.Errorf("question %s: choose needs sub_questions", q.Label)
+				}
+				continue
+			}
+			if q.Choose < 0 || q.Choose > len(q.SubQuestions) {
+				return fmt.Errorf("question %s: choose must be between 0 and %d", q.Label, len(q.SubQuestions))
+			}
+			for _, sq := range q.SubQuestions {
+				if sq.MaxMarks <= 0 {
+					return fmt.Errorf("question %s%s: max_marks must be > 0", q.Label, sq.Label)
+				}
+			}
+		}
+	}
+	if p.MinAttempted < 0 || p.MinAttempted > questions {
+		return fmt.Errorf("min_attempted must be between 0 and %d", questions)
+	}
+	if max := p.MaxTotal(); p.TotalMarks <= 0 || p.TotalMarks > max {
+		return fmt.Errorf("total_marks must be between 1 and %d (the most the pattern can award)", max)
+	}
+	return nil
+}
+
+// Leaves lists the entries of the flat marks list in order: each question,
+// or each of its sub-questions.
+func (p *Pattern) Leaves() []Leaf {
+	var out []Leaf
+	for _, s := range p.Sections {
+		for _, q := range s.Questions {
+			if len(q.SubQuestions) == 0 {
+				out = append(out, Leaf{Label: q.Label, MaxMarks: q.MaxMarks})
+				continue
+			}
+			for _, sq := range q.SubQuestions {
+				out = append(out, Leaf{Label: q.Label + sq.Label, MaxMarks: sq.MaxMarks})
+			}
+		}
+	}
+	return out
+}
+
+// MaxTotal is the most the pattern can award.
+func (p *Pattern) MaxTotal() int {
+	var maxima []int
+	for _, s := range p.Sections {
+		for _, q := range s.Questions {
+			qm := q.MaxMarks
+			if len(q.SubQuestions) > 0 {
+				subs := make([]int, 0, len(q.SubQuestions))
+				for _, sq := range q.SubQuestions {
+					subs = append(subs, sq.MaxMarks)
+				}
+				if sum := bestSum(subs, q.Choose); qm == 0 || sum < qm {
+					qm = sum
+				}
+			}
+			maxima = append(maxima, qm)
+		}
+	}
+	return p.total(maxima)
+}
+
+// Score totals marks under the pattern without validating them: missing
+// entries count as 0 and extra entries are ignored.
+func (p *Pattern) Score(marks []int) int {
+	return p.total(p.questionScores(marks))
+}
+
+// Check validates marks against the pattern and returns the total.
+func (p *Pattern) Check(marks []int) (int, error) {
+	leaves := p.Leaves()
+	if len(marks) != len(leaves) {
+		return 0, fmt.Errorf("pattern %s expects %d marks, got %d", p.Code, len(leaves), len(marks))
+	}
+	for i, m := range marks {
+		if m < 0 {
+			return 0, fmt.Errorf("%s: marks cannot be negative", leaves[i].Label)
+		}
+		if leaves[i].MaxMarks > 0 && m > leaves[i].MaxMarks {
+			return 0, fmt.Errorf("%s: %d exceeds maximum %d", leaves[i].Label, m, leaves[i].MaxMarks)
+		}
+	}
+	scores := p.questionScores(marks)
+	attempted := 0
+	for _, qs := range p.attempted(marks) {
+		if qs {
+			attempted++
+		}
+	}
+	if attempted < p.MinAttempted {
+		return 0, fmt.Errorf("minimum %d questions must be attempted (got %d)", p.MinAttempted, attempted)
+	}
+	total := p.total(scores)
+	if p.TotalMarks > 0 && total > p.TotalMarks {
+		return 0, fmt.Errorf("total calculated score %d exceeds maximum %d", total, p.TotalMarks)
+	}
+	return total, nil
+}
+
+// questionScores returns one score per question in pattern order.
+func (p *Pattern) questionScores(marks []int) []int {
+	at := func(i int) int {
+		if i < len(marks) {
+			return marks[i]
+		}
+		return 0
+	}
+	var out []int
+	i := 0
+	for _, s := range p.Sections {
+		for _, q := range s.Questions {
+			var qs int
+			if len(q.SubQuestions) == 0 {
+				qs = at(i)
+				i++
+			} else {
+				subs := make([]int, 0, len(q.SubQuestions))
+				for range q.SubQuestions {
+					subs = append(subs, at(i))
+					i++
+				}
+				qs = bestSum(subs, q.Choose)
+			}
+			if q.MaxMarks > 0 && qs > q.MaxMarks {
+				qs = q.MaxMarks
+			}
+			out = append(out, qs)
+		}
+	}
+	return out
+}
+
+// attempted reports per question whether any of its marks is non-zero.
+func (p *Pattern) attempted(marks []int) []bool {
+	var out []bool
+	i := 0
+	for _, s := range p.Sections {
+		for _, q := range s.Questions {
+			n := len(q.SubQuestions)
+			if n == 0 {
+				n = 1
+			}
+			any := false
+			for k := 0; k < n; k++ {
+				if i < len(marks) && marks[i] > 0 {
+					any = true
+				}
+				i++
+			}
+			out = append(out, any)
+		}
+	}
+	return out
+}
+
+// total applies the section choice rules and caps to per-question scores.
+func (p *Pattern) total(scores []int) int {
+	sum, i := 0, 0
+	for _, s := range p.Sections {
+		n := len(s.Questions)
+		if i+n > len(scores) {
+			n = len(scores) - i
+		}
+		st := bestSum(scores[i:i+n], s.Choose)
+		i += n
+		if s.MaxMarks > 0 && st > s.MaxMarks {
+			st = s.MaxMarks
+		}
+		sum += st
+	}
+	return sum
+}
+
+// bestSum adds the k largest values (all when k == 0).
+func bestSum(v []int, k int) int {
+	s := append([]int(nil), v...)
+	sort.Sort(sort.Reverse(sort.IntSlice(s)))
+	if k <= 0 || k > len(s) {
+		k = len(s)
+	}
+	sum := 0
+	for _, x := range s[:k] {
+		sum += x
+	}
+	return sum
+}
+
+// FromMarks returns the pattern recorded in an evaluation's marks JSON at
+// submission, falling back to the legacy best-of-2 reading for evaluations
+// recorded before patterns (the Default pattern for 10 marks).
+func FromMarks(raw []byte) *Pattern {
+	var m struct {
+		ExamPattern *Pattern `json:"exam_pattern"`
+		MarksScored []int    `json:"marks_scored"`
+	}
+	_ = json.Unmarshal(raw, &m)
+	if m.ExamPattern != nil && len(m.ExamPattern.Sections) > 0 {
+		return m.ExamPattern
+	}
+	if len(m.MarksScored) == 10 {
+		return Default()
+	}
+	return Pairs(len(m.MarksScored))
+}
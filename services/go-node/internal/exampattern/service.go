@@ -0,0 +1,112 @@
+package exampattern
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"digital-eval-system/services/go-node/internal/db"
+)
+
+// ErrInUse is returned when deleting a pattern that courses still reference.
+var ErrInUse = errors.New("exam pattern is referenced by courses")
+
+// Service loads and stores exam patterns in Postgres.
+type Service struct {
+	pg *db.PostgresDB
+}
+
+func NewService(pg *db.PostgresDB) *Service {
+	return &Service{pg: pg}
+}
+
+// Get returns the pattern with the given code. An empty code or DEFAULT
+// without a stored override yields the built-in Default. Returns nil, nil
+// for an unknown code.
+func (s *Service) Get(ctx context.Context, code string) (*Pattern, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		code = DefaultCode
+	}
+	if s != nil && s.pg != nil {
+		row, err := s.pg.GetExamPattern(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		if row != nil {
+			return fromRow(row)
+		}
+	}
+	if code == DefaultCode {
+		return Default(), nil
+	}
+	return nil, nil
+}
+
+// Resolve returns the pattern a course is marked under. Unlike Get an unknown
+// code is an error: marks must never be validated against a guessed pattern.
+func (s *Service) Resolve(ctx context.Context, code string) (*Pattern, error) {
+	p, err := s.Get(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("exam pattern lookup failed: %w", err)
+	}
+	if p == nil {
+		return nil, fmt.Errorf("exam pattern %q is not defined", code)
+	}
+	return p, nil
+}
+
+// List returns every stored pattern.
+func (s *Service) List(ctx context.Context) ([]*Pattern, error) {
+	rows, err := s.pg.ListExamPatterns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*Pattern, 0, len(rows))
+	for i := range rows {
+		p, err := fromRow(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// Save validates and stores a pattern, replacing any with the same code.
+func (s *Service) Save(ctx context.Context, p *Pattern) error {
+	p.Normalize()
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	def, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return s.pg.UpsertExamPattern(ctx, db.ExamPatternRow{Code: p.Code, Name: p.Name, Definition: def})
+}
+
+// Delete removes a stored pattern unless a course still references it.
+func (s *Service) Delete(ctx context.Context, code string) error {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	n, err := s.pg.CountCoursesWithExamPattern(ctx, code)
+	if err != nil {
+		return err
+	}
+	if n > 0 && code != DefaultCode {
+		return ErrInUse
+	}
+	return s.pg.DeleteExamPattern(ctx, code)
+}
+
+func fromRow(r *db.ExamPatternRow) (*Pattern, error) {
+	var p Pattern
+	if err := json.Unmarshal(r.Definition, &p); err != nil {
+		return nil, fmt.Errorf("decode exam pattern %s: %w", r.Code, err)
+	}
+	p.Code = r.Code
+	p.Name = r.Name
+	return &p, nil
+}
@@ -0,0 +1,83 @@
+package grading
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler exposes grading scheme administration endpoints.
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GET /api/v1/admin/grading/schemes
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	schemes, err := h.svc.List(r.Context())
+	if err != nil {
+		http.Error(w, "failed to load schemes", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, schemes, http.StatusOK)
+}
+
+// POST /api/v1/admin/grading/schemes
+// Creates the scheme or replaces the one stored for the same regulation + academic year.
+func (h *Handler) Save(w http.ResponseWriter, r *http.Request) {
+	var sc Scheme
+	if err := json.NewDecoder(r.Body).Decode(&sc); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if err := sc.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	id, err := h.svc.Save(r.Context(), &sc)
+	if err != nil {
+		http.Error(w, "save failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"id": id}, http.StatusOK)
+}
+
+// DELETE /api/v1/admin/grading/schemes/{id}
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		http.Error(w, "delete failed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "deleted"}, http.StatusOK)
+}
+
+// GET /api/v1/admin/grading/resolve?regulation=...&academic_year=...
+// Shows which scheme applies (stored or built-in default).
+func (h *Handler) Resolve(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	writeJSON(w, h.svc.Resolve(r.Context(), q.Get("regulation"), q.Get("academic_year")), http.StatusOK)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func RegisterGradingRoutes(r *mux.Router, svc *Service) {
+	h := NewHandler(svc)
+	r.HandleFunc("/admin/grading/schemes", h.List).Methods("GET")
+	r.HandleFunc("/admin/grading/schemes", h.Save).Methods("POST")
+	r.HandleFunc("/admin/grading/schemes/{id}", h.Delete).Methods("DELETE")
+	r.HandleFunc("/admin/grading/resolve", h.Resolve).Methods("GET")
+}
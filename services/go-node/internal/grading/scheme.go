@@ -0,0 +1,162 @@
+package grading
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Result codes written to evaluations.result for attempted scripts.
+const (
+	ResultPass = "PASS"
+	ResultFail = "FAIL"
+)
+
+// Rounding modes applied to computed grade point averages.
+const (
+	RoundHalfUp = "half_up"
+	RoundFloor  = "floor"
+	RoundCeil   = "ceil"
+)
+
+// Grade is one band of a scheme: any percentage >= MinPercentage (and below
+// the next band) earns Letter and GradePoint.
+type Grade struct {
+	Letter        string  `json:"letter"`
+	MinPercentage float64 `json:"min_percentage"`
+	GradePoint    float64 `json:"grade_point"`
+}
+
+// Scheme is the grading policy for a regulation, optionally scoped to one
+// academic year.
+type Scheme struct {
+	ID             int64   `json:"id,omitempty"`
+	Regulation     string  `json:"regulation"`
+	AcademicYear   string  `json:"academic_year"`
+	PassPercentage float64 `json:"pass_percentage"`
+	AbsentCode     string  `json:"absent_code"`
+	WithheldCode   string  `json:"withheld_code"`
+	Rounding       string  `json:"rounding"`
+	Decimals       int     `json:"decimals"`
+	Grades         []Grade `json:"grades"`
+}
+
+// Default returns the built-in scheme used when no policy is stored. It matches
+// the thresholds that were hard-coded before schemes became configurable.
+func Default() *Scheme {
+	return &Scheme{
+		Regulation:     "DEFAULT",
+		PassPercentage: 36,
+		AbsentCode:     "AB",
+		WithheldCode:   "WH",
+		Rounding:       RoundHalfUp,
+		Decimals:       2,
+		Grades: []Grade{
+			{Letter: "O", MinPercentage: 90, GradePoint: 10},
+			{Letter: "A+", MinPercentage: 80, GradePoint: 9},
+			{Letter: "A", MinPercentage: 70, GradePoint: 8},
+			{Letter: "B+", MinPercentage: 60, GradePoint: 7},
+			{Letter: "B", MinPercentage: 50, GradePoint: 6},
+			{Letter: "C", MinPercentage: 40, GradePoint: 5},
+			{Letter: "F", MinPercentage: 0, GradePoint: 0},
+		},
+	}
+}
+
+// Validate checks the scheme is usable and sorts its grades from the highest
+// band down.
+func (s *Scheme) Validate() error {
+	if strings.TrimSpace(s.Regulation) == "" {
+		return fmt.Errorf("regulation is required")
+	}
+	if s.PassPercentage < 0 || s.PassPercentage > 100 {
+		return fmt.Errorf("pass_percentage must be between 0 and 100")
+	}
+	if strings.TrimSpace(s.AbsentCode) == "" || strings.TrimSpace(s.WithheldCode) == "" {
+		return fmt.Errorf("absent_code and withheld_code are required")
+	}
+	switch s.Rounding {
+	case RoundHalfUp, RoundFloor, RoundCeil:
+	case "":
+		s.Rounding = RoundHalfUp
+	default:
+		return fmt.Errorf("unknown rounding %q", s.Rounding)
+	}
+	if s.Decimals < 0 || s.Decimals > 4 {
+		return fmt.Errorf("decimals must be between 0 and 4")
+	}
+	if len(s.Grades) == 0 {
+		return fmt.Errorf("at least one grade is required")
+	}
+	sort.SliceStable(s.Grades, func(i, j int) bool { return s.Grades[i].MinPercentage > s.Grades[j].MinPercentage })
+	seen := map[string]bool{}
+	for i, g := range s.Grades {
+		if strings.TrimSpace(g.Letter) == "" {
+			return fmt.Errorf("grades[%d] letter is required", i)
+		}
+		if seen[g.Letter] {
+			return fmt.Errorf("duplicate grade letter %q", g.Letter)
+		}
+		seen[g.Letter] = true
+		if g.GradePoint < 0 {
+			return fmt.Errorf("grades[%d] grade_point negative", i)
+		}
+		if i > 0 && g.MinPercentage == s.Grades[i-1].MinPercentage {
+			return fmt.Errorf("grades %q and %q share min_percentage %.2f", s.Grades[i-1].Letter, g.Letter, g.MinPercentage)
+		}
+	}
+	if s.Grades[len(s.Grades)-1].MinPercentage > 0 {
+		return fmt.Errorf("lowest grade must start at 0%%")
+	}
+	return nil
+}
+
+// Percentage converts scored/total into a percentage (0 when total <= 0).
+func Percentage(scored, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return (float64(scored) / float64(total)) * 100.0
+}
+
+// GradeFor returns the band a percentage falls in.
+func (s *Scheme) GradeFor(perc float64) Grade {
+	for _, g := range s.Grades {
+		if perc >= g.MinPercentage {
+			return g
+		}
+	}
+	return Grade{Letter: "F"}
+}
+
+// IsPass reports whether a percentage meets the scheme's pass threshold.
+func (s *Scheme) IsPass(perc float64) bool {
+	return perc >= s.PassPercentage
+}
+
+// Result returns PASS or FAIL for an attempted script.
+func (s *Scheme) Result(scored, total int) string {
+	if total > 0 && s.IsPass(Percentage(scored, total)) {
+		return ResultPass
+	}
+	return ResultFail
+}
+
+// IsNonAttempt reports whether a stored result is the absent or withheld code.
+func (s *Scheme) IsNonAttempt(result string) bool {
+	return result == s.AbsentCode || result == s.WithheldCode
+}
+
+// Round applies the scheme's rounding rule to a computed average.
+func (s *Scheme) Round(v float64) float64 {
+	f := math.Pow(10, float64(s.Decimals))
+	switch s.Rounding {
+	case RoundFloor:
+		return math.Floor(v*f) / f
+	case RoundCeil:
+		return math.Ceil(v*f) / f
+	default:
+		return math.Round(v*f) / f
+	}
+}
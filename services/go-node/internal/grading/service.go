@@ -0,0 +1,106 @@
+package grading
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"digital-eval-system/services/go-node/internal/db"
+)
+
+// Service loads and stores grading schemes in Postgres.
+type Service struct {
+	pg *db.PostgresDB
+}
+
+func NewService(pg *db.PostgresDB) *Service {
+	return &Service{pg: pg}
+}
+
+// Resolve returns the scheme for a regulation and academic year, falling back
+// to the built-in Default when nothing is stored (or the lookup fails).
+func (s *Service) Resolve(ctx context.Context, regulation, academicYear string) *Scheme {
+	if s == nil || s.pg == nil {
+		return Default()
+	}
+	row, err := s.pg.FindGradingScheme(ctx, regulation, academicYear)
+	if err != nil {
+		logrus.Warnf("grading scheme lookup failed (regulation=%q year=%q): %v", regulation, academicYear, err)
+		return Default()
+	}
+	if row == nil {
+		return Default()
+	}
+	sc, err := fromRow(row)
+	if err != nil {
+		logrus.Warnf("grading scheme %d unusable: %v", row.ID, err)
+		return Default()
+	}
+	return sc
+}
+
+// List returns every stored scheme.
+func (s *Service) List(ctx context.Context) ([]*Scheme, error) {
+	rows, err := s.pg.ListGradingSchemes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*Scheme, 0, len(rows))
+	for i := range rows {
+		sc, err := fromRow(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, sc)
+	}
+	return out, nil
+}
+
+// Save validates and stores a scheme, replacing any existing scheme for the
+// same regulation and academic year.
+func (s *Service) Save(ctx context.Context, sc *Scheme) (int64, error) {
+	if err := sc.Validate(); err != nil {
+		return 0, err
+	}
+	grades, err := json.Marshal(sc.Grades)
+	if err != nil {
+		return 0, err
+	}
+	return s.pg.UpsertGradingScheme(ctx, db.GradingSchemeRow{
+		Regulation:     sc.Regulation,
+		AcademicYear:   sc.AcademicYear,
+		PassPercentage: sc.PassPercentage,
+		AbsentCode:     sc.AbsentCode,
+		WithheldCode:   sc.WithheldCode,
+		Rounding:       sc.Rounding,
+		Decimals:       sc.Decimals,
+		Grades:         grades,
+	})
+}
+
+// Delete removes a stored scheme.
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	return s.pg.DeleteGradingScheme(ctx, id)
+}
+
+func fromRow(r *db.GradingSchemeRow) (*Scheme, error) {
+	sc := &Scheme{
+		ID:             r.ID,
+		Regulation:     r.Regulation,
+		AcademicYear:   r.AcademicYear,
+		PassPercentage: r.PassPercentage,
+		AbsentCode:     r.AbsentCode,
+		WithheldCode:   r.WithheldCode,
+		Rounding:       r.Rounding,
+		Decimals:       r.Decimals,
+	}
+	if err := json.Unmarshal(r.Grades, &sc.Grades); err != nil {
+		return nil, fmt.Errorf("decode grades: %w", err)
+	}
+	if err := sc.Validate(); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
@@ -0,0 +1,54 @@
+package i18n
+
+var english = map[string]string{
+	// institute header
+	"institute.name":  "BAPUJI INSTITUTE OF ENGINEERING & TECHNOLOGY",
+	"institute.place": "DAVANAGERE - 577004",
+	"institute.short": "BIET Davangere",
+
+	// result / transcript PDFs
+	"pdf.result.title":     "STUDENT RESULT REPORT",
+	"pdf.transcript.title": "CONSOLIDATED TRANSCRIPT",
+	"pdf.usn":              "USN",
+	"pdf.semester":         "Semester",
+	"pdf.institute":        "Institute",
+	"pdf.exam_date":        "Exam Date",
+	"pdf.issued":           "Issued",
+	"pdf.academic_year":    "Academic Year",
+	"pdf.course_id":        "Course ID",
+	"pdf.course_name":      "Course Name",
+	"pdf.credits":          "Credits",
+	"pdf.marks":            "Marks",
+	"pdf.total":            "Total",
+	"pdf.grade":            "Grade",
+	"pdf.grade_point":      "GP",
+	"pdf.result":           "Result",
+	"pdf.total_scored":     "Total Marks Scored",
+	"pdf.sgpa_semester":    "SGPA (this semester)",
+	"pdf.sgpa":             "SGPA",
+	"pdf.cgpa":             "CGPA",
+	"pdf.credits_earned":   "Credits Earned",
+	"pdf.backlogs":         "Backlogs",
+	"pdf.none":             "None",
+
+	// remuneration statement PDF
+	"pdf.remuneration.title":    "EVALUATOR REMUNERATION STATEMENT",
+	"pdf.remuneration.unrated":  "%d scripts have no rate and are not paid; the statement cannot be approved until a rate covers them.",
+	"pdf.remuneration.approved": "Approved by %s on %s",
+	"pdf.remuneration.paid":     "Paid on %s, payout reference %s",
+	"pdf.evaluator":             "Evaluator",
+	"pdf.statement_no":          "Statement No",
+	"pdf.status":                "Status",
+	"pdf.computed":              "Computed",
+	"pdf.course":                "Course",
+	"pdf.course_type":           "Course Type",
+	"pdf.valuation":             "Valuation",
+	"pdf.scripts":               "Scripts",
+	"pdf.rate":                  "Rate",
+	"pdf.amount":                "Amount",
+	"pdf.no_rate":               "no rate",
+
+	// API messages
+	"api.results_not_released": "Results are not yet released",
+	"api.no_linked_usn":        "No USN is linked to this account",
+}
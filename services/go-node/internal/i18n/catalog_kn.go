@@ -0,0 +1,54 @@
+package i18n
+
+var kannada = map[string]string{
+	// institute header
+	"institute.name":  "ಬಾಪೂಜಿ ಇಂಜಿನಿಯರಿಂಗ್ ಮತ್ತು ತಂತ್ರಜ್ಞಾನ ಸಂಸ್ಥೆ",
+	"institute.place": "ದಾವಣಗೆರೆ - 577004",
+	"institute.short": "ಬಿ.ಐ.ಇ.ಟಿ ದಾವಣಗೆರೆ",
+
+	// result / transcript PDFs
+	"pdf.result.title":     "ವಿದ್ಯಾರ್ಥಿ ಫಲಿತಾಂಶ ವರದಿ",
+	"pdf.transcript.title": "ಕ್ರೋಢೀಕೃತ ಅಂಕಪಟ್ಟಿ",
+	"pdf.usn":              "ವಿ.ನೋ.ಸಂ",
+	"pdf.semester":         "ಸೆಮಿಸ್ಟರ್",
+	"pdf.institute":        "ಸಂಸ್ಥೆ",
+	"pdf.exam_date":        "ಪರೀಕ್ಷಾ ದಿನಾಂಕ",
+	"pdf.issued":           "ನೀಡಿದ ದಿನಾಂಕ",
+	"pdf.academic_year":    "ಶೈಕ್ಷಣಿಕ ವರ್ಷ",
+	"pdf.course_id":        "ವಿಷಯ ಸಂಕೇತ",
+	"pdf.course_name":      "ವಿಷಯದ ಹೆಸರು",
+	"pdf.credits":          "ಕ್ರೆಡಿಟ್‌ಗಳು",
+	"pdf.marks":            "ಅಂಕಗಳು",
+	"pdf.total":            "ಒಟ್ಟು",
+	"pdf.grade":            "ಶ್ರೇಣಿ",
+	"pdf.grade_point":      "ಶ್ರೇ.ಅಂ",
+	"pdf.result":           "ಫಲಿತಾಂಶ",
+	"pdf.total_scored":     "ಗಳಿಸಿದ ಒಟ್ಟು ಅಂಕಗಳು",
+	"pdf.sgpa_semester":    "ಎಸ್‌ಜಿಪಿಎ (ಈ ಸೆಮಿಸ್ಟರ್)",
+	"pdf.sgpa":             "ಎಸ್‌ಜಿಪಿಎ",
+	"pdf.cgpa":             "ಸಿಜಿಪಿಎ",
+	"pdf.credits_earned":   "ಗಳಿಸಿದ ಕ್ರೆಡಿಟ್‌ಗಳು",
+	"pdf.backlogs":         "ಬಾಕಿ ವಿಷಯಗಳು",
+	"pdf.none":             "ಇಲ್ಲ",
+
+	// remuneration statement PDF
+	"pdf.remuneration.title":    "ಮೌಲ್ಯಮಾಪಕರ ಸಂಭಾವನೆ ಪಟ್ಟಿ",
+	"pdf.remuneration.unrated":  "%d ಉತ್ತರ ಪತ್ರಿಕೆಗಳಿಗೆ ದರ ನಿಗದಿಯಾಗಿಲ್ಲ ಮತ್ತು ಪಾವತಿಸಲಾಗುವುದಿಲ್ಲ; ದರ ನಿಗದಿಯಾಗುವವರೆಗೆ ಪಟ್ಟಿಯನ್ನು ಅನುಮೋದಿಸಲಾಗುವುದಿಲ್ಲ.",
+	"pdf.remuneration.approved": "%s ಅವರಿಂದ %s ರಂದು ಅನುಮೋದಿಸಲಾಗಿದೆ",
+	"pdf.remuneration.paid":     "%s ರಂದು ಪಾವತಿಸಲಾಗಿದೆ, ಪಾವತಿ ಉಲ್ಲೇಖ %s",
+	"pdf.evaluator":             "ಮೌಲ್ಯಮಾಪಕರು",
+	"pdf.statement_no":          "ಪಟ್ಟಿ ಸಂಖ್ಯೆ",
+	"pdf.status":                "ಸ್ಥಿತಿ",
+	"pdf.computed":              "ಲೆಕ್ಕಹಾಕಿದ ದಿನಾಂಕ",
+	"pdf.course":                "ವಿಷಯ",
+	"pdf.course_type":           "ವಿಷಯದ ಪ್ರಕಾರ",
+	"pdf.valuation":             "ಮೌಲ್ಯಮಾಪನ",
+	"pdf.scripts":               "ಉತ್ತರ ಪತ್ರಿಕೆಗಳು",
+	"pdf.rate":                  "ದರ",
+	"pdf.amount":                "ಮೊತ್ತ",
+	"pdf.no_rate":               "ದರ ಇಲ್ಲ",
+
+	// API messages
+	"api.results_not_released": "ಫಲಿತಾಂಶಗಳು ಇನ್ನೂ ಪ್ರಕಟವಾಗಿಲ್ಲ",
+	"api.no_linked_usn":        "ಈ ಖಾತೆಗೆ ಯಾವುದೇ ವಿ.ನೋ.ಸಂ ಜೋಡಿಸಲಾಗಿಲ್ಲ",
+}
@@ -0,0 +1,69 @@
+// Package i18n holds the message catalogs used for PDF labels and API messages.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lang selects the language a document or message is rendered in.
+type Lang string
+
+const (
+	English   Lang = "en"
+	Kannada   Lang = "kn"
+	Bilingual Lang = "bi" // English followed by Kannada
+)
+
+// catalogs maps each single language to its messages. Bilingual is composed
+// from the two at lookup time.
+var catalogs = map[Lang]map[string]string{
+	English: english,
+	Kannada: kannada,
+}
+
+// ParseLang maps a request's lang parameter to a Lang; anything unrecognised
+// is English.
+func ParseLang(s string) Lang {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "kn", "kannada":
+		return Kannada
+	case "bi", "bilingual", "en-kn", "en_kn", "kn-en":
+		return Bilingual
+	default:
+		return English
+	}
+}
+
+// NeedsKannadaFont reports whether text in l contains Kannada script.
+func (l Lang) NeedsKannadaFont() bool {
+	return l == Kannada || l == Bilingual
+}
+
+// T returns the message for key in lang, formatted with args. Missing
+// translations fall back to English, then to the key itself.
+func T(lang Lang, key string, args ...interface{}) string {
+	if lang == Bilingual {
+		en, kn := T(English, key, args...), T(Kannada, key, args...)
+		if en == kn {
+			return en
+		}
+		return en + " / " + kn
+	}
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		if msg, ok = english[key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Pair returns the English and Kannada messages for key separately, for
+// layouts that stack the two lines instead of joining them.
+func Pair(key string, args ...interface{}) (string, string) {
+	return T(English, key, args...), T(Kannada, key, args...)
+}
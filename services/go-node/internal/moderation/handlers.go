@@ -0,0 +1,184 @@
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/auth"
+)
+
+// Handler exposes the authority moderation endpoints.
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// moderatorID is the authenticated authority making the change.
+func moderatorID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	u, ok := auth.FromContext(r.Context())
+	if !ok || u == nil || u.UserID == "" {
+		http.Error(w, "unauthenticated", http.StatusUnauthorized)
+		return "", false
+	}
+	return u.UserID, true
+}
+
+// GET /api/v1/authority/moderation/evaluations?course_id=...&semester=...&academic_year=...
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("course_id") == "" || q.Get("semester") == "" || q.Get("academic_year") == "" {
+		http.Error(w, "course_id, semester and academic_year are required", http.StatusBadRequest)
+		return
+	}
+	rows, err := h.svc.List(r.Context(), q.Get("course_id"), q.Get("semester"), q.Get("academic_year"))
+	if err != nil {
+		http.Error(w, "failed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rows, http.StatusOK)
+}
+
+// GET /api/v1/authority/moderation/evaluations/{id}
+func (h *Handler) History(w http.ResponseWriter, r *http.Request) {
+	id, ok := evaluationID(w, r)
+	if !ok {
+		return
+	}
+	hist, err := h.svc.History(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, hist, http.StatusOK)
+}
+
+// POST /api/v1/authority/moderation/evaluations/{id}/adjust
+// body: {"reason": "...", "changes": [{"question": 3, "marks": 8}]}
+func (h *Handler) Adjust(w http.ResponseWriter, r *http.Request) {
+	id, ok := evaluationID(w, r)
+	if !ok {
+		return
+	}
+	moderator, ok := moderatorID(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Reason  string   `json:"reason"`
+		Changes []Change `json:"changes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	hash, err := h.svc.Adjust(r.Context(), id, moderator, body.Reason, body.Changes)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "moderated", "block_hash": hash}, http.StatusOK)
+}
+
+// POST /api/v1/authority/moderation/evaluations/{id}/note
+// body: {"reason": "..."}
+func (h *Handler) Note(w http.ResponseWriter, r *http.Request) {
+	id, ok := evaluationID(w, r)
+	if !ok {
+		return
+	}
+	moderator, ok := moderatorID(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	hash, err := h.svc.Note(r.Context(), id, moderator, body.Reason)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "noted", "block_hash": hash}, http.StatusOK)
+}
+
+// POST /api/v1/authority/moderation/grace
+// body: {"course_id", "semester", "academic_year", "reason", "grace": 3, "only_failing": false}
+func (h *Handler) Grace(w http.ResponseWriter, r *http.Request) {
+	moderator, ok := moderatorID(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		CourseID     string `json:"course_id"`
+		Semester     string `json:"semester"`
+		AcademicYear string `json:"academic_year"`
+		Reason       string `json:"reason"`
+		GraceRule
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if body.CourseID == "" || body.Semester == "" || body.AcademicYear == "" {
+		http.Error(w, "course_id, semester and academic_year are required", http.StatusBadRequest)
+		return
+	}
+	res, err := h.svc.Grace(r.Context(), body.CourseID, body.Semester, body.AcademicYear, moderator, body.Reason, body.GraceRule)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, res, http.StatusOK)
+}
+
+func evaluationID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrReleased):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, ErrNoSigningKey):
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// RegisterModerationRoutes mounts the moderation endpoints behind JWT auth and
+// the authority role; the moderator is always the authenticated user.
+func RegisterModerationRoutes(r *mux.Router, svc *Service, jwtMgr *auth.Manager) {
+	h := NewHandler(svc)
+	mr := r.PathPrefix("/authority/moderation").Subrouter()
+	mr.Use(auth.AuthMiddleware(jwtMgr), auth.RequireRole(auth.RoleAuthority))
+	mr.HandleFunc("/evaluations", h.List).Methods("GET")
+	mr.HandleFunc("/evaluations/{id}", h.History).Methods("GET")
+	mr.HandleFunc("/evaluations/{id}/adjust", h.Adjust).Methods("POST")
+	mr.HandleFunc("/evaluations/{id}/note", h.Note).Methods("POST")
+	mr.HandleFunc("/grace", h.Grace).Methods("POST")
+}
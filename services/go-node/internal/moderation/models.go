@@ -0,0 +1,73 @@
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+
+	"digital-eval-system/services/go-node/internal/db"
+)
+
+// Moderation kinds.
+const (
+	KindAdjust = "adjust"
+	KindGrace  = "grace"
+	KindNote   = "note"
+)
+
+var (
+	ErrNotFound       = errors.New("evaluation not found")
+	ErrReleased       = errors.New("results are already released; use revaluation instead")
+	ErrReasonRequired = errors.New("reason is required")
+	ErrNoSigningKey   = errors.New("no signing key configured for moderation transactions")
+)
+
+// Entry is one evaluation in the moderation list of a course.
+type Entry struct {
+	EvaluationID  int64  `json:"evaluation_id"`
+	ScriptID      string `json:"script_id"`
+	StudentUSN    string `json:"student_usn"`
+	EvaluatorID   string `json:"evaluator_id"`
+	MarksScored   []int  `json:"marks_scored"`
+	MarksAllotted []int  `json:"marks_allotted,omitempty"`
+	GraceMarks    int    `json:"grace_marks,omitempty"`
+	Score         int    `json:"score"`
+	TotalMarks    int    `json:"total_marks"`
+	Result        string `json:"result"`
+	Revision      int    `json:"revision"`
+	BlockHash     string `json:"block_hash"`
+	// evaluator's annotated copy of the script, if one was uploaded
+	AnnotatedCID    string `json:"annotated_cid,omitempty"`
+	AnnotatedSHA256 string `json:"annotated_sha256,omitempty"`
+	// why the marks were given: per-question remarks and page annotations
+	Remarks     json.RawMessage `json:"remarks,omitempty"`
+	Annotations json.RawMessage `json:"annotations,omitempty"`
+}
+
+// Change sets the marks of one question (1-based).
+type Change struct {
+	Question int `json:"question"`
+	Marks    int `json:"marks"`
+}
+
+// GraceRule adds Grace marks to the total of every present script of a
+// course, or only to failing ones when OnlyFailing is set. Totals are capped
+// at the paper's total marks.
+type GraceRule struct {
+	Grace       int  `json:"grace"`
+	OnlyFailing bool `json:"only_failing"`
+}
+
+// BatchResult summarises a bulk moderation.
+type BatchResult struct {
+	BatchID   string `json:"batch_id"`
+	Applied   int    `json:"applied"`
+	Skipped   int    `json:"skipped"`
+	BlockHash string `json:"block_hash,omitempty"`
+}
+
+// History is an evaluation with its moderations and superseded versions.
+type History struct {
+	Evaluation  Entry                      `json:"evaluation"`
+	Moderations []db.ModerationRow         `json:"moderations"`
+	Revisions   []db.EvaluationRevisionRow `json:"revisions"`
+}
@@ -0,0 +1,337 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"digital-eval-system/services/go-node/internal/block"
+	"digital-eval-system/services/go-node/internal/chain"
+	"digital-eval-system/services/go-node/internal/course"
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/exampattern"
+	"digital-eval-system/services/go-node/internal/grading"
+	"digital-eval-system/services/go-node/internal/outbox"
+	"digital-eval-system/services/go-node/internal/student"
+)
+
+// Service lets authorities review and moderate marks before release. Every
+// change is a "_moderation" transaction referencing the evaluation block it
+// modifies, signed with the node's chain key; the moderator is named in the
+// transaction payload.
+type Service struct {
+	pg    *db.PostgresDB
+	chain interface {
+		AppendBlock(*block.Block) (string, error)
+	}
+	grading *grading.Service
+	courses *course.Service
+	signer  *chain.Signer
+	outbox  *outbox.Service
+}
+
+func NewService(pg *db.PostgresDB, ch *chain.Chain, gradingSvc *grading.Service, courseSvc *course.Service, signer *chain.Signer, outboxSvc *outbox.Service) *Service {
+	return &Service{pg: pg, chain: ch, grading: gradingSvc, courses: courseSvc, signer: signer, outbox: outboxSvc}
+}
+
+// List returns the evaluations of a course for review.
+func (s *Service) List(ctx context.Context, courseID, semester, academicYear string) ([]Entry, error) {
+	rows, err := s.pg.ListCourseEvaluations(ctx, strings.ToUpper(strings.TrimSpace(courseID)), semester, academicYear)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Entry, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, toEntry(r))
+	}
+	return out, nil
+}
+
+// History returns an evaluation with every moderation and superseded version.
+func (s *Service) History(ctx context.Context, evaluationID int64) (*History, error) {
+	cur, err := s.pg.GetEvaluation(ctx, evaluationID)
+	if err != nil {
+		return nil, err
+	}
+	if cur == nil {
+		return nil, ErrNotFound
+	}
+	h := &History{Evaluation: toEntry(*cur)}
+	if h.Moderations, err = s.pg.ListModerations(ctx, evaluationID); err != nil {
+		return nil, err
+	}
+	if h.Revisions, err = s.pg.ListEvaluationRevisions(ctx, evaluationID); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Adjust changes per-question marks of one evaluation.
+func (s *Service) Adjust(ctx context.Context, evaluationID int64, moderatorID, reason string, changes []Change) (string, error) {
+	if len(changes) == 0 {
+		return "", fmt.Errorf("at least one change is required")
+	}
+	cur, scheme, err := s.load(ctx, evaluationID, moderatorID, reason)
+	if err != nil {
+		return "", err
+	}
+	if scheme.IsNonAttempt(cur.Result) {
+		return "", fmt.Errorf("cannot adjust marks of a %s script", cur.Result)
+	}
+	marks, scored, allotted, grace := readMarks(cur.Marks)
+	revised := append([]int(nil), scored...)
+	for _, c := range changes {
+		if c.Question < 1 || c.Question > len(revised) {
+			return "", fmt.Errorf("question %d out of range 1..%d", c.Question, len(revised))
+		}
+		if c.Marks < 0 || (c.Question <= len(allotted) && allotted[c.Question-1] > 0 && c.Marks > allotted[c.Question-1]) {
+			return "", fmt.Errorf("marks %d invalid for question %d", c.Marks, c.Question)
+		}
+		revised[c.Question-1] = c.Marks
+	}
+	base, err := exampattern.FromMarks(cur.Marks).Check(revised)
+	if err != nil {
+		return "", err
+	}
+	score := capScore(base+grace, cur.TotalMarks)
+
+	w := s.write(cur, scheme, KindAdjust, moderatorID, reason, "", changes, marks, revised, grace, score)
+	return s.commit(ctx, moderatorID, []db.ModerationWrite{w})
+}
+
+// Note annotates an evaluation without changing its marks.
+func (s *Service) Note(ctx context.Context, evaluationID int64, moderatorID, reason string) (string, error) {
+	cur, scheme, err := s.load(ctx, evaluationID, moderatorID, reason)
+	if err != nil {
+		return "", err
+	}
+	score := student.RowMarksScored(cur.EvaluationRow)
+	w := s.write(cur, scheme, KindNote, moderatorID, reason, "", map[string]interface{}{}, nil, nil, 0, score)
+	w.Moderation.ModeratedResult = cur.Result
+	return s.commit(ctx, moderatorID, []db.ModerationWrite{w})
+}
+
+// Grace applies a bulk grace rule to every present evaluation of a course.
+// All moderations of the batch go into one block and one Postgres transaction.
+func (s *Service) Grace(ctx context.Context, courseID, semester, academicYear, moderatorID, reason string, rule GraceRule) (*BatchResult, error) {
+	if rule.Grace <= 0 {
+		return nil, fmt.Errorf("grace must be positive")
+	}
+	if strings.TrimSpace(reason) == "" {
+		return nil, ErrReasonRequired
+	}
+	if strings.TrimSpace(moderatorID) == "" {
+		return nil, fmt.Errorf("moderator_id is required")
+	}
+	rel, err := s.pg.GetRelease(ctx, semester, academicYear)
+	if err != nil {
+		return nil, err
+	}
+	if rel != nil {
+		return nil, ErrReleased
+	}
+	rows, err := s.pg.ListCourseEvaluations(ctx, strings.ToUpper(strings.TrimSpace(courseID)), semester, academicYear)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &BatchResult{BatchID: uuid.NewString()}
+	var writes []db.ModerationWrite
+	for i := range rows {
+		cur := &rows[i]
+		scheme := s.scheme(ctx, cur)
+		if scheme.IsNonAttempt(cur.Result) || (rule.OnlyFailing && cur.Result != grading.ResultFail) {
+			res.Skipped++
+			continue
+		}
+		marks, scored, _, grace := readMarks(cur.Marks)
+		previous := student.RowMarksScored(cur.EvaluationRow)
+		score := capScore(previous+rule.Grace, cur.TotalMarks)
+		if score == previous {
+			res.Skipped++
+			continue
+		}
+		changes := map[string]interface{}{"grace": rule.Grace, "only_failing": rule.OnlyFailing}
+		writes = append(writes, s.write(cur, scheme, KindGrace, moderatorID, reason, res.BatchID, changes, marks, scored, grace+score-previous, score))
+	}
+	if len(writes) == 0 {
+		return res, nil
+	}
+	hash, err := s.commit(ctx, moderatorID, writes)
+	if err != nil {
+		return nil, err
+	}
+	res.Applied = len(writes)
+	res.BlockHash = hash
+	return res, nil
+}
+
+// load fetches an unreleased evaluation and checks the moderator fields.
+func (s *Service) load(ctx context.Context, evaluationID int64, moderatorID, reason string) (*db.CurrentEvaluationRow, *grading.Scheme, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, nil, ErrReasonRequired
+	}
+	if strings.TrimSpace(moderatorID) == "" {
+		return nil, nil, fmt.Errorf("moderator_id is required")
+	}
+	cur, err := s.pg.GetEvaluation(ctx, evaluationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cur == nil {
+		return nil, nil, ErrNotFound
+	}
+	rel, err := s.pg.GetRelease(ctx, cur.Semester, cur.AcademicYear)
+	if err != nil {
+		return nil, nil, err
+	}
+	if rel != nil {
+		return nil, nil, ErrReleased
+	}
+	return cur, s.scheme(ctx, cur), nil
+}
+
+// write prepares the Postgres side of one moderation; the block hash is
+// filled in when the outbox applies it.
+func (s *Service) write(cur *db.CurrentEvaluationRow, scheme *grading.Scheme, kind, moderatorID, reason, batchID string, changes interface{}, marks map[string]interface{}, scored []int, grace, score int) db.ModerationWrite {
+	changesJSON, _ := json.Marshal(changes)
+	w := db.ModerationWrite{
+		Current: *cur,
+		Moderation: db.ModerationRow{
+			EvaluationID:        cur.ID,
+			BatchID:             batchID,
+			Kind:                kind,
+			ModeratorID:         moderatorID,
+			Reason:              strings.TrimSpace(reason),
+			Changes:             changesJSON,
+			PreviousScore:       student.RowMarksScored(cur.EvaluationRow),
+			ModeratedScore:      score,
+			PreviousResult:      cur.Result,
+			ModeratedResult:     scheme.Result(score, cur.TotalMarks),
+			EvaluationBlockHash: cur.BlockHash,
+		},
+	}
+	if marks != nil {
+		marks["marks_scored"] = scored
+		marks["final_score"] = score
+		if grace > 0 {
+			marks["grace_marks"] = grace
+		}
+		marks["moderated"] = true
+		w.Marks, _ = json.Marshal(marks)
+	}
+	return w
+}
+
+// commit appends one block holding a transaction per moderation, all signed
+// by the chain signer, and stores them through the outbox.
+func (s *Service) commit(ctx context.Context, moderatorID string, writes []db.ModerationWrite) (string, error) {
+	if s.signer == nil {
+		return "", ErrNoSigningKey
+	}
+	txs := make([]block.Transaction, 0, len(writes))
+	for _, w := range writes {
+		m := w.Moderation
+		payload, _ := json.Marshal(map[string]interface{}{
+			"evaluation_id":    m.EvaluationID,
+			"batch_id":         m.BatchID,
+			"kind":             m.Kind,
+			"moderator_id":     m.ModeratorID,
+			"reason":           m.Reason,
+			"changes":          m.Changes,
+			"previous_score":   m.PreviousScore,
+			"moderated_score":  m.ModeratedScore,
+			"previous_result":  m.PreviousResult,
+			"moderated_result": m.ModeratedResult,
+			"marks":            json.RawMessage(w.Marks),
+		})
+		tx := block.Transaction{
+			ScriptID:     w.Current.ScriptID,
+			CourseID:     w.Current.CourseID,
+			Semester:     w.Current.Semester,
+			AcademicYear: w.Current.AcademicYear,
+			Meta: map[string]string{
+				"_moderation": string(payload),
+				"_references": m.EvaluationBlockHash,
+			},
+			CreatedAt: time.Now().Unix(),
+			SignerID:  s.signer.ID,
+		}
+		if err := tx.SignRSA(s.signer.Key); err != nil {
+			return "", fmt.Errorf("sign moderation transaction: %w", err)
+		}
+		txs = append(txs, tx)
+	}
+
+	blk := block.NewBlock("", txs, s.signer.ID)
+	if err := blk.SignHeaderRSA(s.signer.Key); err != nil {
+		return "", fmt.Errorf("sign moderation block: %w", err)
+	}
+	blockHash, pending, err := s.outbox.Append(ctx, s.chain, blk, outbox.Effect{Kind: outbox.KindModeration, Payload: outbox.Moderation{Writes: writes}})
+	if err != nil {
+		return "", fmt.Errorf("append block failed: %w", err)
+	}
+	if err := s.outbox.Deliver(ctx, pending...); err != nil {
+		logrus.Warnf("moderation in block %s queued for retry: %v", blockHash, err)
+	}
+	logrus.Infof("moderation by %s: %d evaluation(s) in block %s", moderatorID, len(writes), blockHash)
+	return blockHash, nil
+}
+
+func (s *Service) scheme(ctx context.Context, cur *db.CurrentEvaluationRow) *grading.Scheme {
+	regulation := ""
+	if co, err := s.courses.Get(ctx, cur.CourseID); err == nil && co != nil {
+		regulation = co.Regulation
+	}
+	return s.grading.Resolve(ctx, regulation, cur.AcademicYear)
+}
+
+// readMarks decodes the stored marks JSON.
+func readMarks(raw json.RawMessage) (map[string]interface{}, []int, []int, int) {
+	var m map[string]interface{}
+	_ = json.Unmarshal(raw, &m)
+	if m == nil {
+		m = map[string]interface{}{}
+	}
+	var typed struct {
+		MarksScored   []int `json:"marks_scored"`
+		MarksAllotted []int `json:"marks_allotted"`
+		GraceMarks    int   `json:"grace_marks"`
+	}
+	_ = json.Unmarshal(raw, &typed)
+	return m, typed.MarksScored, typed.MarksAllotted, typed.GraceMarks
+}
+
+func toEntry(r db.CurrentEvaluationRow) Entry {
+	_, scored, allotted, grace := readMarks(r.Marks)
+	return Entry{
+		EvaluationID:    r.ID,
+		ScriptID:        r.ScriptID,
+		StudentUSN:      r.StudentUSN.String,
+		EvaluatorID:     r.Evaluator,
+		MarksScored:     scored,
+		MarksAllotted:   allotted,
+		GraceMarks:      grace,
+		Score:           student.RowMarksScored(r.EvaluationRow),
+		TotalMarks:      r.TotalMarks,
+		Result:          r.Result,
+		Revision:        r.Revision,
+		BlockHash:       r.BlockHash,
+		AnnotatedCID:    r.AnnotatedCID,
+		AnnotatedSHA256: r.AnnotatedSHA256,
+		Remarks:         r.Remarks,
+		Annotations:     r.Annotations,
+	}
+}
+
+func capScore(score, total int) int {
+	if total > 0 && score > total {
+		return total
+	}
+	return score
+}
@@ -0,0 +1,145 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"digital-eval-system/services/go-node/internal/db"
+)
+
+// Item kinds.
+const (
+	KindEvaluationResult = "evaluation_result" // evaluations row of an "_evaluation" block
+	KindResultRelease    = "result_release"    // result_releases row of a "_result_release" block
+	KindValuation        = "valuation"         // valuations row of a "_valuation" block
+	KindModeration       = "moderation"        // moderations (and revised evaluations) of a "_moderation" block
+	KindRevision         = "revision"          // revised evaluation of a revaluation / re-totaling block
+)
+
+// EvaluationResult is the evaluations row written for an evaluation block.
+type EvaluationResult struct {
+	ScriptID      string          `json:"script_id"`
+	StudentUSN    string          `json:"student_usn"`
+	CourseID      string          `json:"course_id"`
+	Semester      string          `json:"semester"`
+	AcademicYear  string          `json:"academic_year"`
+	CourseCredits int             `json:"course_credits"`
+	EvaluatorID   string          `json:"evaluator_id"`
+	Marks         json.RawMessage `json:"marks"`
+	TotalMarks    int             `json:"total_marks"`
+	Result        string          `json:"result"`
+	// annotated copy the evaluation was submitted with, if any
+	AnnotatedCID    string `json:"annotated_cid,omitempty"`
+	AnnotatedSHA256 string `json:"annotated_sha256,omitempty"`
+	// per-question remarks and page annotations, if any
+	Remarks     json.RawMessage `json:"remarks,omitempty"`
+	Annotations json.RawMessage `json:"annotations,omitempty"`
+}
+
+// ResultRelease is the result_releases row written for a release block.
+type ResultRelease struct {
+	Semester     string `json:"semester"`
+	AcademicYear string `json:"academic_year"`
+	ReleasedBy   string `json:"released_by"`
+}
+
+// Valuation is the valuations row written for a valuation block, with what
+// the final evaluation needs once the script's valuations settle. Its
+// applier is registered by the evaluator submit service.
+type Valuation struct {
+	ScriptID       string          `json:"script_id"`
+	AssignmentID   int64           `json:"assignment_id"`
+	EvaluatorID    string          `json:"evaluator_id"`
+	ValuationRound int             `json:"valuation_round"`
+	CourseID       string          `json:"course_id"`
+	Semester       string          `json:"semester"`
+	AcademicYear   string          `json:"academic_year"`
+	Marks          json.RawMessage `json:"marks"`
+	Score          int             `json:"score"`
+	TotalMarks     int             `json:"total_marks"`
+	StudentUSN     string          `json:"student_usn"`
+	CourseCredits  int             `json:"course_credits"`
+	Regulation     string          `json:"regulation"`
+	// annotated copy, remarks and annotations of this valuation, if any
+	AnnotatedCID    string          `json:"annotated_cid,omitempty"`
+	AnnotatedSHA256 string          `json:"annotated_sha256,omitempty"`
+	Remarks         json.RawMessage `json:"remarks,omitempty"`
+	Annotations     json.RawMessage `json:"annotations,omitempty"`
+}
+
+// Moderation is the Postgres side of the moderations of one block.
+type Moderation struct {
+	Writes []db.ModerationWrite `json:"writes"`
+}
+
+// Revision is the revised evaluation of a completed revaluation request.
+type Revision struct {
+	RequestID    int64                   `json:"request_id"`
+	Current      db.CurrentEvaluationRow `json:"current"`
+	Marks        json.RawMessage         `json:"marks"`
+	Result       string                  `json:"result"`
+	CompletedBy  string                  `json:"completed_by"`
+	RevisedScore int                     `json:"revised_score"`
+	// remarks and annotations still describing the revised marks, if any
+	Remarks     json.RawMessage `json:"remarks,omitempty"`
+	Annotations json.RawMessage `json:"annotations,omitempty"`
+}
+
+func (s *Service) insertEvaluationResult(ctx context.Context, it *Item) error {
+	var e EvaluationResult
+	if err := json.Unmarshal(it.Payload, &e); err != nil {
+		return err
+	}
+	if err := s.pg.InsertEvaluationResult(ctx, e.ScriptID, e.StudentUSN, e.CourseID, e.Semester, e.AcademicYear, e.CourseCredits, e.EvaluatorID, e.Marks, e.TotalMarks, e.Result, it.BlockHash); err != nil {
+		return err
+	}
+	if e.AnnotatedCID != "" {
+		if err := s.pg.SetEvaluationAnnotatedScript(ctx, it.BlockHash, e.AnnotatedCID, e.AnnotatedSHA256); err != nil {
+			return err
+		}
+	}
+	if len(e.Remarks) > 0 || len(e.Annotations) > 0 {
+		return s.pg.SetEvaluationRemarks(ctx, it.BlockHash, e.Remarks, e.Annotations)
+	}
+	return nil
+}
+
+func (s *Service) recordRelease(ctx context.Context, it *Item) error {
+	var r ResultRelease
+	if err := json.Unmarshal(it.Payload, &r); err != nil {
+		return err
+	}
+	existing, err := s.pg.GetRelease(ctx, r.Semester, r.AcademicYear)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if existing.BlockHash.String == it.BlockHash {
+			return nil
+		}
+		return fmt.Errorf("semester %s %s already recorded as released in block %s", r.Semester, r.AcademicYear, existing.BlockHash.String)
+	}
+	_, err = s.pg.RecordRelease(ctx, r.Semester, r.AcademicYear, r.ReleasedBy, it.BlockHash)
+	return err
+}
+
+func (s *Service) applyModeration(ctx context.Context, it *Item) error {
+	var m Moderation
+	if err := json.Unmarshal(it.Payload, &m); err != nil {
+		return err
+	}
+	for i := range m.Writes {
+		m.Writes[i].Moderation.BlockHash = it.BlockHash
+	}
+	_, err := s.pg.ApplyModerations(ctx, m.Writes)
+	return err
+}
+
+func (s *Service) applyRevision(ctx context.Context, it *Item) error {
+	var r Revision
+	if err := json.Unmarshal(it.Payload, &r); err != nil {
+		return err
+	}
+	return s.pg.ApplyRevaluation(ctx, r.RequestID, r.Current, r.Marks, r.Result, it.BlockHash, r.CompletedBy, r.RevisedScore, r.Remarks, r.Annotations)
+}
@@ -0,0 +1,106 @@
+package outbox
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler exposes the outbox to admins.
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GET /api/v1/admin/outbox?stuck=true
+// Pending Postgres writes of appended blocks, oldest first.
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	items, err := h.svc.List(r.URL.Query().Get("stuck") == "true")
+	if err != nil {
+		http.Error(w, "failed to load outbox", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"policy": h.svc.Policy(), "items": items}, http.StatusOK)
+}
+
+// GET /api/v1/admin/outbox/{id}
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	it, err := h.svc.Get(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, it, http.StatusOK)
+}
+
+// POST /api/v1/admin/outbox/retry
+// Runs the retry pass now.
+func (h *Handler) Retry(w http.ResponseWriter, r *http.Request) {
+	rep, err := h.svc.Retry(r.Context())
+	if err != nil {
+		http.Error(w, "retry failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rep, http.StatusOK)
+}
+
+// POST /api/v1/admin/outbox/{id}/retry
+// Applies one item now; a failure returns the rescheduled item.
+func (h *Handler) RetryItem(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	it, err := h.svc.RetryNow(r.Context(), id)
+	if err == ErrNotFound || err == ErrBusy {
+		writeError(w, err)
+		return
+	}
+	if err != nil {
+		writeJSON(w, map[string]interface{}{"delivered": false, "error": err.Error(), "item": it}, http.StatusBadGateway)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"id": id, "delivered": true}, http.StatusOK)
+}
+
+// DELETE /api/v1/admin/outbox/{id}?admin_id=
+// Drops an item that can never succeed; Postgres must then be fixed by hand.
+func (h *Handler) Discard(w http.ResponseWriter, r *http.Request) {
+	id, by := mux.Vars(r)["id"], r.URL.Query().Get("admin_id")
+	if by == "" {
+		http.Error(w, "admin_id is required", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.Discard(id, by); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, map[string]string{"discarded": id}, http.StatusOK)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch err {
+	case ErrNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case ErrBusy:
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func RegisterOutboxRoutes(r *mux.Router, svc *Service) {
+	h := NewHandler(svc)
+	r.HandleFunc("/admin/outbox", h.List).Methods("GET")
+	r.HandleFunc("/admin/outbox/retry", h.Retry).Methods("POST")
+	r.HandleFunc("/admin/outbox/{id}", h.Get).Methods("GET")
+	r.HandleFunc("/admin/outbox/{id}", h.Discard).Methods("DELETE")
+	r.HandleFunc("/admin/outbox/{id}/retry", h.RetryItem).Methods("POST")
+}
@@ -0,0 +1,60 @@
+package outbox
+
+import (
+	"fmt"
+	"time"
+)
+
+// Policy sets how often pending items are retried and when an item counts
+// as stuck.
+type Policy struct {
+	RetrySeconds      int `yaml:"retry_interval_seconds" json:"retry_interval_seconds"`
+	MaxBackoffMinutes int `yaml:"max_backoff_minutes" json:"max_backoff_minutes"`
+	StuckAttempts     int `yaml:"stuck_after_attempts" json:"stuck_after_attempts"`
+}
+
+// Default retries every 30 seconds, backing off to at most every 30 minutes,
+// and reports an item as stuck after 5 failed attempts.
+func Default() Policy {
+	return Policy{RetrySeconds: 30, MaxBackoffMinutes: 30, StuckAttempts: 5}
+}
+
+// Normalize fills unset fields from Default.
+func (p *Policy) Normalize() {
+	def := Default()
+	if p.RetrySeconds <= 0 {
+		p.RetrySeconds = def.RetrySeconds
+	}
+	if p.MaxBackoffMinutes <= 0 {
+		p.MaxBackoffMinutes = def.MaxBackoffMinutes
+	}
+	if p.StuckAttempts <= 0 {
+		p.StuckAttempts = def.StuckAttempts
+	}
+}
+
+// Validate checks the policy.
+func (p Policy) Validate() error {
+	if p.RetrySeconds < 0 || p.MaxBackoffMinutes < 0 || p.StuckAttempts < 0 {
+		return fmt.Errorf("outbox retry settings must be >= 0")
+	}
+	return nil
+}
+
+func (p Policy) interval() time.Duration {
+	return time.Duration(p.RetrySeconds) * time.Second
+}
+
+// backoff is the wait after the given number of failed attempts: the retry
+// interval doubled per attempt, capped at the maximum.
+func (p Policy) backoff(attempts int) time.Duration {
+	max := time.Duration(p.MaxBackoffMinutes) * time.Minute
+	d := p.interval()
+	for i := 1; i < attempts && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
@@ -0,0 +1,390 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"digital-eval-system/services/go-node/internal/block"
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/storage"
+)
+
+var (
+	// ErrNotFound is returned for an unknown outbox item.
+	ErrNotFound = errors.New("outbox item not found")
+	// ErrBusy is returned for an item that is being applied right now.
+	ErrBusy = errors.New("outbox item is being delivered")
+)
+
+// Item is a Postgres write implied by an appended block, kept in the node's
+// bolt store next to the chain until it succeeds.
+type Item struct {
+	ID          string          `json:"id"`
+	Kind        string          `json:"kind"`
+	BlockHash   string          `json:"block_hash"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempts    int             `json:"attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	NextAttempt time.Time       `json:"next_attempt_at"`
+	Stuck       bool            `json:"stuck"`
+}
+
+// Effect is a side effect to record with a block.
+type Effect struct {
+	Kind    string
+	Payload interface{}
+}
+
+// Applier applies one item. It must be idempotent: an item is applied again
+// when the node stops between the write and the item's removal.
+type Applier func(ctx context.Context, it *Item) error
+
+// RetryReport summarises one retry pass.
+type RetryReport struct {
+	Delivered []string `json:"delivered"`
+	Failed    []string `json:"failed"`
+	Abandoned []string `json:"abandoned"`
+	Pending   int      `json:"pending"`
+	Stuck     int      `json:"stuck"`
+}
+
+// Service records the side effects of appended blocks and retries them until
+// they succeed, so the chain and Postgres converge after crashes or database
+// outages.
+type Service struct {
+	pg       *db.PostgresDB
+	store    storage.Storage
+	policy   Policy
+	appliers map[string]Applier
+	mu       sync.Mutex
+	claimed  map[string]bool // items being applied; each by one caller at a time
+}
+
+func NewService(pg *db.PostgresDB, store storage.Storage, policy Policy) *Service {
+	policy.Normalize()
+	s := &Service{pg: pg, store: store, policy: policy, appliers: map[string]Applier{}, claimed: map[string]bool{}}
+	s.Register(KindEvaluationResult, s.insertEvaluationResult)
+	s.Register(KindResultRelease, s.recordRelease)
+	s.Register(KindModeration, s.applyModeration)
+	s.Register(KindRevision, s.applyRevision)
+	return s
+}
+
+// Register sets the applier for an item kind.
+func (s *Service) Register(kind string, h Applier) {
+	s.appliers[kind] = h
+}
+
+// Policy returns the retry policy in effect.
+func (s *Service) Policy() Policy {
+	return s.policy
+}
+
+// Append records the effects of a block, then appends it. The effects are
+// written first, under the hash the block will have, so a crash after the
+// append cannot lose them; they are dropped again when the append fails.
+// Returns the block hash and the ids of the recorded items, to be passed to
+// Deliver once the caller is done with the block.
+func (s *Service) Append(ctx context.Context, chain interface {
+	AppendBlock(*block.Block) (string, error)
+}, blk *block.Block, effects ...Effect) (string, []string, error) {
+	hash, err := block.BlockHash(blk)
+	if err != nil {
+		return "", nil, err
+	}
+	now := time.Now().UTC()
+	ids := make([]string, 0, len(effects))
+	for _, e := range effects {
+		if _, ok := s.appliers[e.Kind]; !ok {
+			s.drop(ids)
+			return "", nil, fmt.Errorf("no outbox applier for %q", e.Kind)
+		}
+		payload, err := json.Marshal(e.Payload)
+		if err != nil {
+			s.drop(ids)
+			return "", nil, err
+		}
+		it := &Item{
+			ID:        uuid.NewString(),
+			Kind:      e.Kind,
+			BlockHash: hash,
+			Payload:   payload,
+			CreatedAt: now,
+			// the retry job leaves the item to the caller for one interval
+			NextAttempt: now.Add(s.policy.interval()),
+		}
+		if err := s.put(it); err != nil {
+			s.drop(ids)
+			return "", nil, fmt.Errorf("record outbox item: %w", err)
+		}
+		ids = append(ids, it.ID)
+	}
+	appended, err := chain.AppendBlock(blk)
+	if err != nil {
+		s.drop(ids)
+		return "", nil, err
+	}
+	return appended, ids, nil
+}
+
+// Deliver applies the given items now. Items that fail stay queued for the
+// retry job; the first failure is returned. An item another caller is
+// applying is skipped. Appliers may Deliver the items of blocks they append.
+func (s *Service) Deliver(ctx context.Context, ids ...string) error {
+	var first error
+	for _, id := range ids {
+		if !s.claim(id) {
+			continue
+		}
+		it, err := s.get(id)
+		if err != nil {
+			s.release(id)
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+		if it == nil {
+			s.release(id)
+			continue // already delivered
+		}
+		if err := s.apply(ctx, it, time.Now().UTC()); err != nil && first == nil {
+			first = err
+		}
+		s.release(id)
+	}
+	return first
+}
+
+// Run retries due items on the policy interval until ctx is cancelled.
+func (s *Service) Run(ctx context.Context) {
+	t := time.NewTicker(s.policy.interval())
+	defer t.Stop()
+	for {
+		if rep, err := s.Retry(ctx); err != nil {
+			logrus.Warnf("outbox retry failed: %v", err)
+		} else if len(rep.Delivered) > 0 || len(rep.Failed) > 0 || len(rep.Abandoned) > 0 {
+			logrus.Infof("outbox retry: %d delivered, %d failed, %d abandoned, %d pending (%d stuck)",
+				len(rep.Delivered), len(rep.Failed), len(rep.Abandoned), rep.Pending, rep.Stuck)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Retry applies every item that is due. An item whose block never reached
+// the chain (the node stopped before the append) is abandoned.
+func (s *Service) Retry(ctx context.Context) (*RetryReport, error) {
+	items, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	rep := &RetryReport{Delivered: []string{}, Failed: []string{}, Abandoned: []string{}}
+	for i := range items {
+		it := &items[i]
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if it.NextAttempt.After(now) || !s.claim(it.ID) {
+			rep.Pending++
+			if it.Stuck {
+				rep.Stuck++
+			}
+			continue
+		}
+		if _, err := s.store.GetBlock(it.BlockHash); err != nil {
+			logrus.Warnf("outbox item %s (%s): block %s is not on the chain, abandoning it", it.ID, it.Kind, it.BlockHash)
+			err := s.store.DeleteOutbox(it.ID)
+			s.release(it.ID)
+			if err != nil {
+				return nil, err
+			}
+			rep.Abandoned = append(rep.Abandoned, it.ID)
+			continue
+		}
+		err := s.apply(ctx, it, now)
+		s.release(it.ID)
+		if err != nil {
+			rep.Failed = append(rep.Failed, it.ID)
+			rep.Pending++
+			if it.Stuck {
+				rep.Stuck++
+			}
+			continue
+		}
+		rep.Delivered = append(rep.Delivered, it.ID)
+	}
+	return rep, nil
+}
+
+// List returns the pending items, oldest first; with stuckOnly only those
+// that have failed at least the policy's stuck_after_attempts times.
+func (s *Service) List(stuckOnly bool) ([]Item, error) {
+	items, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+	if !stuckOnly {
+		return items, nil
+	}
+	out := make([]Item, 0, len(items))
+	for _, it := range items {
+		if it.Stuck {
+			out = append(out, it)
+		}
+	}
+	return out, nil
+}
+
+// Get returns one pending item.
+func (s *Service) Get(id string) (*Item, error) {
+	it, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	if it == nil {
+		return nil, ErrNotFound
+	}
+	return it, nil
+}
+
+// RetryNow applies one item immediately regardless of its schedule.
+func (s *Service) RetryNow(ctx context.Context, id string) (*Item, error) {
+	if !s.claim(id) {
+		return nil, ErrBusy
+	}
+	defer s.release(id)
+	it, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	if it == nil {
+		return nil, ErrNotFound
+	}
+	if err := s.apply(ctx, it, time.Now().UTC()); err != nil {
+		return it, err
+	}
+	return nil, nil
+}
+
+// Discard drops an item that can never succeed. The block stays on the
+// chain; whoever discards it takes over reconciling Postgres.
+func (s *Service) Discard(id, by string) error {
+	if !s.claim(id) {
+		return ErrBusy
+	}
+	defer s.release(id)
+	it, err := s.get(id)
+	if err != nil {
+		return err
+	}
+	if it == nil {
+		return ErrNotFound
+	}
+	if err := s.store.DeleteOutbox(id); err != nil {
+		return err
+	}
+	logrus.Warnf("outbox item %s (%s, block %s) discarded by %s after %d attempts: %s", it.ID, it.Kind, it.BlockHash, by, it.Attempts, it.LastError)
+	return nil
+}
+
+// apply runs the item's applier, removing the item on success and
+// rescheduling it on failure.
+func (s *Service) apply(ctx context.Context, it *Item, now time.Time) error {
+	h, ok := s.appliers[it.Kind]
+	var err error
+	if !ok {
+		err = fmt.Errorf("no outbox applier for %q", it.Kind)
+	} else {
+		err = h(ctx, it)
+	}
+	if err == nil {
+		return s.store.DeleteOutbox(it.ID)
+	}
+	it.Attempts++
+	it.LastError = err.Error()
+	it.NextAttempt = now.Add(s.policy.backoff(it.Attempts))
+	it.Stuck = it.Attempts >= s.policy.StuckAttempts
+	if perr := s.put(it); perr != nil {
+		logrus.Warnf("outbox item %s: failed to reschedule: %v", it.ID, perr)
+	}
+	logrus.Warnf("outbox item %s (%s, block %s) failed, attempt %d: %v", it.ID, it.Kind, it.BlockHash, it.Attempts, err)
+	return err
+}
+
+// claim marks an item as being applied, false if another caller has it.
+func (s *Service) claim(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.claimed[id] {
+		return false
+	}
+	s.claimed[id] = true
+	return true
+}
+
+func (s *Service) release(id string) {
+	s.mu.Lock()
+	delete(s.claimed, id)
+	s.mu.Unlock()
+}
+
+func (s *Service) put(it *Item) error {
+	v, err := json.Marshal(it)
+	if err != nil {
+		return err
+	}
+	return s.store.PutOutbox(it.ID, v)
+}
+
+func (s *Service) get(id string) (*Item, error) {
+	v, err := s.store.GetOutbox(id)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	var it Item
+	if err := json.Unmarshal(v, &it); err != nil {
+		return nil, fmt.Errorf("outbox item %s unreadable: %w", id, err)
+	}
+	return &it, nil
+}
+
+func (s *Service) list() ([]Item, error) {
+	var out []Item
+	err := s.store.ForEachOutbox(func(id string, v []byte) error {
+		var it Item
+		if err := json.Unmarshal(v, &it); err != nil {
+			logrus.Warnf("outbox item %s unreadable: %v", id, err)
+			return nil
+		}
+		out = append(out, it)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+// drop removes items recorded for a block that was not appended.
+func (s *Service) drop(ids []string) {
+	for _, id := range ids {
+		if err := s.store.DeleteOutbox(id); err != nil {
+			logrus.Warnf("failed to drop outbox item %s: %v", id, err)
+		}
+	}
+}
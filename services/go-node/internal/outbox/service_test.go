@@ -0,0 +1,381 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"digital-eval-system/services/go-node/internal/block"
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/storage"
+)
+
+// fakeStore keeps blocks and outbox items in memory.
+type fakeStore struct {
+	storage.Storage
+	blocks    map[string]*block.Block
+	head      string
+	items     map[string][]byte
+	deleteErr error // returned once by DeleteOutbox
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{blocks: map[string]*block.Block{}, items: map[string][]byte{}}
+}
+
+func (f *fakeStore) PutBlock(hash string, b *block.Block) error { f.blocks[hash] = b; return nil }
+func (f *fakeStore) SetHead(hash string) error                  { f.head = hash; return nil }
+func (f *fakeStore) Head() (string, error)                      { return f.head, nil }
+
+func (f *fakeStore) GetBlock(hash string) (*block.Block, error) {
+	b, ok := f.blocks[hash]
+	if !ok {
+		return nil, fmt.Errorf("block %s not found", hash)
+	}
+	return b, nil
+}
+
+func (f *fakeStore) PutOutbox(id string, v []byte) error { f.items[id] = v; return nil }
+func (f *fakeStore) GetOutbox(id string) ([]byte, error) { return f.items[id], nil }
+
+func (f *fakeStore) DeleteOutbox(id string) error {
+	if err := f.deleteErr; err != nil {
+		f.deleteErr = nil
+		return err
+	}
+	delete(f.items, id)
+	return nil
+}
+
+func (f *fakeStore) ForEachOutbox(fn func(id string, v []byte) error) error {
+	for id, v := range f.items {
+		if err := fn(id, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fakeChain appends to the fake store, or fails with err.
+type fakeChain struct {
+	store *fakeStore
+	err   error
+}
+
+func (c *fakeChain) AppendBlock(b *block.Block) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+	h, err := block.BlockHash(b)
+	if err != nil {
+		return "", err
+	}
+	c.store.PutBlock(h, b)
+	c.store.SetHead(h)
+	return h, nil
+}
+
+// rows is an idempotent applier: it keeps one row per block hash and fails
+// while err is set.
+type rows struct {
+	byBlock map[string]string
+	calls   int
+	err     error
+}
+
+func (r *rows) apply(ctx context.Context, it *Item) error {
+	r.calls++
+	if r.err != nil {
+		return r.err
+	}
+	if _, ok := r.byBlock[it.BlockHash]; ok {
+		return nil
+	}
+	var v string
+	if err := json.Unmarshal(it.Payload, &v); err != nil {
+		return err
+	}
+	r.byBlock[it.BlockHash] = v
+	return nil
+}
+
+const kindTest = "test_row"
+
+func newTestService(p Policy) (*Service, *fakeStore, *fakeChain, *rows) {
+	store := newFakeStore()
+	s := NewService(nil, store, p)
+	r := &rows{byBlock: map[string]string{}}
+	s.Register(kindTest, r.apply)
+	return s, store, &fakeChain{store: store}, r
+}
+
+func testBlock(scriptID string) *block.Block {
+	tx := block.Transaction{ScriptID: scriptID, Meta: map[string]string{"_test": scriptID}, CreatedAt: time.Now().UnixNano(), SignerID: "node"}
+	return block.NewBlock("", []block.Transaction{tx}, "node")
+}
+
+// due makes an item's next attempt due now.
+func due(t *testing.T, s *Service, id string) {
+	t.Helper()
+	it, err := s.get(id)
+	if err != nil || it == nil {
+		t.Fatalf("item %s: %v", id, err)
+	}
+	it.NextAttempt = time.Now().UTC().Add(-time.Second)
+	if err := s.put(it); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAppend(t *testing.T) {
+	tests := []struct {
+		name      string
+		kind      string
+		chainErr  error
+		wantErr   bool
+		wantItems int
+	}{
+		{name: "records items before the block", kind: kindTest, wantItems: 1},
+		{name: "unknown kind", kind: "nope", wantErr: true},
+		{name: "append fails", kind: kindTest, chainErr: errors.New("disk full"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, store, ch, _ := newTestService(Default())
+			ch.err = tt.chainErr
+			blk := testBlock("S1")
+			want, _ := block.BlockHash(blk)
+			hash, ids, err := s.Append(context.Background(), ch, blk, Effect{Kind: kindTest, Payload: "row"}, Effect{Kind: tt.kind, Payload: "row"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Append error = %v, want error %v", err, tt.wantErr)
+			}
+			if len(store.items) != 2*tt.wantItems {
+				t.Fatalf("outbox has %d items, want %d", len(store.items), 2*tt.wantItems)
+			}
+			if tt.wantErr {
+				return
+			}
+			if hash != want || len(ids) != 2 {
+				t.Fatalf("Append = %s %v, want %s with 2 items", hash, ids, want)
+			}
+			it, _ := s.Get(ids[0])
+			if it.BlockHash != hash || it.Kind != kindTest || it.Attempts != 0 {
+				t.Fatalf("item = %+v", it)
+			}
+		})
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	ctx := context.Background()
+	s, store, ch, r := newTestService(Default())
+	hash, ids, err := s.Append(ctx, ch, testBlock("S1"), Effect{Kind: kindTest, Payload: "row"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.err = errors.New("postgres down")
+	if err := s.Deliver(ctx, ids...); err == nil {
+		t.Fatal("Deliver succeeded with a failing applier")
+	}
+	it, _ := s.Get(ids[0])
+	if it.Attempts != 1 || it.LastError != "postgres down" || it.Stuck {
+		t.Fatalf("failed item = %+v", it)
+	}
+	if !it.NextAttempt.After(time.Now()) {
+		t.Fatalf("failed item not rescheduled: %v", it.NextAttempt)
+	}
+
+	r.err = nil
+	if err := s.Deliver(ctx, ids...); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.items) != 0 || r.byBlock[hash] != "row" {
+		t.Fatalf("after delivery: items %d, rows %v", len(store.items), r.byBlock)
+	}
+	// delivering again is a no-op
+	if err := s.Deliver(ctx, ids...); err != nil {
+		t.Fatal(err)
+	}
+	if r.calls != 2 {
+		t.Fatalf("applier called %d times, want 2", r.calls)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+	s, store, ch, r := newTestService(Default())
+	_, sent, _ := s.Append(ctx, ch, testBlock("S1"), Effect{Kind: kindTest, Payload: "due"})
+	_, later, _ := s.Append(ctx, ch, testBlock("S2"), Effect{Kind: kindTest, Payload: "later"})
+	// an item whose block never reached the chain
+	lost := &Item{ID: "lost", Kind: kindTest, BlockHash: "missing", Payload: json.RawMessage(`"lost"`), CreatedAt: time.Now().UTC()}
+	s.put(lost)
+	due(t, s, sent[0])
+	due(t, s, "lost")
+
+	rep, err := s.Retry(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Delivered) != 1 || rep.Delivered[0] != sent[0] {
+		t.Fatalf("delivered %v, want [%s]", rep.Delivered, sent[0])
+	}
+	if len(rep.Abandoned) != 1 || rep.Abandoned[0] != "lost" {
+		t.Fatalf("abandoned %v, want [lost]", rep.Abandoned)
+	}
+	if rep.Pending != 1 || len(rep.Failed) != 0 {
+		t.Fatalf("report = %+v", rep)
+	}
+	if _, ok := store.items[later[0]]; !ok || len(store.items) != 1 {
+		t.Fatalf("outbox = %v, want only %s", store.items, later[0])
+	}
+	if len(r.byBlock) != 1 {
+		t.Fatalf("rows = %v", r.byBlock)
+	}
+}
+
+func TestRetryMarksStuck(t *testing.T) {
+	ctx := context.Background()
+	p := Default()
+	p.StuckAttempts = 3
+	s, _, ch, r := newTestService(p)
+	_, ids, _ := s.Append(ctx, ch, testBlock("S1"), Effect{Kind: kindTest, Payload: "row"})
+	r.err = errors.New("constraint violation")
+
+	for attempt := 1; attempt <= 4; attempt++ {
+		due(t, s, ids[0])
+		rep, err := s.Retry(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		it, _ := s.Get(ids[0])
+		wantStuck := attempt >= p.StuckAttempts
+		if it.Attempts != attempt || it.Stuck != wantStuck || len(rep.Failed) != 1 {
+			t.Fatalf("attempt %d: item = %+v, report = %+v", attempt, it, rep)
+		}
+		if wantStuck && rep.Stuck != 1 {
+			t.Fatalf("attempt %d: report counts %d stuck", attempt, rep.Stuck)
+		}
+		stuck, _ := s.List(true)
+		if (len(stuck) == 1) != wantStuck {
+			t.Fatalf("attempt %d: stuck list = %v", attempt, stuck)
+		}
+	}
+}
+
+func TestReplayIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	s, store, ch, r := newTestService(Default())
+	hash, ids, _ := s.Append(ctx, ch, testBlock("S1"), Effect{Kind: kindTest, Payload: "row"})
+
+	// the row is written but the node stops before the item is removed
+	store.deleteErr = errors.New("node stopped")
+	if err := s.Deliver(ctx, ids...); err == nil {
+		t.Fatal("Deliver hid the failed removal")
+	}
+	if _, ok := store.items[ids[0]]; !ok {
+		t.Fatal("item removed although its removal failed")
+	}
+
+	due(t, s, ids[0])
+	rep, err := s.Retry(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Delivered) != 1 || len(store.items) != 0 {
+		t.Fatalf("replay: report %+v, outbox %v", rep, store.items)
+	}
+	if r.calls != 2 || len(r.byBlock) != 1 || r.byBlock[hash] != "row" {
+		t.Fatalf("replay: %d calls, rows %v", r.calls, r.byBlock)
+	}
+}
+
+func TestDeliverFromApplier(t *testing.T) {
+	// an applier that appends a follow-up block delivers its items itself
+	ctx := context.Background()
+	s, store, ch, r := newTestService(Default())
+	s.Register("parent", func(ctx context.Context, it *Item) error {
+		_, ids, err := s.Append(ctx, ch, testBlock("child"), Effect{Kind: kindTest, Payload: "child"})
+		if err != nil {
+			return err
+		}
+		return s.Deliver(ctx, ids...)
+	})
+	_, ids, _ := s.Append(ctx, ch, testBlock("parent"), Effect{Kind: "parent", Payload: "parent"})
+	if err := s.Deliver(ctx, ids...); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.items) != 0 || len(r.byBlock) != 1 {
+		t.Fatalf("outbox %v, rows %v", store.items, r.byBlock)
+	}
+}
+
+func TestClaimedItemIsSkipped(t *testing.T) {
+	ctx := context.Background()
+	s, _, ch, r := newTestService(Default())
+	_, ids, _ := s.Append(ctx, ch, testBlock("S1"), Effect{Kind: kindTest, Payload: "row"})
+	due(t, s, ids[0])
+	s.claim(ids[0])
+
+	if err := s.Deliver(ctx, ids...); err != nil {
+		t.Fatal(err)
+	}
+	rep, err := s.Retry(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.calls != 0 || rep.Pending != 1 {
+		t.Fatalf("claimed item applied: %d calls, report %+v", r.calls, rep)
+	}
+	if _, err := s.RetryNow(ctx, ids[0]); err != ErrBusy {
+		t.Fatalf("RetryNow = %v, want ErrBusy", err)
+	}
+	if err := s.Discard(ids[0], "admin"); err != ErrBusy {
+		t.Fatalf("Discard = %v, want ErrBusy", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := Policy{RetrySeconds: 30, MaxBackoffMinutes: 2, StuckAttempts: 5}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{10, 2 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := p.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestModerationPayload(t *testing.T) {
+	// a note carries no marks and must still have none after the round trip
+	note := db.ModerationWrite{Moderation: db.ModerationRow{EvaluationID: 7, Kind: "note", Changes: json.RawMessage(`{}`)}}
+	note.Current.ID = 7
+	note.Current.Marks = json.RawMessage(`{"marks_scored":[1,2]}`)
+	adjust := note
+	adjust.Marks = []byte(`{"marks_scored":[2,2]}`)
+
+	raw, err := json.Marshal(Moderation{Writes: []db.ModerationWrite{note, adjust}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Moderation
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Writes) != 2 || got.Writes[0].Marks != nil || string(got.Writes[1].Marks) != string(adjust.Marks) {
+		t.Fatalf("writes = %+v", got.Writes)
+	}
+	if got.Writes[0].Current.ID != 7 || string(got.Writes[0].Current.Marks) != string(note.Current.Marks) {
+		t.Fatalf("current = %+v", got.Writes[0].Current)
+	}
+}
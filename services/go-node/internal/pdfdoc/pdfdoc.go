@@ -0,0 +1,131 @@
+// Package pdfdoc draws the parts shared by the institute's PDF documents:
+// the letterhead, the label fonts and bilingual table headers.
+package pdfdoc
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"digital-eval-system/services/go-node/internal/i18n"
+)
+
+// Options locate the letterhead resources and pick the label language.
+type Options struct {
+	LogoPath string    // e.g. services/go-node/internal/student/assets/biet_logo.jpg
+	FontDir  string    // e.g. services/go-node/internal/student/assets/fonts
+	Lang     i18n.Lang // label language: en (default), kn or bi (bilingual)
+}
+
+// NewInstitutePDF sets up an A4 document with the report fonts registered and
+// the institute header (logo, name, motto) already drawn on the first page.
+// Every institute document (marks card, transcript, remuneration statement)
+// starts from it.
+func NewInstitutePDF(opts Options) *gofpdf.Fpdf {
+	// ---------------------------------------------------------------------
+	// Font / resource paths
+	// ---------------------------------------------------------------------
+	roboto := filepath.Join(opts.FontDir, "Roboto-Regular.ttf")
+	robotoB := filepath.Join(opts.FontDir, "Roboto-Bold.ttf")
+	kannada := filepath.Join(opts.FontDir, "NotoSansKannada-Regular.ttf")
+
+	// ---------------------------------------------------------------------
+	// Setup PDF
+	// ---------------------------------------------------------------------
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 18)
+	pdf.AddPage()
+
+	// Add fonts (safe adds; if files missing gofpdf will fallback)
+	pdf.AddUTF8Font("Rob", "", roboto)
+	pdf.AddUTF8Font("RobB", "", robotoB)
+	pdf.AddUTF8Font("Kan", "", kannada)
+
+	// ---------------------------------------------------------------------
+	// Header
+	// ---------------------------------------------------------------------
+	if opts.LogoPath != "" {
+		// place logo left, keep height ~26mm
+		pdf.Image(opts.LogoPath, 15, 12, 26, 0, false, "", 0, "")
+	}
+
+	pdf.SetXY(15, 12)
+	if opts.Lang != i18n.Kannada {
+		pdf.SetFont("RobB", "", 14)
+		pdf.CellFormat(180, 10, i18n.T(i18n.English, "institute.name"), "", 1, "C", false, 0, "")
+	}
+	if opts.Lang.NeedsKannadaFont() {
+		pdf.SetFont("Kan", "", 14)
+		pdf.CellFormat(180, 10, i18n.T(i18n.Kannada, "institute.name"), "", 1, "C", false, 0, "")
+	}
+
+	LabelFont(pdf, opts.Lang, false, 12)
+	pdf.CellFormat(180, 6, i18n.T(opts.Lang, "institute.place"), "", 1, "C", false, 0, "")
+
+	// Kannada motto (if font available)
+	pdf.SetFont("Kan", "", 11)
+	pdf.CellFormat(180, 7, "ಕರ್ಮಣೇಯೇವಾಧಿಕಾರಸ್ತೇ ಮಾಫಲೇಷು ಕದಾಚನ", "", 1, "C", false, 0, "")
+	pdf.Ln(6)
+
+	return pdf
+}
+
+// LabelFont selects the font for catalog labels. Roboto has no Kannada
+// glyphs, so Kannada and bilingual labels use the Noto Kannada font (which
+// also covers Latin) and bold is not available for them.
+func LabelFont(pdf *gofpdf.Fpdf, lang i18n.Lang, bold bool, size float64) {
+	switch {
+	case lang.NeedsKannadaFont():
+		pdf.SetFont("Kan", "", size)
+	case bold:
+		pdf.SetFont("RobB", "", size)
+	default:
+		pdf.SetFont("Rob", "", size)
+	}
+}
+
+// InfoText formats a "label: value" pair for an info box.
+func InfoText(lang i18n.Lang, key, value string) string {
+	return fmt.Sprintf("%s: %s", i18n.T(lang, key), value)
+}
+
+// HeaderCol is a table column: its width and the catalog key of its title.
+type HeaderCol struct {
+	W   float64
+	Key string
+}
+
+// TableHeader draws a filled header row from catalog keys. Bilingual headers
+// stack English over Kannada in a double-height row so narrow columns stay
+// readable; the cursor is left at the start of the next line.
+func TableHeader(pdf *gofpdf.Fpdf, lang i18n.Lang, size, h float64, cols []HeaderCol) {
+	if lang != i18n.Bilingual {
+		LabelFont(pdf, lang, true, size)
+		for i, c := range cols {
+			ln := 0
+			if i == len(cols)-1 {
+				ln = 1
+			}
+			pdf.CellFormat(c.W, h, i18n.T(lang, c.Key), "1", ln, "C", true, 0, "")
+		}
+		return
+	}
+
+	x0, y0 := pdf.GetXY()
+	x := x0
+	for _, c := range cols {
+		en, kn := i18n.Pair(c.Key)
+		pdf.SetXY(x, y0)
+		pdf.CellFormat(c.W, 2*h, "", "1", 0, "C", true, 0, "")
+		pdf.SetXY(x, y0)
+		pdf.SetFont("RobB", "", size-2)
+		pdf.CellFormat(c.W, h, en, "", 0, "C", false, 0, "")
+		pdf.SetXY(x, y0+h)
+		pdf.SetFont("Kan", "", size-2)
+		pdf.CellFormat(c.W, h, kn, "", 0, "C", false, 0, "")
+		x += c.W
+	}
+	pdf.SetXY(x0, y0+2*h)
+}
@@ -172,3 +172,39 @@ func (c *Client) ValidateEvaluation(ctx context.Context, in EvalValidationInput)
 	}
 	return &out, nil
 }
+
+// MaskPDF calls Python /mask: the script PDF behind cid is copied with the
+// student identifiers redacted and label stamped in their place. Returns the
+// CID of the masked copy.
+func (c *Client) MaskPDF(ctx context.Context, in MaskRequest) (*MaskResponse, error) {
+	if c == nil {
+		return nil, fmt.Errorf("pybridge client nil")
+	}
+	b, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/mask", bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("pybridge mask request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("python mask error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var out MaskResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("decode mask response: %w", err)
+	}
+	if out.MaskedCID == "" {
+		return nil, fmt.Errorf("python mask returned no CID")
+	}
+	return &out, nil
+}
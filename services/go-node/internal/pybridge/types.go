@@ -28,3 +28,14 @@ type ValidateResponse struct {
 	Errors []string `json:"errors,omitempty"`
 	Error  string   `json:"error,omitempty"`
 }
+
+type MaskRequest struct {
+	CID            string  `json:"cid"`
+	Label          string  `json:"label,omitempty"`
+	HeaderFraction float64 `json:"header_fraction,omitempty"`
+}
+
+type MaskResponse struct {
+	Status    string `json:"status"`
+	MaskedCID string `json:"masked_cid"`
+}
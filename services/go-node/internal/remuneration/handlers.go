@@ -0,0 +1,244 @@
+package remuneration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/i18n"
+)
+
+// Handler exposes remuneration rate and statement endpoints.
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GET /api/v1/admin/remuneration/rates
+func (h *Handler) ListRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.svc.Rates(r.Context())
+	if err != nil {
+		http.Error(w, "failed to load rates", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rates, http.StatusOK)
+}
+
+// POST /api/v1/admin/remuneration/rates
+// Creates the rate or replaces the one stored for the same scope.
+func (h *Handler) SaveRate(w http.ResponseWriter, r *http.Request) {
+	var rate Rate
+	if err := json.NewDecoder(r.Body).Decode(&rate); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	rate.Normalize()
+	if err := rate.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	id, err := h.svc.SaveRate(r.Context(), &rate)
+	if err != nil {
+		http.Error(w, "save failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"id": id}, http.StatusOK)
+}
+
+// DELETE /api/v1/admin/remuneration/rates/{id}
+func (h *Handler) DeleteRate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.DeleteRate(r.Context(), id); err != nil {
+		http.Error(w, "delete failed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "deleted"}, http.StatusOK)
+}
+
+// POST /api/v1/authority/remuneration/compute
+// body: {"semester": "...", "academic_year": "..."}
+// Recomputes the cycle's draft statements from its evaluations.
+func (h *Handler) Compute(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Semester     string `json:"semester"`
+		AcademicYear string `json:"academic_year"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(body.Semester) == "" || strings.TrimSpace(body.AcademicYear) == "" {
+		http.Error(w, "semester and academic_year are required", http.StatusBadRequest)
+		return
+	}
+	rep, err := h.svc.Compute(r.Context(), body.Semester, body.AcademicYear)
+	if err != nil {
+		http.Error(w, "compute failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rep, http.StatusOK)
+}
+
+// GET /api/v1/authority/remuneration/statements?semester=&academic_year=&evaluator_id=&status=&format=json|csv
+func (h *Handler) ListStatements(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	sts, err := h.svc.Statements(r.Context(), q.Get("semester"), q.Get("academic_year"), q.Get("evaluator_id"), q.Get("status"))
+	if err != nil {
+		http.Error(w, "failed to load statements", http.StatusInternalServerError)
+		return
+	}
+	switch format := strings.ToLower(q.Get("format")); format {
+	case "", "json":
+		writeJSON(w, sts, http.StatusOK)
+	case "csv":
+		data, err := StatementsCSV(sts)
+		if err != nil {
+			http.Error(w, "failed to render csv", http.StatusInternalServerError)
+			return
+		}
+		name := "remuneration"
+		if s := q.Get("semester"); s != "" {
+			name += "_sem" + s
+		}
+		if y := q.Get("academic_year"); y != "" {
+			name += "_" + y
+		}
+		writeFile(w, data, "text/csv", name+".csv")
+	default:
+		http.Error(w, "unsupported format "+format, http.StatusBadRequest)
+	}
+}
+
+// GET /api/v1/authority/remuneration/statements/{id}?format=json|csv|pdf&lang=en|kn|bi
+func (h *Handler) GetStatement(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	st, err := h.svc.Statement(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	name := fmt.Sprintf("remuneration_%s_sem%s_%s", st.EvaluatorID, st.Semester, st.AcademicYear)
+	switch format := strings.ToLower(r.URL.Query().Get("format")); format {
+	case "", "json":
+		writeJSON(w, st, http.StatusOK)
+	case "csv":
+		data, err := st.CSV()
+		if err != nil {
+			http.Error(w, "failed to render csv", http.StatusInternalServerError)
+			return
+		}
+		writeFile(w, data, "text/csv", name+".csv")
+	case "pdf":
+		data, err := st.PDF(i18n.ParseLang(r.URL.Query().Get("lang")))
+		if err != nil {
+			http.Error(w, "failed to render pdf", http.StatusInternalServerError)
+			return
+		}
+		writeFile(w, data, "application/pdf", name+".pdf")
+	default:
+		http.Error(w, "unsupported format "+format, http.StatusBadRequest)
+	}
+}
+
+// POST /api/v1/authority/remuneration/statements/{id}/approve
+// body: {"authority_id": "..."}
+func (h *Handler) Approve(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	var body struct {
+		AuthorityID string `json:"authority_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.AuthorityID == "" {
+		http.Error(w, "authority_id is required", http.StatusBadRequest)
+		return
+	}
+	st, err := h.svc.Approve(r.Context(), id, body.AuthorityID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, st, http.StatusOK)
+}
+
+// POST /api/v1/authority/remuneration/statements/{id}/payout
+// body: {"payout_reference": "...", "authority_id": "..."}
+func (h *Handler) RecordPayout(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	var body struct {
+		PayoutReference string `json:"payout_reference"`
+		AuthorityID     string `json:"authority_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(body.PayoutReference) == "" || body.AuthorityID == "" {
+		http.Error(w, "payout_reference and authority_id are required", http.StatusBadRequest)
+		return
+	}
+	st, err := h.svc.RecordPayout(r.Context(), id, body.PayoutReference, body.AuthorityID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, st, http.StatusOK)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrNotDraft), errors.Is(err, ErrUnrated), errors.Is(err, ErrNotApproved):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeFile(w http.ResponseWriter, data []byte, contentType, filename string) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+filename+"\"")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func RegisterRemunerationRoutes(r *mux.Router, svc *Service) {
+	h := NewHandler(svc)
+	r.HandleFunc("/admin/remuneration/rates", h.ListRates).Methods("GET")
+	r.HandleFunc("/admin/remuneration/rates", h.SaveRate).Methods("POST")
+	r.HandleFunc("/admin/remuneration/rates/{id}", h.DeleteRate).Methods("DELETE")
+	r.HandleFunc("/authority/remuneration/compute", h.Compute).Methods("POST")
+	r.HandleFunc("/authority/remuneration/statements", h.ListStatements).Methods("GET")
+	r.HandleFunc("/authority/remuneration/statements/{id}", h.GetStatement).Methods("GET")
+	r.HandleFunc("/authority/remuneration/statements/{id}/approve", h.Approve).Methods("POST")
+	r.HandleFunc("/authority/remuneration/statements/{id}/payout", h.RecordPayout).Methods("POST")
+}
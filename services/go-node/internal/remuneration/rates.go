@@ -0,0 +1,80 @@
+package remuneration
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Valuation types a script is paid under.
+const (
+	TypeSingle      = "single"      // the only valuation of a script
+	TypeDouble      = "double"      // one of two independent valuations
+	TypeThird       = "third"       // third valuation after a discrepancy
+	TypeRevaluation = "revaluation" // revaluation requested by the student
+)
+
+// Rate is the amount paid per script. Empty scope fields match anything.
+type Rate struct {
+	ID            int64   `json:"id,omitempty"`
+	CourseType    string  `json:"course_type"`
+	ValuationType string  `json:"valuation_type"`
+	AcademicYear  string  `json:"academic_year"`
+	RatePerScript float64 `json:"rate_per_script"`
+}
+
+// Normalize trims and lower-cases the scope.
+func (r *Rate) Normalize() {
+	r.CourseType = strings.ToLower(strings.TrimSpace(r.CourseType))
+	r.ValuationType = strings.ToLower(strings.TrimSpace(r.ValuationType))
+	r.AcademicYear = strings.TrimSpace(r.AcademicYear)
+}
+
+// Validate checks the valuation type and amount.
+func (r *Rate) Validate() error {
+	switch r.ValuationType {
+	case "", TypeSingle, TypeDouble, TypeThird, TypeRevaluation:
+	default:
+		return fmt.Errorf("valuation_type must be empty or one of %s, %s, %s, %s", TypeSingle, TypeDouble, TypeThird, TypeRevaluation)
+	}
+	if r.RatePerScript < 0 {
+		return fmt.Errorf("rate_per_script must be >= 0")
+	}
+	return nil
+}
+
+// specificity ranks a matching rate: course type beats valuation type beats
+// academic year.
+func (r *Rate) specificity() int {
+	n := 0
+	if r.CourseType != "" {
+		n += 4
+	}
+	if r.ValuationType != "" {
+		n += 2
+	}
+	if r.AcademicYear != "" {
+		n++
+	}
+	return n
+}
+
+func (r *Rate) matches(courseType, valuationType, academicYear string) bool {
+	return (r.CourseType == "" || r.CourseType == courseType) &&
+		(r.ValuationType == "" || r.ValuationType == valuationType) &&
+		(r.AcademicYear == "" || r.AcademicYear == academicYear)
+}
+
+// rateTable is the stored rates, resolved per course type and valuation type.
+type rateTable []Rate
+
+// find returns the most specific matching rate, nil if none.
+func (t rateTable) find(courseType, valuationType, academicYear string) *Rate {
+	var best *Rate
+	for i := range t {
+		r := &t[i]
+		if r.matches(courseType, valuationType, academicYear) && (best == nil || r.specificity() > best.specificity()) {
+			best = r
+		}
+	}
+	return best
+}
@@ -0,0 +1,373 @@
+package remuneration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"digital-eval-system/services/go-node/internal/course"
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/valuation"
+)
+
+// Statement statuses.
+const (
+	StatusDraft    = "draft"
+	StatusApproved = "approved"
+	StatusPaid     = "paid"
+)
+
+var (
+	// ErrNotFound is returned for an unknown statement id.
+	ErrNotFound = errors.New("statement not found")
+	// ErrNotDraft is returned when approving a statement that is not a draft.
+	ErrNotDraft = errors.New("statement is not a draft")
+	// ErrUnrated is returned when approving a statement with scripts no rate covers.
+	ErrUnrated = errors.New("statement has scripts without a rate")
+	// ErrNotApproved is returned when recording a payout for a statement that is not approved.
+	ErrNotApproved = errors.New("statement is not approved")
+)
+
+// Service computes evaluator remuneration from the evaluations of a cycle.
+type Service struct {
+	pg      *db.PostgresDB
+	courses *course.Service
+}
+
+// NewService constructs the remuneration service.
+func NewService(pg *db.PostgresDB, courseSvc *course.Service) *Service {
+	return &Service{pg: pg, courses: courseSvc}
+}
+
+// Line is the part of a statement for one course and valuation type.
+type Line struct {
+	CourseID      string  `json:"course_id"`
+	CourseType    string  `json:"course_type"`
+	ValuationType string  `json:"valuation_type"`
+	Scripts       int     `json:"scripts"`
+	Rated         bool    `json:"rated"` // false when no rate matches; the line pays nothing
+	RatePerScript float64 `json:"rate_per_script"`
+	Amount        float64 `json:"amount"`
+}
+
+// Statement is an evaluator's remuneration for one cycle.
+type Statement struct {
+	db.RemunerationStatementRow
+	Lines []Line `json:"lines"`
+}
+
+// MissingRate is a course type and valuation type no rate covers.
+type MissingRate struct {
+	CourseType    string `json:"course_type"`
+	ValuationType string `json:"valuation_type"`
+	Scripts       int    `json:"scripts"`
+}
+
+// ComputeReport is the outcome of computing a cycle.
+type ComputeReport struct {
+	Semester     string        `json:"semester"`
+	AcademicYear string        `json:"academic_year"`
+	Evaluators   int           `json:"evaluators"`
+	Scripts      int           `json:"scripts"`
+	Amount       float64       `json:"amount"`
+	Drafts       int           `json:"drafts"`             // statements written as drafts
+	Frozen       []string      `json:"frozen,omitempty"`   // evaluators whose approved or paid statement was kept
+	Diverged     []string      `json:"diverged,omitempty"` // frozen statements the evaluations no longer agree with
+	MissingRates []MissingRate `json:"missing_rates,omitempty"`
+}
+
+// work is one paid piece of evaluation.
+type work struct {
+	evaluatorID   string
+	courseID      string
+	valuationType string
+}
+
+// Rates returns every stored rate.
+func (s *Service) Rates(ctx context.Context) ([]Rate, error) {
+	rows, err := s.pg.ListRemunerationRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Rate, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, Rate{ID: r.ID, CourseType: r.CourseType, ValuationType: r.ValuationType, AcademicYear: r.AcademicYear, RatePerScript: r.RatePerScript})
+	}
+	return out, nil
+}
+
+// SaveRate validates and stores a rate, replacing the one with the same scope.
+func (s *Service) SaveRate(ctx context.Context, r *Rate) (int64, error) {
+	r.Normalize()
+	if err := r.Validate(); err != nil {
+		return 0, err
+	}
+	return s.pg.UpsertRemunerationRate(ctx, db.RemunerationRateRow{
+		CourseType:    r.CourseType,
+		ValuationType: r.ValuationType,
+		AcademicYear:  r.AcademicYear,
+		RatePerScript: round2(r.RatePerScript),
+	})
+}
+
+// DeleteRate removes a stored rate.
+func (s *Service) DeleteRate(ctx context.Context, id int64) error {
+	return s.pg.DeleteRemunerationRate(ctx, id)
+}
+
+// Compute pays every evaluation of a cycle to the evaluators who did it:
+// each valuation of a double-valued script, the single valuation otherwise,
+// and every completed revaluation. Draft statements of the cycle are
+// replaced; approved and paid statements are kept and reported as diverged
+// when the evaluations no longer agree with them.
+func (s *Service) Compute(ctx context.Context, semester, academicYear string) (*ComputeReport, error) {
+	semester, academicYear = strings.TrimSpace(semester), strings.TrimSpace(academicYear)
+	if semester == "" || academicYear == "" {
+		return nil, fmt.Errorf("semester and academic_year are required")
+	}
+	items, err := s.collect(ctx, semester, academicYear)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := s.Rates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load rates: %w", err)
+	}
+	var codes []string
+	seen := map[string]bool{}
+	for _, it := range items {
+		if c := strings.ToUpper(it.courseID); !seen[c] {
+			seen[c] = true
+			codes = append(codes, c)
+		}
+	}
+	catalog, err := s.courses.Catalog(ctx, codes)
+	if err != nil {
+		return nil, fmt.Errorf("load courses: %w", err)
+	}
+
+	type lineKey struct{ evaluator, course, vtype string }
+	lines := map[lineKey]*Line{}
+	for _, it := range items {
+		k := lineKey{it.evaluatorID, strings.ToUpper(it.courseID), it.valuationType}
+		if lines[k] == nil {
+			co, _ := catalog.Get(it.courseID)
+			lines[k] = &Line{CourseID: k.course, CourseType: co.CourseType, ValuationType: it.valuationType}
+		}
+		lines[k].Scripts++
+	}
+
+	statements := map[string]*Statement{}
+	missing := map[[2]string]int{}
+	for k, l := range lines {
+		if r := rateTable(rates).find(l.CourseType, l.ValuationType, academicYear); r != nil {
+			l.Rated = true
+			l.RatePerScript = r.RatePerScript
+			l.Amount = round2(r.RatePerScript * float64(l.Scripts))
+		} else {
+			missing[[2]string{l.CourseType, l.ValuationType}] += l.Scripts
+		}
+		st := statements[k.evaluator]
+		if st == nil {
+			st = &Statement{RemunerationStatementRow: db.RemunerationStatementRow{EvaluatorID: k.evaluator, Semester: semester, AcademicYear: academicYear, Status: StatusDraft}}
+			statements[k.evaluator] = st
+		}
+		st.Lines = append(st.Lines, *l)
+		st.Scripts += l.Scripts
+		st.Amount = round2(st.Amount + l.Amount)
+		if !l.Rated {
+			st.Unrated += l.Scripts
+		}
+	}
+
+	existing, err := s.pg.ListRemunerationStatements(ctx, semester, academicYear, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("load statements: %w", err)
+	}
+	frozen := map[string]db.RemunerationStatementRow{}
+	for _, e := range existing {
+		if e.Status != StatusDraft {
+			frozen[e.EvaluatorID] = e
+		}
+	}
+
+	rep := &ComputeReport{Semester: semester, AcademicYear: academicYear, Evaluators: len(statements)}
+	var drafts []db.RemunerationStatementRow
+	for id, st := range statements {
+		sortLines(st.Lines)
+		rep.Scripts += st.Scripts
+		rep.Amount = round2(rep.Amount + st.Amount)
+		if f, ok := frozen[id]; ok {
+			rep.Frozen = append(rep.Frozen, id)
+			if f.Scripts != st.Scripts || f.Amount != st.Amount {
+				rep.Diverged = append(rep.Diverged, id)
+			}
+			continue
+		}
+		st.RemunerationStatementRow.Lines, _ = json.Marshal(st.Lines)
+		drafts = append(drafts, st.RemunerationStatementRow)
+	}
+	// an evaluator left with no work keeps a frozen statement but not a draft
+	for id, f := range frozen {
+		if _, ok := statements[id]; !ok {
+			rep.Frozen = append(rep.Frozen, id)
+			if f.Scripts != 0 {
+				rep.Diverged = append(rep.Diverged, id)
+			}
+		}
+	}
+	if err := s.pg.ReplaceDraftStatements(ctx, semester, academicYear, drafts); err != nil {
+		return nil, fmt.Errorf("store statements: %w", err)
+	}
+	rep.Drafts = len(drafts)
+	for k, n := range missing {
+		rep.MissingRates = append(rep.MissingRates, MissingRate{CourseType: k[0], ValuationType: k[1], Scripts: n})
+	}
+	sort.Slice(rep.MissingRates, func(i, j int) bool {
+		a, b := rep.MissingRates[i], rep.MissingRates[j]
+		if a.CourseType != b.CourseType {
+			return a.CourseType < b.CourseType
+		}
+		return a.ValuationType < b.ValuationType
+	})
+	sort.Strings(rep.Frozen)
+	sort.Strings(rep.Diverged)
+	if len(rep.Diverged) > 0 {
+		logrus.Warnf("remuneration %s %s: approved statements of %v no longer match the evaluations", semester, academicYear, rep.Diverged)
+	}
+	return rep, nil
+}
+
+// collect lists the paid work of a cycle.
+func (s *Service) collect(ctx context.Context, semester, academicYear string) ([]work, error) {
+	evals, err := s.pg.ListRemunerableEvaluations(ctx, semester, academicYear)
+	if err != nil {
+		return nil, fmt.Errorf("load evaluations: %w", err)
+	}
+	var out []work
+	for _, e := range evals {
+		var vals []valuation.Valuation
+		if len(e.Valuations) > 0 {
+			if err := json.Unmarshal(e.Valuations, &vals); err != nil {
+				logrus.Warnf("remuneration: evaluation %d has unreadable valuations, paid as single: %v", e.EvaluationID, err)
+				vals = nil
+			}
+		}
+		if len(vals) == 0 {
+			out = append(out, work{e.EvaluatorID, e.CourseID, TypeSingle})
+			continue
+		}
+		for _, v := range vals {
+			t := TypeDouble
+			if v.Round > 2 {
+				t = TypeThird
+			}
+			out = append(out, work{v.EvaluatorID, e.CourseID, t})
+		}
+	}
+	revals, err := s.pg.ListCompletedRevaluations(ctx, semester, academicYear)
+	if err != nil {
+		return nil, fmt.Errorf("load revaluations: %w", err)
+	}
+	for _, r := range revals {
+		out = append(out, work{r.EvaluatorID, r.CourseID, TypeRevaluation})
+	}
+	return out, nil
+}
+
+// Statements returns statements filtered by cycle, evaluator and status.
+func (s *Service) Statements(ctx context.Context, semester, academicYear, evaluatorID, status string) ([]Statement, error) {
+	rows, err := s.pg.ListRemunerationStatements(ctx, semester, academicYear, evaluatorID, status)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Statement, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, fromRow(r))
+	}
+	return out, nil
+}
+
+// Statement returns one statement.
+func (s *Service) Statement(ctx context.Context, id int64) (*Statement, error) {
+	row, err := s.pg.GetRemunerationStatement(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return nil, ErrNotFound
+	}
+	st := fromRow(*row)
+	return &st, nil
+}
+
+// Approve freezes a draft statement; later computations leave it alone.
+func (s *Service) Approve(ctx context.Context, id int64, approvedBy string) (*Statement, error) {
+	if strings.TrimSpace(approvedBy) == "" {
+		return nil, fmt.Errorf("authority_id is required")
+	}
+	row, err := s.pg.ApproveRemunerationStatement(ctx, id, approvedBy)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		cur, err := s.Statement(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if cur.Status != StatusDraft {
+			return nil, fmt.Errorf("%w (status %s)", ErrNotDraft, cur.Status)
+		}
+		return nil, fmt.Errorf("%w: %d scripts", ErrUnrated, cur.Unrated)
+	}
+	logrus.Infof("remuneration statement %d (%s, %s %s) approved by %s: %.2f", row.ID, row.EvaluatorID, row.Semester, row.AcademicYear, approvedBy, row.Amount)
+	st := fromRow(*row)
+	return &st, nil
+}
+
+// RecordPayout marks an approved statement paid under finance's payout reference.
+func (s *Service) RecordPayout(ctx context.Context, id int64, reference, paidBy string) (*Statement, error) {
+	reference = strings.TrimSpace(reference)
+	if reference == "" || strings.TrimSpace(paidBy) == "" {
+		return nil, fmt.Errorf("payout_reference and authority_id are required")
+	}
+	row, err := s.pg.RecordRemunerationPayout(ctx, id, reference, paidBy)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		cur, err := s.Statement(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w (status %s)", ErrNotApproved, cur.Status)
+	}
+	logrus.Infof("remuneration statement %d (%s) paid by %s, reference %s", row.ID, row.EvaluatorID, paidBy, reference)
+	st := fromRow(*row)
+	return &st, nil
+}
+
+func fromRow(r db.RemunerationStatementRow) Statement {
+	st := Statement{RemunerationStatementRow: r}
+	if err := json.Unmarshal(r.Lines, &st.Lines); err != nil || st.Lines == nil {
+		st.Lines = []Line{}
+	}
+	return st
+}
+
+func sortLines(lines []Line) {
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].CourseID != lines[j].CourseID {
+			return lines[i].CourseID < lines[j].CourseID
+		}
+		return lines[i].ValuationType < lines[j].ValuationType
+	})
+}
+
+func round2(v float64) float64 { return math.Round(v*100) / 100 }
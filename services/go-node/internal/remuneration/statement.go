@@ -0,0 +1,129 @@
+package remuneration
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"time"
+
+	"digital-eval-system/services/go-node/internal/i18n"
+	"digital-eval-system/services/go-node/internal/pdfdoc"
+	"digital-eval-system/services/go-node/internal/rootdir"
+)
+
+func money(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+
+func rateText(l Line) string {
+	if !l.Rated {
+		return "no rate"
+	}
+	return money(l.RatePerScript)
+}
+
+// CSV renders the statement as one line per course and valuation type
+// followed by a total line.
+func (st *Statement) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	rows := [][]string{{"Evaluator", "Semester", "Academic Year", "Course", "Course Type", "Valuation Type", "Scripts", "Rate", "Amount"}}
+	for _, l := range st.Lines {
+		rows = append(rows, []string{st.EvaluatorID, st.Semester, st.AcademicYear, l.CourseID, l.CourseType, l.ValuationType,
+			strconv.Itoa(l.Scripts), rateText(l), money(l.Amount)})
+	}
+	rows = append(rows, []string{st.EvaluatorID, st.Semester, st.AcademicYear, "TOTAL", "", "", strconv.Itoa(st.Scripts), "", money(st.Amount)})
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// StatementsCSV renders a summary of several statements, one line each, for
+// finance to reconcile against payouts.
+func StatementsCSV(sts []Statement) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	rows := [][]string{{"Statement", "Evaluator", "Semester", "Academic Year", "Scripts", "Unrated", "Amount", "Status", "Approved By", "Payout Reference", "Paid At"}}
+	for _, st := range sts {
+		paidAt := ""
+		if st.PaidAt != nil {
+			paidAt = st.PaidAt.Format(time.RFC3339)
+		}
+		rows = append(rows, []string{strconv.FormatInt(st.ID, 10), st.EvaluatorID, st.Semester, st.AcademicYear,
+			strconv.Itoa(st.Scripts), strconv.Itoa(st.Unrated), money(st.Amount), st.Status, st.ApprovedBy, st.PayoutReference, paidAt})
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PDF renders the statement on the institute letterhead, with labels in lang.
+func (st *Statement) PDF(lang i18n.Lang) ([]byte, error) {
+	pdf := pdfdoc.NewInstitutePDF(pdfdoc.Options{
+		LogoPath: rootdir.Resolve("services/go-node/internal/student/assets/biet_logo.jpg"),
+		FontDir:  rootdir.Resolve("services/go-node/internal/student/assets/fonts"),
+		Lang:     lang,
+	})
+
+	pdfdoc.LabelFont(pdf, lang, true, 12)
+	pdf.CellFormat(0, 9, i18n.T(lang, "pdf.remuneration.title"), "", 1, "C", false, 0, "")
+	pdf.Ln(2)
+
+	pdfdoc.LabelFont(pdf, lang, false, 11)
+	pdf.SetFillColor(248, 248, 248)
+	pdf.CellFormat(95, 8, pdfdoc.InfoText(lang, "pdf.evaluator", st.EvaluatorID), "1", 0, "L", true, 0, "")
+	pdf.CellFormat(95, 8, pdfdoc.InfoText(lang, "pdf.statement_no", strconv.FormatInt(st.ID, 10)), "1", 1, "L", true, 0, "")
+	pdf.CellFormat(95, 8, pdfdoc.InfoText(lang, "pdf.semester", st.Semester), "1", 0, "L", true, 0, "")
+	pdf.CellFormat(95, 8, pdfdoc.InfoText(lang, "pdf.academic_year", st.AcademicYear), "1", 1, "L", true, 0, "")
+	pdf.CellFormat(95, 8, pdfdoc.InfoText(lang, "pdf.status", st.Status), "1", 0, "L", true, 0, "")
+	pdf.CellFormat(95, 8, pdfdoc.InfoText(lang, "pdf.computed", st.ComputedAt.Format("02-01-2006 15:04")), "1", 1, "L", true, 0, "")
+	pdf.Ln(8)
+
+	cols := []pdfdoc.HeaderCol{
+		{W: 30, Key: "pdf.course"},
+		{W: 32, Key: "pdf.course_type"},
+		{W: 36, Key: "pdf.valuation"},
+		{W: 26, Key: "pdf.scripts"},
+		{W: 30, Key: "pdf.rate"},
+		{W: 36, Key: "pdf.amount"},
+	}
+	pdf.SetFillColor(230, 230, 230)
+	pdfdoc.TableHeader(pdf, lang, 11, 8, cols)
+
+	pdfdoc.LabelFont(pdf, lang, false, 11)
+	for _, l := range st.Lines {
+		rate := rateText(l)
+		if !l.Rated {
+			rate = i18n.T(lang, "pdf.no_rate")
+		}
+		pdf.CellFormat(cols[0].W, 8, l.CourseID, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(cols[1].W, 8, l.CourseType, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(cols[2].W, 8, l.ValuationType, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(cols[3].W, 8, strconv.Itoa(l.Scripts), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(cols[4].W, 8, rate, "1", 0, "R", false, 0, "")
+		pdf.CellFormat(cols[5].W, 8, money(l.Amount), "1", 1, "R", false, 0, "")
+	}
+	pdfdoc.LabelFont(pdf, lang, true, 11)
+	pdf.CellFormat(cols[0].W+cols[1].W+cols[2].W, 8, i18n.T(lang, "pdf.total"), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(cols[3].W, 8, strconv.Itoa(st.Scripts), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(cols[4].W, 8, "", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(cols[5].W, 8, money(st.Amount), "1", 1, "R", false, 0, "")
+	pdf.Ln(8)
+
+	pdfdoc.LabelFont(pdf, lang, false, 11)
+	if st.Unrated > 0 {
+		pdf.MultiCell(0, 6, i18n.T(lang, "pdf.remuneration.unrated", st.Unrated), "", "L", false)
+	}
+	if st.ApprovedAt != nil {
+		pdf.CellFormat(0, 6, i18n.T(lang, "pdf.remuneration.approved", st.ApprovedBy, st.ApprovedAt.Format("02-01-2006")), "", 1, "L", false, 0, "")
+	}
+	if st.PaidAt != nil {
+		pdf.CellFormat(0, 6, i18n.T(lang, "pdf.remuneration.paid", st.PaidAt.Format("02-01-2006"), st.PayoutReference), "", 1, "L", false, 0, "")
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,277 @@
+package revaluation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/auth"
+	"digital-eval-system/services/go-node/internal/i18n"
+	"digital-eval-system/services/go-node/internal/student"
+)
+
+// Handler exposes the revaluation workflow to students, the authority and evaluators.
+type Handler struct {
+	svc      *Service
+	students *student.Service
+}
+
+func NewHandler(svc *Service, studentSvc *student.Service) *Handler {
+	return &Handler{svc: svc, students: studentSvc}
+}
+
+// studentUSN resolves the authenticated student's USN from the token claims.
+func (h *Handler) studentUSN(w http.ResponseWriter, r *http.Request) string {
+	u, ok := auth.FromContext(r.Context())
+	if !ok || u == nil {
+		http.Error(w, "unauthenticated", http.StatusUnauthorized)
+		return ""
+	}
+	usn, err := h.students.USNForUser(r.Context(), u.UserID)
+	if errors.Is(err, student.ErrNoLinkedUSN) {
+		http.Error(w, i18n.T(i18n.ParseLang(r.URL.Query().Get("lang")), "api.no_linked_usn"), http.StatusForbidden)
+		return ""
+	}
+	if err != nil {
+		http.Error(w, "failed to resolve usn", http.StatusInternalServerError)
+		return ""
+	}
+	return usn
+}
+
+// POST /api/v1/student/revaluation
+// body: {"course_id": "...", "semester": "5", "academic_year": "2024-2025", "kind": "revaluation|retotal", "reason": "..."}
+func (h *Handler) File(w http.ResponseWriter, r *http.Request) {
+	usn := h.studentUSN(w, r)
+	if usn == "" {
+		return
+	}
+	var in FileRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	id, err := h.svc.File(r.Context(), usn, in)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"request_id": id, "status": StatusPending}, http.StatusCreated)
+}
+
+// GET /api/v1/student/revaluation
+func (h *Handler) ListMine(w http.ResponseWriter, r *http.Request) {
+	usn := h.studentUSN(w, r)
+	if usn == "" {
+		return
+	}
+	rows, err := h.svc.List(r.Context(), "", usn, "")
+	if err != nil {
+		http.Error(w, "failed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rows, http.StatusOK)
+}
+
+// GET /api/v1/student/revaluation/{id}
+func (h *Handler) MyHistory(w http.ResponseWriter, r *http.Request) {
+	usn := h.studentUSN(w, r)
+	if usn == "" {
+		return
+	}
+	id, ok := requestID(w, r)
+	if !ok {
+		return
+	}
+	hist, err := h.svc.History(r.Context(), id)
+	if err == nil && hist.Request.StudentUSN != usn {
+		err = ErrNotFound
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, hist, http.StatusOK)
+}
+
+// GET /api/v1/authority/revaluation?status=pending|approved|rejected|completed
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.svc.List(r.Context(), r.URL.Query().Get("status"), r.URL.Query().Get("usn"), "")
+	if err != nil {
+		http.Error(w, "failed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rows, http.StatusOK)
+}
+
+// GET /api/v1/authority/revaluation/{id}
+func (h *Handler) History(w http.ResponseWriter, r *http.Request) {
+	id, ok := requestID(w, r)
+	if !ok {
+		return
+	}
+	hist, err := h.svc.History(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, hist, http.StatusOK)
+}
+
+// POST /api/v1/authority/revaluation/{id}/approve
+// body: {"decided_by": "authority_1", "evaluator_id": "...", "note": "..."} (evaluator_id only for revaluation)
+func (h *Handler) Approve(w http.ResponseWriter, r *http.Request) {
+	id, ok := requestID(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		DecidedBy   string `json:"decided_by"`
+		EvaluatorID string `json:"evaluator_id"`
+		Note        string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.Approve(r.Context(), id, body.DecidedBy, body.EvaluatorID, body.Note); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, map[string]string{"status": StatusApproved}, http.StatusOK)
+}
+
+// POST /api/v1/authority/revaluation/{id}/reject
+// body: {"decided_by": "authority_1", "note": "..."}
+func (h *Handler) Reject(w http.ResponseWriter, r *http.Request) {
+	id, ok := requestID(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		DecidedBy string `json:"decided_by"`
+		Note      string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.Reject(r.Context(), id, body.DecidedBy, body.Note); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, map[string]string{"status": StatusRejected}, http.StatusOK)
+}
+
+// POST /api/v1/authority/revaluation/{id}/retotal
+// body: {"staff_id": "...", "marks_scored": [..]} (marks_scored optional)
+func (h *Handler) Retotal(w http.ResponseWriter, r *http.Request) {
+	id, ok := requestID(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		StaffID     string `json:"staff_id"`
+		MarksScored []int  `json:"marks_scored"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	hash, err := h.svc.Retotal(r.Context(), id, body.StaffID, body.MarksScored)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, map[string]string{"status": StatusCompleted, "block_hash": hash}, http.StatusOK)
+}
+
+// GET /api/v1/evaluator/revaluation?evaluator_id=...
+func (h *Handler) ListAssigned(w http.ResponseWriter, r *http.Request) {
+	eid := r.URL.Query().Get("evaluator_id")
+	if eid == "" {
+		http.Error(w, "missing evaluator_id", http.StatusBadRequest)
+		return
+	}
+	rows, err := h.svc.List(r.Context(), StatusApproved, "", eid)
+	if err != nil {
+		http.Error(w, "failed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rows, http.StatusOK)
+}
+
+// POST /api/v1/evaluator/revaluation/{id}/submit
+// body: {"evaluator_id": "...", "marks_scored": [..]}
+func (h *Handler) Submit(w http.ResponseWriter, r *http.Request) {
+	id, ok := requestID(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		EvaluatorID string `json:"evaluator_id"`
+		MarksScored []int  `json:"marks_scored"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	hash, err := h.svc.SubmitRevaluation(r.Context(), id, body.EvaluatorID, body.MarksScored)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, map[string]string{"status": StatusCompleted, "block_hash": hash}, http.StatusOK)
+}
+
+func requestID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrNoEvaluation):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrNotReleased), errors.Is(err, ErrNotYourRequest):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrAlreadyOpen), errors.Is(err, ErrWrongState), errors.Is(err, ErrSameEvaluator):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// RegisterRevaluationRoutes mounts the authority and evaluator endpoints on r
+// and the student endpoints behind student authentication.
+func RegisterRevaluationRoutes(r *mux.Router, svc *Service, studentSvc *student.Service, jwtMgr *auth.Manager) {
+	h := NewHandler(svc, studentSvc)
+
+	sr := r.PathPrefix("/student/revaluation").Subrouter()
+	sr.Use(auth.AuthMiddleware(jwtMgr), auth.RequireRole(auth.RoleStudent))
+	sr.HandleFunc("", h.File).Methods("POST")
+	sr.HandleFunc("", h.ListMine).Methods("GET")
+	sr.HandleFunc("/{id}", h.MyHistory).Methods("GET")
+
+	r.HandleFunc("/authority/revaluation", h.List).Methods("GET")
+	r.HandleFunc("/authority/revaluation/{id}", h.History).Methods("GET")
+	r.HandleFunc("/authority/revaluation/{id}/approve", h.Approve).Methods("POST")
+	r.HandleFunc("/authority/revaluation/{id}/reject", h.Reject).Methods("POST")
+	r.HandleFunc("/authority/revaluation/{id}/retotal", h.Retotal).Methods("POST")
+
+	r.HandleFunc("/evaluator/revaluation", h.ListAssigned).Methods("GET")
+	r.HandleFunc("/evaluator/revaluation/{id}/submit", h.Submit).Methods("POST")
+}
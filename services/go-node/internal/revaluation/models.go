@@ -0,0 +1,107 @@
+package revaluation
+
+import (
+	"errors"
+	"time"
+
+	"digital-eval-system/services/go-node/internal/db"
+)
+
+// Request kinds: a full revaluation by a different evaluator, or staff
+// re-totaling of the marks already awarded.
+const (
+	KindRevaluation = "revaluation"
+	KindRetotal     = "retotal"
+)
+
+// Request statuses.
+const (
+	StatusPending   = "pending"
+	StatusApproved  = "approved"
+	StatusRejected  = "rejected"
+	StatusCompleted = "completed"
+)
+
+var (
+	ErrNotReleased    = errors.New("results for this semester are not released")
+	ErrNoEvaluation   = errors.New("no evaluation found for this course")
+	ErrAlreadyOpen    = errors.New("a revaluation request for this course is already open")
+	ErrNotFound       = errors.New("revaluation request not found")
+	ErrWrongState     = errors.New("revaluation request is not in the required state")
+	ErrSameEvaluator  = errors.New("revaluation must be done by an evaluator who has not marked the script")
+	ErrNotYourRequest = errors.New("revaluation request is not assigned to this evaluator")
+)
+
+// FileRequest is the student's revaluation application.
+type FileRequest struct {
+	CourseID     string `json:"course_id"`
+	Semester     string `json:"semester"`
+	AcademicYear string `json:"academic_year"`
+	Kind         string `json:"kind"` // revaluation (default) | retotal
+	Reason       string `json:"reason,omitempty"`
+}
+
+// Request is a revaluation request as shown to students, authorities and evaluators.
+type Request struct {
+	ID            int64      `json:"id"`
+	EvaluationID  int64      `json:"evaluation_id"`
+	StudentUSN    string     `json:"student_usn"`
+	CourseID      string     `json:"course_id"`
+	Semester      string     `json:"semester"`
+	AcademicYear  string     `json:"academic_year"`
+	Kind          string     `json:"kind"`
+	Reason        string     `json:"reason,omitempty"`
+	Status        string     `json:"status"`
+	EvaluatorID   string     `json:"evaluator_id,omitempty"`
+	DecidedBy     string     `json:"decided_by,omitempty"`
+	DecidedAt     *time.Time `json:"decided_at,omitempty"`
+	DecisionNote  string     `json:"decision_note,omitempty"`
+	CompletedBy   string     `json:"completed_by,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	PreviousScore *int       `json:"previous_score,omitempty"`
+	RevisedScore  *int       `json:"revised_score,omitempty"`
+	BlockHash     string     `json:"block_hash,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// History is a request with every superseded version of its evaluation.
+type History struct {
+	Request   Request                    `json:"request"`
+	Current   map[string]interface{}     `json:"current"`
+	Revisions []db.EvaluationRevisionRow `json:"revisions"`
+}
+
+func fromRow(r db.RevaluationRequestRow) Request {
+	out := Request{
+		ID:           r.ID,
+		EvaluationID: r.EvaluationID,
+		StudentUSN:   r.StudentUSN,
+		CourseID:     r.CourseID,
+		Semester:     r.Semester,
+		AcademicYear: r.AcademicYear,
+		Kind:         r.Kind,
+		Reason:       r.Reason,
+		Status:       r.Status,
+		EvaluatorID:  r.EvaluatorID,
+		DecidedBy:    r.DecidedBy,
+		DecisionNote: r.DecisionNote,
+		CompletedBy:  r.CompletedBy,
+		BlockHash:    r.BlockHash,
+		CreatedAt:    r.CreatedAt,
+	}
+	if r.DecidedAt.Valid {
+		out.DecidedAt = &r.DecidedAt.Time
+	}
+	if r.CompletedAt.Valid {
+		out.CompletedAt = &r.CompletedAt.Time
+	}
+	if r.PreviousScore.Valid {
+		v := int(r.PreviousScore.Int64)
+		out.PreviousScore = &v
+	}
+	if r.RevisedScore.Valid {
+		v := int(r.RevisedScore.Int64)
+		out.RevisedScore = &v
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+package revaluation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// revisedReview returns the remarks and annotations the revised evaluation
+// keeps. They explain the marks of the version being superseded, which
+// evaluation_revisions keeps whole: a revaluation is a fresh judgement and
+// keeps none of them, a re-total keeps those on questions whose marks did not
+// change, and the annotations tied to no question.
+func revisedReview(kind string, remarks, annotations []interface{}, previous, revised []int) ([]interface{}, []interface{}) {
+	if kind != KindRetotal {
+		return nil, nil
+	}
+	unchanged := func(item interface{}) bool {
+		m, _ := item.(map[string]interface{})
+		q, _ := m["question"].(float64)
+		i := int(q) - 1
+		if i < 0 {
+			return true
+		}
+		return i < len(previous) && i < len(revised) && previous[i] == revised[i]
+	}
+	keep := func(items []interface{}) []interface{} {
+		var out []interface{}
+		for _, it := range items {
+			if unchanged(it) {
+				out = append(out, it)
+			}
+		}
+		return out
+	}
+	return keep(remarks), keep(annotations)
+}
+
+// reviewItems reads a remarks or annotations list out of the marks JSON.
+func reviewItems(v interface{}) []interface{} {
+	items, _ := v.([]interface{})
+	return items
+}
+
+// reviewJSON returns remarks and annotations as stored in Postgres and the
+// SHA-256 of both, as the evaluation block records it.
+func reviewJSON(remarks, annotations []interface{}) ([]byte, []byte, string) {
+	if remarks == nil {
+		remarks = []interface{}{}
+	}
+	if annotations == nil {
+		annotations = []interface{}{}
+	}
+	r, _ := json.Marshal(remarks)
+	a, _ := json.Marshal(annotations)
+	sum := sha256.New()
+	sum.Write(r)
+	sum.Write([]byte{'\n'})
+	sum.Write(a)
+	return r, a, hex.EncodeToString(sum.Sum(nil))
+}
@@ -0,0 +1,75 @@
+package revaluation
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// storedMarks is the marks JSON of an evaluation with remarks on questions 1
+// and 2, a tick on question 2 and a comment on no question.
+const storedMarks = `{
+	"marks_scored": [8, 6, 5],
+	"remarks": [{"question": 1, "label": "1a", "remark": "complete"}, {"question": 2, "label": "1b", "remark": "derivation missing"}],
+	"annotations": [{"page": 2, "x": 0.4, "y": 0.5, "kind": "tick", "question": 2}, {"page": 3, "x": 0.1, "y": 0.9, "kind": "comment", "text": "untidy"}],
+	"remarks_sha256": "old"
+}`
+
+func TestRevisedReview(t *testing.T) {
+	tests := []struct {
+		name            string
+		kind            string
+		revised         []int
+		wantRemarks     []float64 // questions of the kept remarks
+		wantAnnotations []float64
+	}{
+		{name: "revaluation keeps none", kind: KindRevaluation, revised: []int{8, 6, 5}},
+		{name: "retotal with the marks unchanged keeps all", kind: KindRetotal, revised: []int{8, 6, 5}, wantRemarks: []float64{1, 2}, wantAnnotations: []float64{2, 0}},
+		{name: "retotal drops those of a corrected question", kind: KindRetotal, revised: []int{8, 7, 5}, wantRemarks: []float64{1}, wantAnnotations: []float64{0}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var marks map[string]interface{}
+			if err := json.Unmarshal([]byte(storedMarks), &marks); err != nil {
+				t.Fatal(err)
+			}
+			remarks, annotations := revisedReview(tc.kind, reviewItems(marks["remarks"]), reviewItems(marks["annotations"]), []int{8, 6, 5}, tc.revised)
+			if got := questions(remarks); !equal(got, tc.wantRemarks) {
+				t.Errorf("remarks on questions %v, want %v", got, tc.wantRemarks)
+			}
+			if got := questions(annotations); !equal(got, tc.wantAnnotations) {
+				t.Errorf("annotations on questions %v, want %v", got, tc.wantAnnotations)
+			}
+		})
+	}
+}
+
+func TestReviewJSON(t *testing.T) {
+	r, a, digest := reviewJSON(nil, []interface{}{map[string]interface{}{"page": 1, "kind": "tick"}})
+	if string(r) != "[]" || string(a) != `[{"kind":"tick","page":1}]` {
+		t.Fatalf("stored remarks %s, annotations %s", r, a)
+	}
+	if _, _, again := reviewJSON([]interface{}{}, []interface{}{map[string]interface{}{"page": 1, "kind": "tick"}}); again != digest || len(digest) != 64 {
+		t.Errorf("digest %q, again %q", digest, again)
+	}
+}
+
+func questions(items []interface{}) []float64 {
+	var out []float64
+	for _, it := range items {
+		q, _ := it.(map[string]interface{})["question"].(float64)
+		out = append(out, q)
+	}
+	return out
+}
+
+func equal(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
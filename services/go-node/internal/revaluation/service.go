@@ -0,0 +1,337 @@
+package revaluation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"digital-eval-system/services/go-node/internal/analytics"
+	"digital-eval-system/services/go-node/internal/block"
+	"digital-eval-system/services/go-node/internal/chain"
+	"digital-eval-system/services/go-node/internal/course"
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/exampattern"
+	"digital-eval-system/services/go-node/internal/grading"
+	"digital-eval-system/services/go-node/internal/outbox"
+	"digital-eval-system/services/go-node/internal/student"
+)
+
+// Service runs the revaluation / re-totaling workflow: students file, the
+// authority approves, a different evaluator (or staff) revises the marks and
+// the outcome is appended on-chain superseding the earlier evaluation.
+type Service struct {
+	pg    *db.PostgresDB
+	chain interface {
+		AppendBlock(*block.Block) (string, error)
+	}
+	grading   *grading.Service
+	courses   *course.Service
+	analytics *analytics.Service
+	outbox    *outbox.Service
+}
+
+func NewService(pg *db.PostgresDB, chain *chain.Chain, gradingSvc *grading.Service, courseSvc *course.Service, analyticsSvc *analytics.Service, outboxSvc *outbox.Service) *Service {
+	return &Service{pg: pg, chain: chain, grading: gradingSvc, courses: courseSvc, analytics: analyticsSvc, outbox: outboxSvc}
+}
+
+// File records a student's request against a released evaluation.
+func (s *Service) File(ctx context.Context, usn string, in FileRequest) (int64, error) {
+	in.CourseID = strings.ToUpper(strings.TrimSpace(in.CourseID))
+	in.Kind = strings.ToLower(strings.TrimSpace(in.Kind))
+	if in.Kind == "" {
+		in.Kind = KindRevaluation
+	}
+	if in.Kind != KindRevaluation && in.Kind != KindRetotal {
+		return 0, fmt.Errorf("kind must be %q or %q", KindRevaluation, KindRetotal)
+	}
+	if in.CourseID == "" || in.Semester == "" || in.AcademicYear == "" {
+		return 0, fmt.Errorf("course_id, semester and academic_year are required")
+	}
+
+	rel, err := s.pg.GetRelease(ctx, in.Semester, in.AcademicYear)
+	if err != nil {
+		return 0, err
+	}
+	if rel == nil {
+		return 0, ErrNotReleased
+	}
+	cur, err := s.pg.GetStudentEvaluation(ctx, usn, in.CourseID, in.Semester, in.AcademicYear)
+	if err != nil {
+		return 0, err
+	}
+	if cur == nil {
+		return 0, ErrNoEvaluation
+	}
+	if s.scheme(ctx, cur).IsNonAttempt(cur.Result) {
+		return 0, fmt.Errorf("no marks to revalue for result %s", cur.Result)
+	}
+
+	id, err := s.pg.InsertRevaluationRequest(ctx, db.RevaluationRequestRow{
+		EvaluationID:  cur.ID,
+		StudentUSN:    usn,
+		CourseID:      cur.CourseID,
+		Semester:      cur.Semester,
+		AcademicYear:  cur.AcademicYear,
+		Kind:          in.Kind,
+		Reason:        strings.TrimSpace(in.Reason),
+		PreviousScore: sql.NullInt64{Int64: int64(student.RowMarksScored(cur.EvaluationRow)), Valid: true},
+	})
+	if err != nil && strings.Contains(err.Error(), "uq_revaluation_open") {
+		return 0, ErrAlreadyOpen
+	}
+	return id, err
+}
+
+// List returns requests filtered by status, student and evaluator.
+func (s *Service) List(ctx context.Context, status, usn, evaluatorID string) ([]Request, error) {
+	rows, err := s.pg.ListRevaluationRequests(ctx, status, usn, evaluatorID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Request, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, fromRow(r))
+	}
+	return out, nil
+}
+
+// History returns a request with the current evaluation and every version it superseded.
+func (s *Service) History(ctx context.Context, id int64) (*History, error) {
+	req, err := s.pg.GetRevaluationRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, ErrNotFound
+	}
+	h := &History{Request: fromRow(*req)}
+	cur, err := s.pg.GetEvaluation(ctx, req.EvaluationID)
+	if err != nil {
+		return nil, err
+	}
+	if cur != nil {
+		h.Current = map[string]interface{}{
+			"revision":    cur.Revision,
+			"evaluator":   cur.Evaluator,
+			"marks":       cur.Marks,
+			"total_marks": cur.TotalMarks,
+			"result":      cur.Result,
+			"block_hash":  cur.BlockHash,
+			"remarks":     cur.Remarks,
+			"annotations": cur.Annotations,
+		}
+		if cur.AnnotatedCID != "" {
+			h.Current["annotated_script"] = map[string]string{"cid": cur.AnnotatedCID, "sha256": cur.AnnotatedSHA256}
+		}
+	}
+	h.Revisions, err = s.pg.ListEvaluationRevisions(ctx, req.EvaluationID)
+	return h, err
+}
+
+// Approve accepts a pending request. Revaluations go to evaluatorID, who must
+// not have marked the script before; re-totaling is done by staff.
+func (s *Service) Approve(ctx context.Context, id int64, decidedBy, evaluatorID, note string) error {
+	req, cur, err := s.load(ctx, id, StatusPending)
+	if err != nil {
+		return err
+	}
+	evaluatorID = strings.TrimSpace(evaluatorID)
+	if req.Kind == KindRevaluation {
+		if evaluatorID == "" {
+			return fmt.Errorf("evaluator_id is required for a revaluation")
+		}
+		marked, err := s.pg.ListScriptEvaluators(ctx, cur.ScriptID)
+		if err != nil {
+			return err
+		}
+		for _, e := range marked {
+			if e == evaluatorID {
+				return ErrSameEvaluator
+			}
+		}
+	} else {
+		evaluatorID = ""
+	}
+	return s.decide(ctx, id, StatusApproved, evaluatorID, decidedBy, note)
+}
+
+// Reject declines a pending request.
+func (s *Service) Reject(ctx context.Context, id int64, decidedBy, note string) error {
+	if _, _, err := s.load(ctx, id, StatusPending); err != nil {
+		return err
+	}
+	return s.decide(ctx, id, StatusRejected, "", decidedBy, note)
+}
+
+// SubmitRevaluation records the assigned evaluator's fresh marks.
+func (s *Service) SubmitRevaluation(ctx context.Context, id int64, evaluatorID string, marksScored []int) (string, error) {
+	req, cur, err := s.load(ctx, id, StatusApproved)
+	if err != nil {
+		return "", err
+	}
+	if req.Kind != KindRevaluation {
+		return "", fmt.Errorf("request %d is a %s request", id, req.Kind)
+	}
+	if req.EvaluatorID != evaluatorID {
+		return "", ErrNotYourRequest
+	}
+	if len(marksScored) == 0 {
+		return "", fmt.Errorf("marks_scored is required")
+	}
+	return s.apply(ctx, req, cur, marksScored, evaluatorID)
+}
+
+// Retotal re-adds the awarded marks. Staff may pass corrected per-question
+// marks where a mark was not carried over correctly; nil re-totals the
+// stored ones.
+func (s *Service) Retotal(ctx context.Context, id int64, staffID string, marksScored []int) (string, error) {
+	req, cur, err := s.load(ctx, id, StatusApproved)
+	if err != nil {
+		return "", err
+	}
+	if req.Kind != KindRetotal {
+		return "", fmt.Errorf("request %d is a %s request", id, req.Kind)
+	}
+	if strings.TrimSpace(staffID) == "" {
+		return "", fmt.Errorf("staff_id is required")
+	}
+	return s.apply(ctx, req, cur, marksScored, staffID)
+}
+
+// apply writes the revised marks as a new chain transaction superseding the
+// current one, then updates Postgres through the outbox keeping the previous
+// version. Remarks and annotations that no longer describe the revised marks
+// stay with the previous version only.
+func (s *Service) apply(ctx context.Context, req *db.RevaluationRequestRow, cur *db.CurrentEvaluationRow, marksScored []int, by string) (string, error) {
+	var marks map[string]interface{}
+	if err := json.Unmarshal(cur.Marks, &marks); err != nil {
+		return "", fmt.Errorf("stored marks unreadable: %w", err)
+	}
+	var stored struct {
+		MarksScored []int `json:"marks_scored"`
+	}
+	_ = json.Unmarshal(cur.Marks, &stored)
+	if marksScored == nil {
+		marksScored = stored.MarksScored
+	}
+	if len(stored.MarksScored) > 0 && len(marksScored) != len(stored.MarksScored) {
+		return "", fmt.Errorf("expected %d question marks, got %d", len(stored.MarksScored), len(marksScored))
+	}
+	score, err := exampattern.FromMarks(cur.Marks).Check(marksScored)
+	if err != nil {
+		return "", err
+	}
+	if score > cur.TotalMarks {
+		return "", fmt.Errorf("score %d exceeds total marks %d", score, cur.TotalMarks)
+	}
+
+	previous := student.RowMarksScored(cur.EvaluationRow)
+	result := s.scheme(ctx, cur).Result(score, cur.TotalMarks)
+
+	marks["marks_scored"] = marksScored
+	marks["final_score"] = score
+	remarks, annotations := revisedReview(req.Kind, reviewItems(marks["remarks"]), reviewItems(marks["annotations"]), stored.MarksScored, marksScored)
+	delete(marks, "remarks")
+	delete(marks, "annotations")
+	delete(marks, "remarks_sha256")
+	var remarksJSON, annotationsJSON []byte
+	if len(remarks) > 0 || len(annotations) > 0 {
+		var digest string
+		remarksJSON, annotationsJSON, digest = reviewJSON(remarks, annotations)
+		marks["remarks"] = remarks
+		marks["annotations"] = annotations
+		marks["remarks_sha256"] = digest
+	}
+	marks["revaluation"] = map[string]interface{}{
+		"request_id":     req.ID,
+		"kind":           req.Kind,
+		"revised_by":     by,
+		"previous_score": previous,
+		"revised_score":  score,
+		"revision":       cur.Revision + 1,
+		"supersedes":     cur.BlockHash,
+	}
+	marksJSON, _ := json.Marshal(marks)
+
+	tx := block.Transaction{
+		ScriptID:     cur.ScriptID,
+		USN:          "", // hidden, as on the original evaluation
+		CourseID:     cur.CourseID,
+		Semester:     cur.Semester,
+		AcademicYear: cur.AcademicYear,
+		Meta: map[string]string{
+			"_evaluation": string(marksJSON),
+			"_supersedes": cur.BlockHash,
+		},
+		CreatedAt: time.Now().Unix(),
+		SignerID:  by,
+	}
+	blockHash, pending, err := s.outbox.Append(ctx, s.chain, block.NewBlock("", []block.Transaction{tx}, by), outbox.Effect{
+		Kind: outbox.KindRevision,
+		Payload: outbox.Revision{
+			RequestID:    req.ID,
+			Current:      *cur,
+			Marks:        marksJSON,
+			Result:       result,
+			CompletedBy:  by,
+			RevisedScore: score,
+			Remarks:      remarksJSON,
+			Annotations:  annotationsJSON,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("append block failed: %w", err)
+	}
+	if err := s.outbox.Deliver(ctx, pending...); err != nil {
+		logrus.Warnf("revision of request %d in block %s queued for retry: %v", req.ID, blockHash, err)
+	}
+	if s.analytics != nil {
+		s.analytics.Invalidate(cur.Semester, cur.AcademicYear)
+	}
+	logrus.Infof("revaluation %d (%s) of %s %s: %d -> %d (%s)", req.ID, req.Kind, cur.ScriptID, cur.CourseID, previous, score, result)
+	return blockHash, nil
+}
+
+// load fetches a request in the wanted status with its evaluation.
+func (s *Service) load(ctx context.Context, id int64, status string) (*db.RevaluationRequestRow, *db.CurrentEvaluationRow, error) {
+	req, err := s.pg.GetRevaluationRequest(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if req == nil {
+		return nil, nil, ErrNotFound
+	}
+	if req.Status != status {
+		return nil, nil, ErrWrongState
+	}
+	cur, err := s.pg.GetEvaluation(ctx, req.EvaluationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cur == nil {
+		return nil, nil, ErrNoEvaluation
+	}
+	return req, cur, nil
+}
+
+func (s *Service) decide(ctx context.Context, id int64, status, evaluatorID, decidedBy, note string) error {
+	err := s.pg.DecideRevaluationRequest(ctx, id, status, evaluatorID, decidedBy, strings.TrimSpace(note))
+	if err == sql.ErrNoRows {
+		return ErrWrongState
+	}
+	return err
+}
+
+// scheme resolves the grading scheme for the evaluation's course regulation and year.
+func (s *Service) scheme(ctx context.Context, cur *db.CurrentEvaluationRow) *grading.Scheme {
+	regulation := ""
+	if co, err := s.courses.Get(ctx, cur.CourseID); err == nil && co != nil {
+		regulation = co.Regulation
+	}
+	return s.grading.Resolve(ctx, regulation, cur.AcademicYear)
+}
@@ -20,6 +20,11 @@ type Storage interface {
 	SetHead(hash string) error
 	Head() (string, error)
 	Iterator(startHash string) Iterator
+	// outbox of Postgres writes pending for appended blocks
+	PutOutbox(id string, v []byte) error
+	GetOutbox(id string) ([]byte, error)
+	DeleteOutbox(id string) error
+	ForEachOutbox(fn func(id string, v []byte) error) error
 	Close() error
 }
 
@@ -54,6 +59,9 @@ func NewBoltDB(path string, timeout time.Duration) (Storage, error) {
 		if _, e := tx.CreateBucketIfNotExists([]byte(bucketChainMeta)); e != nil {
 			return e
 		}
+		if _, e := tx.CreateBucketIfNotExists([]byte(bucketOutbox)); e != nil {
+			return e
+		}
 		return nil
 	})
 	if err != nil {
@@ -159,6 +167,56 @@ func (b *boltDB) Head() (string, error) {
 	return h, nil
 }
 
+func (b *boltDB) PutOutbox(id string, v []byte) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		ob := tx.Bucket([]byte(bucketOutbox))
+		if ob == nil {
+			return errors.New("outbox bucket missing")
+		}
+		return ob.Put([]byte(id), v)
+	})
+}
+
+// GetOutbox returns an outbox item, nil if there is none with that id.
+func (b *boltDB) GetOutbox(id string) ([]byte, error) {
+	var out []byte
+	err := b.db.View(func(tx *bolt.Tx) error {
+		ob := tx.Bucket([]byte(bucketOutbox))
+		if ob == nil {
+			return errors.New("outbox bucket missing")
+		}
+		if v := ob.Get([]byte(id)); v != nil {
+			out = append([]byte(nil), v...)
+		}
+		return nil
+	})
+	return out, err
+}
+
+func (b *boltDB) DeleteOutbox(id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		ob := tx.Bucket([]byte(bucketOutbox))
+		if ob == nil {
+			return errors.New("outbox bucket missing")
+		}
+		return ob.Delete([]byte(id))
+	})
+}
+
+// ForEachOutbox iterates over the outbox in id order. The value is only
+// valid during the call.
+func (b *boltDB) ForEachOutbox(fn func(id string, v []byte) error) error {
+	return b.db.View(func(tx *bolt.Tx) error {
+		ob := tx.Bucket([]byte(bucketOutbox))
+		if ob == nil {
+			return nil
+		}
+		return ob.ForEach(func(k, v []byte) error {
+			return fn(string(k), v)
+		})
+	})
+}
+
 // iterator implementation
 type boltIterator struct {
 	db       *bolt.DB
@@ -4,4 +4,5 @@ const (
 	// bucket names
 	bucketBlocks    = "blocks"     // key: blockHash -> value: serialized block bytes
 	bucketChainMeta = "chain_meta" // key: "head" -> value: headHash
+	bucketOutbox    = "outbox"     // key: item id -> value: serialized outbox item
 )
@@ -0,0 +1,60 @@
+package student
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNoLinkedUSN is returned when a student login has no USN mapped to it.
+var ErrNoLinkedUSN = errors.New("no USN linked to this account")
+
+// Account links a student login to the USN whose results it may access.
+type Account struct {
+	UserID    string    `json:"user_id"`
+	USN       string    `json:"usn"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// USNForUser resolves the USN of a student login.
+func (s *Service) USNForUser(ctx context.Context, userID string) (string, error) {
+	usn, err := s.pg.GetUSNForUser(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if usn == "" {
+		return "", ErrNoLinkedUSN
+	}
+	return usn, nil
+}
+
+// ListAccounts returns all student login → USN links.
+func (s *Service) ListAccounts(ctx context.Context) ([]Account, error) {
+	rows, err := s.pg.ListStudentAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Account, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, Account{UserID: r.UserID, USN: r.USN, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt})
+	}
+	return out, nil
+}
+
+// LinkAccount maps a student login to a USN (USNs are stored upper-case).
+func (s *Service) LinkAccount(ctx context.Context, userID, usn string) error {
+	userID = strings.TrimSpace(userID)
+	usn = strings.ToUpper(strings.TrimSpace(usn))
+	if userID == "" || usn == "" {
+		return fmt.Errorf("user_id and usn are required")
+	}
+	return s.pg.LinkStudentAccount(ctx, userID, usn)
+}
+
+// UnlinkAccount removes the USN mapping of a student login.
+func (s *Service) UnlinkAccount(ctx context.Context, userID string) error {
+	return s.pg.UnlinkStudentAccount(ctx, userID)
+}
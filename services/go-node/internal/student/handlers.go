@@ -2,9 +2,13 @@ package student
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/gorilla/mux"
+
+	"digital-eval-system/services/go-node/internal/auth"
+	"digital-eval-system/services/go-node/internal/i18n"
 )
 
 type Handler struct {
@@ -15,33 +19,66 @@ func NewHandler(svc *Service) *Handler {
 	return &Handler{svc: svc}
 }
 
+// studentUSN resolves the USN of the authenticated student from the token
+// claims; the query string is never trusted. It writes the error response and
+// returns "" when the USN cannot be determined.
+func (h *Handler) studentUSN(w http.ResponseWriter, r *http.Request) string {
+	u, ok := auth.FromContext(r.Context())
+	if !ok || u == nil {
+		http.Error(w, "unauthenticated", http.StatusUnauthorized)
+		return ""
+	}
+	usn, err := h.svc.USNForUser(r.Context(), u.UserID)
+	if errors.Is(err, ErrNoLinkedUSN) {
+		http.Error(w, i18n.T(i18n.ParseLang(r.URL.Query().Get("lang")), "api.no_linked_usn"), http.StatusForbidden)
+		return ""
+	}
+	if err != nil {
+		http.Error(w, "failed to resolve usn", http.StatusInternalServerError)
+		return ""
+	}
+	return usn
+}
+
+// GET /api/v1/student/results?semester=...&academic_year=...&lang=en|kn|bi
 func (h *Handler) GetResults(w http.ResponseWriter, r *http.Request) {
-	usn := r.URL.Query().Get("usn")
-	sem := r.URL.Query().Get("semester")
-	academicYear := r.URL.Query().Get("academic_year")
+	usn := h.studentUSN(w, r)
 	if usn == "" {
-		http.Error(w, "missing usn", http.StatusBadRequest)
 		return
 	}
+	sem := r.URL.Query().Get("semester")
+	academicYear := r.URL.Query().Get("academic_year")
 	res, err := h.svc.FetchResultsWithGPA(r.Context(), usn, sem, academicYear)
 
 	if err != nil {
 		http.Error(w, "failed", http.StatusInternalServerError)
 		return
 	}
+	if res["status"] == StatusNotReleased {
+		res["message"] = i18n.T(i18n.ParseLang(r.URL.Query().Get("lang")), "api.results_not_released")
+	}
 	writeJSON(w, res, http.StatusOK)
 }
 
+// GET /api/v1/student/download?semester=...&academic_year=...&lang=en|kn|bi
 func (h *Handler) DownloadPDF(w http.ResponseWriter, r *http.Request) {
-	usn := r.URL.Query().Get("usn")
+	usn := h.studentUSN(w, r)
+	if usn == "" {
+		return
+	}
 	semester := r.URL.Query().Get("semester")
 	academicYear := r.URL.Query().Get("academic_year")
-	if usn == "" || semester == "" {
+	if semester == "" {
 		http.Error(w, "missing params", http.StatusBadRequest)
 		return
 	}
 
-	pdfBytes, err := h.svc.GenerateResultPDF(r.Context(), usn, semester, academicYear)
+	lang := i18n.ParseLang(r.URL.Query().Get("lang"))
+	pdfBytes, err := h.svc.GenerateResultPDF(r.Context(), usn, semester, academicYear, lang)
+	if errors.Is(err, ErrNotReleased) {
+		http.Error(w, i18n.T(lang, "api.results_not_released"), http.StatusForbidden)
+		return
+	}
 	if err != nil {
 		http.Error(w, "failed to generate pdf: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -53,14 +90,97 @@ func (h *Handler) DownloadPDF(w http.ResponseWriter, r *http.Request) {
 	w.Write(pdfBytes)
 }
 
+// GET /api/v1/student/transcript
+func (h *Handler) GetTranscript(w http.ResponseWriter, r *http.Request) {
+	usn := h.studentUSN(w, r)
+	if usn == "" {
+		return
+	}
+	t, err := h.svc.BuildTranscript(r.Context(), usn)
+	if err != nil {
+		http.Error(w, "failed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, t, http.StatusOK)
+}
+
+// GET /api/v1/student/transcript/download?lang=en|kn|bi
+func (h *Handler) DownloadTranscriptPDF(w http.ResponseWriter, r *http.Request) {
+	usn := h.studentUSN(w, r)
+	if usn == "" {
+		return
+	}
+
+	pdfBytes, err := h.svc.GenerateTranscriptPDF(r.Context(), usn, i18n.ParseLang(r.URL.Query().Get("lang")))
+	if err != nil {
+		http.Error(w, "failed to generate pdf: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"transcript_"+usn+".pdf\"")
+	w.WriteHeader(http.StatusOK)
+	w.Write(pdfBytes)
+}
+
+// GET /api/v1/admin/students/accounts
+func (h *Handler) ListAccounts(w http.ResponseWriter, r *http.Request) {
+	accounts, err := h.svc.ListAccounts(r.Context())
+	if err != nil {
+		http.Error(w, "failed to load accounts", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, accounts, http.StatusOK)
+}
+
+// POST /api/v1/admin/students/accounts {"user_id": "...", "usn": "..."}
+func (h *Handler) LinkAccount(w http.ResponseWriter, r *http.Request) {
+	var in Account
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.LinkAccount(r.Context(), in.UserID, in.USN); err != nil {
+		http.Error(w, "link failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "linked"}, http.StatusOK)
+}
+
+// DELETE /api/v1/admin/students/accounts/{user_id}
+func (h *Handler) UnlinkAccount(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.UnlinkAccount(r.Context(), mux.Vars(r)["user_id"]); err != nil {
+		http.Error(w, "unlink failed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "unlinked"}, http.StatusOK)
+}
+
 func writeJSON(w http.ResponseWriter, v interface{}, code int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
 	_ = json.NewEncoder(w).Encode(v)
 }
 
-func RegisterStudentRoutes(r *mux.Router, svc *Service) {
+// RegisterStudentRoutes mounts the student endpoints behind JWT auth and the
+// student role; every handler serves only the caller's own USN.
+func RegisterStudentRoutes(r *mux.Router, svc *Service, jwtMgr *auth.Manager) {
+	h := NewHandler(svc)
+	sr := r.PathPrefix("/student").Subrouter()
+	sr.Use(auth.AuthMiddleware(jwtMgr), auth.RequireRole(auth.RoleStudent))
+	sr.HandleFunc("/results", h.GetResults).Methods("GET")
+	sr.HandleFunc("/download", h.DownloadPDF).Methods("GET")
+	sr.HandleFunc("/transcript", h.GetTranscript).Methods("GET")
+	sr.HandleFunc("/transcript/download", h.DownloadTranscriptPDF).Methods("GET")
+}
+
+// RegisterStudentAccountRoutes adds the admin endpoints that map student logins
+// to USNs, behind JWT auth and the admin role.
+func RegisterStudentAccountRoutes(r *mux.Router, svc *Service, jwtMgr *auth.Manager) {
 	h := NewHandler(svc)
-	r.HandleFunc("/student/results", h.GetResults).Methods("GET")
-	r.HandleFunc("/student/download", h.DownloadPDF).Methods("GET")
+	ar := r.PathPrefix("/admin/students/accounts").Subrouter()
+	ar.Use(auth.AuthMiddleware(jwtMgr), auth.RequireRole(auth.RoleAdmin))
+	ar.HandleFunc("", h.ListAccounts).Methods("GET")
+	ar.HandleFunc("", h.LinkAccount).Methods("POST")
+	ar.HandleFunc("/{user_id}", h.UnlinkAccount).Methods("DELETE")
 }
@@ -2,26 +2,46 @@ package student
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math"
 
+	"github.com/sirupsen/logrus"
+
+	"digital-eval-system/services/go-node/internal/course"
 	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/grading"
+	"digital-eval-system/services/go-node/internal/i18n"
 	"digital-eval-system/services/go-node/internal/rootdir"
 )
 
 // Service provides student result access
 type Service struct {
-	pg *db.PostgresDB
+	pg      *db.PostgresDB
+	grading *grading.Service
+	courses *course.Service
 }
 
-func NewService(pg *db.PostgresDB) *Service {
-	return &Service{pg: pg}
+func NewService(pg *db.PostgresDB, gradingSvc *grading.Service, courseSvc *course.Service) *Service {
+	return &Service{pg: pg, grading: gradingSvc, courses: courseSvc}
 }
 
+// Result release states reported by FetchResultsWithGPA.
+const (
+	StatusReleased    = "released"
+	StatusNotReleased = "not_released"
+)
+
+// ErrNotReleased is returned when a student asks for results of a semester
+// the authority has not published yet.
+var ErrNotReleased = errors.New("results not yet released")
+
+// FetchResults returns the student's evaluations for released semesters only;
+// provisional marks of unreleased semesters are never included.
 func (s *Service) FetchResults(ctx context.Context, usn, semester string, academicYear string) ([]db.EvaluationRow, error) {
 	// Your DB helper already filters by USN, so we fetch all rows
-	rows, err := s.pg.FetchResultsByUSN(ctx, usn, academicYear)
+	rows, err := s.pg.FetchReleasedResultsByUSNAndYear(ctx, usn, academicYear)
 	if err != nil {
 		return nil, err
 	}
@@ -38,27 +58,103 @@ func (s *Service) FetchResults(ctx context.Context, usn, semester string, academ
 	return filtered, nil
 }
 
+// applyCatalog loads the catalog entries for the courses in rows and replaces
+// each row's recorded credits with the catalog value. Rows for courses missing
+// from the catalog keep what was recorded at submission time.
+func (s *Service) applyCatalog(ctx context.Context, rows []db.EvaluationRow) course.Catalog {
+	codes := make([]string, 0, len(rows))
+	for _, r := range rows {
+		codes = append(codes, r.CourseID)
+	}
+	cat, err := s.courses.Catalog(ctx, codes)
+	if err != nil {
+		logrus.Warnf("course catalog lookup failed: %v", err)
+		return course.Catalog{}
+	}
+	for i := range rows {
+		if c, ok := cat.Get(rows[i].CourseID); ok {
+			rows[i].CourseCredits = sql.NullInt32{Int32: int32(c.Credits), Valid: true}
+		}
+	}
+	return cat
+}
+
+// catalogRegulation returns the regulation of the catalogued courses in rows.
+func catalogRegulation(rows []db.EvaluationRow, cat course.Catalog) string {
+	codes := make([]string, 0, len(rows))
+	for _, r := range rows {
+		codes = append(codes, r.CourseID)
+	}
+	return cat.Regulation(codes...)
+}
+
 // ---------- PHASE C: FetchResultsWithGPA ----------
+// Before the semester is released the map carries status "not_released" and no rows.
 func (s *Service) FetchResultsWithGPA(ctx context.Context, usn, semester string, academicYear string) (map[string]interface{}, error) {
 
+	var releasedAt interface{}
+	if semester != "" {
+		rel, err := s.pg.GetRelease(ctx, semester, academicYear)
+		if err != nil {
+			return nil, err
+		}
+		if rel == nil {
+			return notReleased(usn, semester, academicYear), nil
+		}
+		releasedAt = rel.ReleasedAt
+	}
+
 	rows, err := s.FetchResults(ctx, usn, semester, academicYear)
 	if err != nil {
 		return nil, err
 	}
+	if semester == "" && len(rows) == 0 {
+		return notReleased(usn, semester, academicYear), nil
+	}
+
+	cat := s.applyCatalog(ctx, rows)
+	scheme := s.grading.Resolve(ctx, catalogRegulation(rows, cat), academicYear)
+	sgpa := CalculateSGPA(rows, scheme)
 
-	sgpa := CalculateSGPA(rows)
+	grades := map[string]string{}
+	for _, r := range rows {
+		grades[r.CourseID] = RowGrade(r, scheme).Letter
+	}
 
 	return map[string]interface{}{
 		"usn":           usn,
 		"semester":      semester,
 		"academic_year": academicYear,
 		"rows":          rows,
+		"grades":        grades,
 		"sgpa":          sgpa,
+		"regulation":    scheme.Regulation,
+		"status":        StatusReleased,
+		"released_at":   releasedAt,
 	}, nil
 }
 
+func notReleased(usn, semester, academicYear string) map[string]interface{} {
+	return map[string]interface{}{
+		"usn":           usn,
+		"semester":      semester,
+		"academic_year": academicYear,
+		"rows":          []db.EvaluationRow{},
+		"status":        StatusNotReleased,
+		"message":       i18n.T(i18n.English, "api.results_not_released"),
+	}
+}
+
 // GenerateResultPDF fetches results and produces the PDF bytes using pdf_generator.go
-func (s *Service) GenerateResultPDF(ctx context.Context, usn, semester string, academicYear string) ([]byte, error) {
+func (s *Service) GenerateResultPDF(ctx context.Context, usn, semester string, academicYear string, lang i18n.Lang) ([]byte, error) {
+	rel, err := s.pg.GetRelease(ctx, semester, academicYear)
+	if err != nil {
+		return nil, fmt.Errorf("fetch release: %w", err)
+	}
+	if rel == nil {
+		return nil, ErrNotReleased
+	}
+
 	// Fix: Use FetchResults directly to get []db.EvaluationRow, not the map from FetchResultsWithGPA
 	rows, err := s.FetchResults(ctx, usn, semester, academicYear)
 	if err != nil {
@@ -68,9 +164,13 @@ func (s *Service) GenerateResultPDF(ctx context.Context, usn, semester string, a
 		return nil, fmt.Errorf("no results found for %s semester %s", usn, semester)
 	}
 
+	cat := s.applyCatalog(ctx, rows)
 	pdfBytes, err := GenerateResultPDF(ctx, usn, semester, academicYear, rows, PDFOptions{
 		LogoPath: rootdir.Resolve("services/go-node/internal/student/assets/biet_logo.jpg"),
 		FontDir:  rootdir.Resolve("services/go-node/internal/student/assets/fonts"),
+		Scheme:   s.grading.Resolve(ctx, catalogRegulation(rows, cat), academicYear),
+		Courses:  cat,
+		Lang:     lang,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("generate PDF: %w", err)
@@ -79,75 +179,64 @@ func (s *Service) GenerateResultPDF(ctx context.Context, usn, semester string, a
 	return pdfBytes, nil
 }
 
-func CalculateSGPA(rows []db.EvaluationRow) float64 {
+// CalculateSGPA returns the credit-weighted grade point average of rows under
+// scheme (the built-in default when nil). Absent and withheld courses count
+// towards credits with zero grade points.
+func CalculateSGPA(rows []db.EvaluationRow, scheme *grading.Scheme) float64 {
 	if len(rows) == 0 {
 		return 0.0
 	}
+	if scheme == nil {
+		scheme = grading.Default()
+	}
 	var totalWeightedPoints float64
 	var totalCredits float64
 
 	for _, r := range rows {
-		// r.TotalMarks is int, r.Result string. We need marks scored from r.Marks JSON.
-		// Expect r.Marks JSON to contain "marks_scored" array or "marks_scored_total" numeric.
-		// We'll try to extract marks_scored array sum; fallback to 0.
-		var marksMap map[string]interface{}
-		_ = json.Unmarshal(r.Marks, &marksMap)
-
-		// sum marks_scored
-		// sum marks_scored using module logic
-		marksScored := calculateModuleScore(marksMap["marks_scored"])
-
-		// If total marks available, compute percentage for grade points.
-		credit := float64(0)
-		if r.CourseCredits.Valid {
-			credit = float64(r.CourseCredits.Int32) // adjust type if CourseCredits is different
-		} else {
-			// fallback: try CourseCredits column as int field if present
-			if r.CourseCredits.Int32 == 0 {
-				credit = 0
-			}
-		}
-
+		credit := rowCredits(r)
 		if credit <= 0 {
 			continue
 		}
+		totalCredits += float64(credit)
+		totalWeightedPoints += RowGrade(r, scheme).GradePoint * float64(credit)
+	}
 
-		totalCredits += credit
+	if totalCredits == 0 {
+		return 0.0
+	}
+	return scheme.Round(totalWeightedPoints / totalCredits)
+}
 
-		// compute percentage and map to grade point (simple 10-point scale).
-		var perc float64
-		if r.TotalMarks > 0 {
-			perc = (float64(marksScored) / float64(r.TotalMarks)) * 100.0
-		} else {
-			perc = 0
-		}
+// RowMarksScored returns the marks scored on an evaluation: the aggregated
+// final_score of a multi-valuation result when present, otherwise the
+// stored marks_scored array totalled under the evaluation's exam pattern.
+func RowMarksScored(r db.EvaluationRow) int {
+	var marksMap map[string]interface{}
+	_ = json.Unmarshal(r.Marks, &marksMap)
+	return marksScored(marksMap)
+}
 
-		// Convert percentage to grade point (0..10). Adjust thresholds as required.
-		var gp float64
-		switch {
-		case perc >= 90:
-			gp = 10.0
-		case perc >= 80:
-			gp = 9.0
-		case perc >= 70:
-			gp = 8.0
-		case perc >= 60:
-			gp = 7.0
-		case perc >= 50:
-			gp = 6.0
-		case perc >= 40:
-			gp = 5.0
-		default:
-			gp = 0.0
-		}
+func marksScored(marksMap map[string]interface{}) int {
+	if v, ok := marksMap["final_score"].(float64); ok {
+		return int(v)
+	}
+	return calculateModuleScore(marksMap)
+}
 
-		totalWeightedPoints += gp * credit
+// rowCredits returns the course credits on the evaluation row (catalog value
+// once applyCatalog has run), 0 if unset.
+func rowCredits(r db.EvaluationRow) int {
+	if !r.CourseCredits.Valid {
+		return 0
 	}
+	return int(r.CourseCredits.Int32)
+}
 
-	if totalCredits == 0 {
-		return 0.0
+// RowGrade maps an evaluation to its grade under scheme. Absent and withheld
+// results keep their code as the letter and earn no grade points.
+func RowGrade(r db.EvaluationRow, scheme *grading.Scheme) grading.Grade {
+	if scheme.IsNonAttempt(r.Result) {
+		return grading.Grade{Letter: r.Result}
 	}
-	sgpa := totalWeightedPoints / totalCredits
-	// round to 2 decimals
-	return math.Round(sgpa*100) / 100
+	return scheme.GradeFor(grading.Percentage(RowMarksScored(r), r.TotalMarks))
 }
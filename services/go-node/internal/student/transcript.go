@@ -0,0 +1,218 @@
+package student
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"digital-eval-system/services/go-node/internal/course"
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/grading"
+	"digital-eval-system/services/go-node/internal/i18n"
+	"digital-eval-system/services/go-node/internal/rootdir"
+)
+
+// TranscriptCourse is one course attempt on a consolidated transcript.
+type TranscriptCourse struct {
+	CourseID     string  `json:"course_id"`
+	CourseName   string  `json:"course_name"`
+	Semester     string  `json:"semester"`
+	AcademicYear string  `json:"academic_year"`
+	Credits      int     `json:"credits"`
+	MarksScored  int     `json:"marks_scored"`
+	TotalMarks   int     `json:"total_marks"`
+	Grade        string  `json:"grade"`
+	GradePoint   float64 `json:"grade_point"`
+	Result       string  `json:"result"`
+}
+
+// TranscriptSemester groups the released courses of one semester sitting.
+type TranscriptSemester struct {
+	Semester          string             `json:"semester"`
+	AcademicYear      string             `json:"academic_year"`
+	Courses           []TranscriptCourse `json:"courses"`
+	SGPA              float64            `json:"sgpa"`
+	CreditsRegistered int                `json:"credits_registered"`
+	CreditsEarned     int                `json:"credits_earned"`
+}
+
+// Transcript is the consolidated record of every released semester for a USN.
+type Transcript struct {
+	USN               string               `json:"usn"`
+	Semesters         []TranscriptSemester `json:"semesters"`
+	CGPA              float64              `json:"cgpa"`
+	CreditsRegistered int                  `json:"credits_registered"`
+	CreditsEarned     int                  `json:"credits_earned"`
+	Backlogs          []TranscriptCourse   `json:"backlogs"`
+	GeneratedAt       time.Time            `json:"generated_at"`
+}
+
+// BuildTranscript gathers all released semesters for usn and computes the
+// credit-weighted CGPA, credits earned and outstanding backlog courses.
+func (s *Service) BuildTranscript(ctx context.Context, usn string) (*Transcript, error) {
+	rows, err := s.pg.FetchReleasedResultsByUSN(ctx, usn)
+	if err != nil {
+		return nil, err
+	}
+	cat := s.applyCatalog(ctx, rows)
+	schemes := map[[2]string]*grading.Scheme{}
+	resolve := func(regulation, academicYear string) *grading.Scheme {
+		key := [2]string{regulation, academicYear}
+		if sc, ok := schemes[key]; ok {
+			return sc
+		}
+		sc := s.grading.Resolve(ctx, regulation, academicYear)
+		schemes[key] = sc
+		return sc
+	}
+	return buildTranscript(usn, rows, cat, resolve), nil
+}
+
+// GenerateTranscriptPDF builds the consolidated transcript PDF for usn.
+func (s *Service) GenerateTranscriptPDF(ctx context.Context, usn string, lang i18n.Lang) ([]byte, error) {
+	t, err := s.BuildTranscript(ctx, usn)
+	if err != nil {
+		return nil, fmt.Errorf("build transcript: %w", err)
+	}
+	if len(t.Semesters) == 0 {
+		return nil, fmt.Errorf("no released results found for %s", usn)
+	}
+
+	pdfBytes, err := GenerateTranscriptPDF(t, PDFOptions{
+		LogoPath: rootdir.Resolve("services/go-node/internal/student/assets/biet_logo.jpg"),
+		FontDir:  rootdir.Resolve("services/go-node/internal/student/assets/fonts"),
+		Lang:     lang,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("generate PDF: %w", err)
+	}
+	return pdfBytes, nil
+}
+
+// buildTranscript orders rows by academic year and semester number. A course
+// attempted more than once counts only its latest attempt towards the CGPA,
+// credits earned and backlog list; earlier attempts remain on their semester.
+// Each row is graded under the scheme of its course's regulation and academic
+// year; SGPA and CGPA are rounded with the scheme of the last course graded.
+func buildTranscript(usn string, rows []db.EvaluationRow, courses course.Catalog, resolve func(regulation, academicYear string) *grading.Scheme) *Transcript {
+	t := &Transcript{
+		USN:         usn,
+		Semesters:   []TranscriptSemester{},
+		Backlogs:    []TranscriptCourse{},
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.AcademicYear != b.AcademicYear {
+			return a.AcademicYear < b.AcademicYear
+		}
+		if na, nb := semesterNumber(a.Semester), semesterNumber(b.Semester); na != nb {
+			return na < nb
+		}
+		if a.Semester != b.Semester {
+			return a.Semester < b.Semester
+		}
+		return a.CourseID < b.CourseID
+	})
+
+	latest := map[string]TranscriptCourse{}
+	var order []string
+	scheme := grading.Default()
+	var semSchemes []*grading.Scheme
+
+	for _, r := range rows {
+		var marksMap map[string]interface{}
+		_ = json.Unmarshal(r.Marks, &marksMap)
+
+		scheme = resolve(courses.Regulation(r.CourseID), r.AcademicYear)
+		grade := RowGrade(r, scheme)
+		scored := marksScored(marksMap)
+		c := TranscriptCourse{
+			CourseID:     r.CourseID,
+			CourseName:   rowCourseName(r, marksMap, courses),
+			Semester:     r.Semester,
+			AcademicYear: r.AcademicYear,
+			Credits:      rowCredits(r),
+			MarksScored:  scored,
+			TotalMarks:   r.TotalMarks,
+			Grade:        grade.Letter,
+			GradePoint:   grade.GradePoint,
+			Result:       r.Result,
+		}
+
+		n := len(t.Semesters)
+		if n == 0 || t.Semesters[n-1].Semester != r.Semester || t.Semesters[n-1].AcademicYear != r.AcademicYear {
+			t.Semesters = append(t.Semesters, TranscriptSemester{Semester: r.Semester, AcademicYear: r.AcademicYear})
+			semSchemes = append(semSchemes, scheme)
+			n++
+		}
+		semSchemes[n-1] = scheme
+		sem := &t.Semesters[n-1]
+		sem.Courses = append(sem.Courses, c)
+		sem.CreditsRegistered += c.Credits
+		if c.Result == grading.ResultPass {
+			sem.CreditsEarned += c.Credits
+		}
+
+		if _, seen := latest[c.CourseID]; !seen {
+			order = append(order, c.CourseID)
+		}
+		latest[c.CourseID] = c
+	}
+
+	for i := range t.Semesters {
+		t.Semesters[i].SGPA = weightedGradePoint(t.Semesters[i].Courses, semSchemes[i])
+	}
+
+	var counted []TranscriptCourse
+	for _, id := range order {
+		c := latest[id]
+		counted = append(counted, c)
+		t.CreditsRegistered += c.Credits
+		if c.Result == grading.ResultPass {
+			t.CreditsEarned += c.Credits
+		} else {
+			t.Backlogs = append(t.Backlogs, c)
+		}
+	}
+	t.CGPA = weightedGradePoint(counted, scheme)
+
+	return t
+}
+
+// weightedGradePoint is the credit-weighted mean grade point of courses,
+// rounded by scheme. Courses without credits are ignored.
+func weightedGradePoint(courses []TranscriptCourse, scheme *grading.Scheme) float64 {
+	var points, credits float64
+	for _, c := range courses {
+		if c.Credits <= 0 {
+			continue
+		}
+		points += c.GradePoint * float64(c.Credits)
+		credits += float64(c.Credits)
+	}
+	if credits == 0 {
+		return 0.0
+	}
+	return scheme.Round(points / credits)
+}
+
+// semesterNumber reads the first number in a semester label ("5", "Sem 10"),
+// so semester 10 sorts after semester 9. Labels without digits sort first.
+func semesterNumber(semester string) int {
+	i := strings.IndexAny(semester, "0123456789")
+	if i < 0 {
+		return 0
+	}
+	j := i
+	for j < len(semester) && semester[j] >= '0' && semester[j] <= '9' {
+		j++
+	}
+	n, _ := strconv.Atoi(semester[i:j])
+	return n
+}
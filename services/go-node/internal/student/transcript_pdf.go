@@ -0,0 +1,94 @@
+package student
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"digital-eval-system/services/go-node/internal/i18n"
+	"digital-eval-system/services/go-node/internal/pdfdoc"
+)
+
+// GenerateTranscriptPDF renders a consolidated transcript: one table per
+// released semester followed by the CGPA, credit and backlog summary.
+func GenerateTranscriptPDF(t *Transcript, opts PDFOptions) ([]byte, error) {
+	if t == nil || len(t.Semesters) == 0 {
+		return nil, fmt.Errorf("empty transcript")
+	}
+
+	pdf := pdfdoc.NewInstitutePDF(opts.doc())
+
+	// ---------------------------------------------------------------------
+	// Title & student info box
+	// ---------------------------------------------------------------------
+	pdfdoc.LabelFont(pdf, opts.Lang, true, 12)
+	pdf.CellFormat(0, 9, i18n.T(opts.Lang, "pdf.transcript.title"), "", 1, "C", false, 0, "")
+	pdf.Ln(2)
+
+	pdfdoc.LabelFont(pdf, opts.Lang, false, 11)
+	pdf.SetFillColor(248, 248, 248)
+	pdf.CellFormat(95, 8, pdfdoc.InfoText(opts.Lang, "pdf.usn", t.USN), "1", 0, "L", true, 0, "")
+	pdf.CellFormat(95, 8, pdfdoc.InfoText(opts.Lang, "pdf.issued", t.GeneratedAt.Format("02-01-2006")), "1", 1, "L", true, 0, "")
+	pdf.CellFormat(190, 8, pdfdoc.InfoText(opts.Lang, "pdf.institute", i18n.T(opts.Lang, "institute.short")), "1", 1, "L", true, 0, "")
+
+	pdf.Ln(8)
+
+	// ---------------------------------------------------------------------
+	// One table per semester
+	// ---------------------------------------------------------------------
+	for _, sem := range t.Semesters {
+		pdfdoc.LabelFont(pdf, opts.Lang, true, 11)
+		pdf.CellFormat(0, 8, fmt.Sprintf("%s %s  (%s)", i18n.T(opts.Lang, "pdf.semester"), sem.Semester, sem.AcademicYear), "", 1, "L", false, 0, "")
+
+		pdf.SetFillColor(230, 230, 230)
+		pdfdoc.TableHeader(pdf, opts.Lang, 11, 7, []pdfdoc.HeaderCol{
+			{W: 26, Key: "pdf.course_id"},
+			{W: 67, Key: "pdf.course_name"},
+			{W: 17, Key: "pdf.credits"},
+			{W: 24, Key: "pdf.marks"},
+			{W: 16, Key: "pdf.grade"},
+			{W: 16, Key: "pdf.grade_point"},
+			{W: 24, Key: "pdf.result"},
+		})
+
+		pdf.SetFont("Rob", "", 10)
+		for _, c := range sem.Courses {
+			pdf.CellFormat(26, 7, c.CourseID, "1", 0, "C", false, 0, "")
+			pdf.CellFormat(67, 7, c.CourseName, "1", 0, "L", false, 0, "")
+			pdf.CellFormat(17, 7, fmt.Sprintf("%d", c.Credits), "1", 0, "C", false, 0, "")
+			pdf.CellFormat(24, 7, fmt.Sprintf("%d/%d", c.MarksScored, c.TotalMarks), "1", 0, "C", false, 0, "")
+			pdf.CellFormat(16, 7, c.Grade, "1", 0, "C", false, 0, "")
+			pdf.CellFormat(16, 7, fmt.Sprintf("%g", c.GradePoint), "1", 0, "C", false, 0, "")
+			pdf.CellFormat(24, 7, c.Result, "1", 1, "C", false, 0, "")
+		}
+
+		pdfdoc.LabelFont(pdf, opts.Lang, false, 10)
+		pdf.CellFormat(0, 7, fmt.Sprintf("%s: %.2f    %s: %d/%d",
+			i18n.T(opts.Lang, "pdf.sgpa"), sem.SGPA,
+			i18n.T(opts.Lang, "pdf.credits_earned"), sem.CreditsEarned, sem.CreditsRegistered), "", 1, "R", false, 0, "")
+		pdf.Ln(4)
+	}
+
+	// ---------------------------------------------------------------------
+	// Summary block
+	// ---------------------------------------------------------------------
+	pdf.Ln(4)
+	renderLabelLine(pdf, opts.Lang, "pdf.cgpa", fmt.Sprintf("%.2f", t.CGPA))
+	renderLabelLine(pdf, opts.Lang, "pdf.credits_earned", fmt.Sprintf("%d/%d", t.CreditsEarned, t.CreditsRegistered))
+
+	backlogs := i18n.T(opts.Lang, "pdf.none")
+	if len(t.Backlogs) > 0 {
+		ids := make([]string, 0, len(t.Backlogs))
+		for _, c := range t.Backlogs {
+			ids = append(ids, c.CourseID)
+		}
+		backlogs = strings.Join(ids, ", ")
+	}
+	renderLabelLine(pdf, opts.Lang, "pdf.backlogs", backlogs)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
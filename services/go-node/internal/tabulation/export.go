@@ -0,0 +1,58 @@
+package tabulation
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+)
+
+// table flattens the register into a header and one line per student:
+// USN, then marks/grade/result for each course column, then SGPA, credits
+// earned and the overall result.
+func (reg *Register) table() ([]string, [][]string) {
+	header := []string{"USN"}
+	for _, c := range reg.Columns {
+		header = append(header,
+			c.CourseCode+" Marks",
+			c.CourseCode+" Grade",
+			c.CourseCode+" Result")
+	}
+	header = append(header, "SGPA", "Credits Earned", "Result")
+
+	lines := make([][]string, 0, len(reg.Rows))
+	for _, r := range reg.Rows {
+		line := []string{r.USN}
+		for _, c := range reg.Columns {
+			cell, ok := r.Courses[c.CourseCode]
+			if !ok {
+				line = append(line, "", "", "")
+				continue
+			}
+			line = append(line, strconv.Itoa(cell.Marks), cell.Grade, cell.Result)
+		}
+		line = append(line, fmt.Sprintf("%.2f", r.SGPA), strconv.Itoa(r.CreditsEarned), r.Result)
+		lines = append(lines, line)
+	}
+	return header, lines
+}
+
+// CSV renders the register as CSV.
+func (reg *Register) CSV() ([]byte, error) {
+	header, lines := reg.table()
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	if err := w.WriteAll(lines); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// XLSX renders the register as a single-sheet workbook.
+func (reg *Register) XLSX() ([]byte, error) {
+	header, lines := reg.table()
+	return writeXLSX("Sem "+reg.Semester, append([][]string{header}, lines...))
+}
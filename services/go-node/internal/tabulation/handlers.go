@@ -0,0 +1,90 @@
+package tabulation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler exposes tabulation register exports.
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GET /api/v1/authority/results/register?semester=...&academic_year=...&department=...&course_id=A,B&format=csv|xlsx|json
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	semester := q.Get("semester")
+	academicYear := q.Get("academic_year")
+	if semester == "" || academicYear == "" {
+		http.Error(w, "semester and academic_year are required", http.StatusBadRequest)
+		return
+	}
+	f := Filter{Department: q.Get("department")}
+	for _, id := range strings.Split(q.Get("course_id"), ",") {
+		if id = strings.ToUpper(strings.TrimSpace(id)); id != "" {
+			f.CourseIDs = append(f.CourseIDs, id)
+		}
+	}
+
+	reg, err := h.svc.BuildRegister(r.Context(), semester, academicYear, f)
+	if errors.Is(err, ErrNotReleased) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed to build register: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("register_sem%s_%s", semester, academicYear)
+	if f.Department != "" {
+		filename += "_" + f.Department
+	}
+
+	switch format := strings.ToLower(q.Get("format")); format {
+	case "", "json":
+		writeJSON(w, reg, http.StatusOK)
+	case "csv":
+		data, err := reg.CSV()
+		if err != nil {
+			http.Error(w, "failed to render csv", http.StatusInternalServerError)
+			return
+		}
+		writeFile(w, data, "text/csv", filename+".csv")
+	case "xlsx":
+		data, err := reg.XLSX()
+		if err != nil {
+			http.Error(w, "failed to render xlsx", http.StatusInternalServerError)
+			return
+		}
+		writeFile(w, data, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", filename+".xlsx")
+	default:
+		http.Error(w, "unsupported format "+format, http.StatusBadRequest)
+	}
+}
+
+func writeFile(w http.ResponseWriter, data []byte, contentType, filename string) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+filename+"\"")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func RegisterTabulationRoutes(r *mux.Router, svc *Service) {
+	r.HandleFunc("/authority/results/register", NewHandler(svc).Export).Methods("GET")
+}
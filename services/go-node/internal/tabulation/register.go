@@ -0,0 +1,142 @@
+package tabulation
+
+import (
+	"sort"
+	"time"
+
+	"digital-eval-system/services/go-node/internal/course"
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/grading"
+	"digital-eval-system/services/go-node/internal/student"
+)
+
+// Column is one course column of the register.
+type Column struct {
+	CourseCode string `json:"course_code"`
+	CourseName string `json:"course_name"`
+	Credits    int    `json:"credits"`
+	Department string `json:"department,omitempty"`
+}
+
+// Cell is a student's outcome in one course.
+type Cell struct {
+	Marks      int     `json:"marks"`
+	TotalMarks int     `json:"total_marks"`
+	Grade      string  `json:"grade"`
+	GradePoint float64 `json:"grade_point"`
+	Result     string  `json:"result"`
+}
+
+// Row is one student line of the register.
+type Row struct {
+	USN           string          `json:"usn"`
+	Courses       map[string]Cell `json:"courses"`
+	SGPA          float64         `json:"sgpa"`
+	CreditsEarned int             `json:"credits_earned"`
+	Result        string          `json:"result"`
+}
+
+// Register is the semester tabulation register.
+type Register struct {
+	Semester     string    `json:"semester"`
+	AcademicYear string    `json:"academic_year"`
+	Department   string    `json:"department,omitempty"`
+	Regulation   string    `json:"regulation,omitempty"`
+	ReleasedAt   time.Time `json:"released_at"`
+	BlockHash    string    `json:"release_block_hash,omitempty"`
+	Columns      []Column  `json:"columns"`
+	Rows         []Row     `json:"rows"`
+	GeneratedAt  time.Time `json:"generated_at"`
+}
+
+// Filter narrows the register to a department and/or set of courses. The
+// filter picks the course columns and the students shown; SGPA and the
+// overall result always cover the student's whole semester.
+type Filter struct {
+	Department string
+	CourseIDs  []string
+}
+
+func (f Filter) includes(c course.Course, code string) bool {
+	if f.Department != "" && c.Department != f.Department {
+		return false
+	}
+	if len(f.CourseIDs) == 0 {
+		return true
+	}
+	for _, id := range f.CourseIDs {
+		if id == code {
+			return true
+		}
+	}
+	return false
+}
+
+// buildRegister tabulates rows into one line per student. rows are ordered
+// oldest first, so a later evaluation of the same course replaces an earlier
+// one, and must already carry catalog credits.
+func buildRegister(rows []db.EvaluationRow, cat course.Catalog, scheme *grading.Scheme, f Filter) ([]Column, []Row) {
+	perStudent := map[string]map[string]db.EvaluationRow{}
+	for _, r := range rows {
+		if !r.StudentUSN.Valid || r.StudentUSN.String == "" {
+			continue
+		}
+		usn := r.StudentUSN.String
+		if perStudent[usn] == nil {
+			perStudent[usn] = map[string]db.EvaluationRow{}
+		}
+		perStudent[usn][r.CourseID] = r
+	}
+
+	columnSet := map[string]Column{}
+	var out []Row
+	for usn, byCourse := range perStudent {
+		all := make([]db.EvaluationRow, 0, len(byCourse))
+		row := Row{USN: usn, Courses: map[string]Cell{}, Result: grading.ResultPass}
+		for code, r := range byCourse {
+			all = append(all, r)
+			g := student.RowGrade(r, scheme)
+			credits := 0
+			if r.CourseCredits.Valid {
+				credits = int(r.CourseCredits.Int32)
+			}
+			if r.Result == grading.ResultPass {
+				row.CreditsEarned += credits
+			} else {
+				row.Result = grading.ResultFail
+			}
+
+			c, _ := cat.Get(code)
+			if !f.includes(c, code) {
+				continue
+			}
+			if _, ok := columnSet[code]; !ok {
+				name := c.CourseName
+				if name == "" {
+					name = "-"
+				}
+				columnSet[code] = Column{CourseCode: code, CourseName: name, Credits: credits, Department: c.Department}
+			}
+			row.Courses[code] = Cell{
+				Marks:      student.RowMarksScored(r),
+				TotalMarks: r.TotalMarks,
+				Grade:      g.Letter,
+				GradePoint: g.GradePoint,
+				Result:     r.Result,
+			}
+		}
+		if len(row.Courses) == 0 {
+			continue
+		}
+		row.SGPA = student.CalculateSGPA(all, scheme)
+		out = append(out, row)
+	}
+
+	cols := make([]Column, 0, len(columnSet))
+	for _, c := range columnSet {
+		cols = append(cols, c)
+	}
+	sort.Slice(cols, func(i, j int) bool { return cols[i].CourseCode < cols[j].CourseCode })
+	sort.Slice(out, func(i, j int) bool { return out[i].USN < out[j].USN })
+	return cols, out
+}
@@ -0,0 +1,77 @@
+package tabulation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"digital-eval-system/services/go-node/internal/course"
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/grading"
+)
+
+// ErrNotReleased is returned when a register is requested for a semester that
+// has not been released.
+var ErrNotReleased = errors.New("results for this semester have not been released")
+
+// Service builds tabulation registers from released evaluations.
+type Service struct {
+	pg      *db.PostgresDB
+	grading *grading.Service
+	courses *course.Service
+}
+
+func NewService(pg *db.PostgresDB, gradingSvc *grading.Service, courseSvc *course.Service) *Service {
+	return &Service{pg: pg, grading: gradingSvc, courses: courseSvc}
+}
+
+// BuildRegister returns the tabulation register of a released semester.
+func (s *Service) BuildRegister(ctx context.Context, semester, academicYear string, f Filter) (*Register, error) {
+	rel, err := s.pg.GetRelease(ctx, semester, academicYear)
+	if err != nil {
+		return nil, err
+	}
+	if rel == nil {
+		return nil, ErrNotReleased
+	}
+
+	rows, err := s.pg.FetchResultsBySemester(ctx, semester, academicYear)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, len(rows))
+	for _, r := range rows {
+		codes = append(codes, r.CourseID)
+	}
+	cat, err := s.courses.Catalog(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		if c, ok := cat.Get(rows[i].CourseID); ok {
+			rows[i].CourseCredits = sql.NullInt32{Int32: int32(c.Credits), Valid: true}
+		}
+	}
+
+	regulation := cat.Regulation(codes...)
+	scheme := s.grading.Resolve(ctx, regulation, academicYear)
+	cols, lines := buildRegister(rows, cat, scheme, f)
+
+	reg := &Register{
+		Semester:     semester,
+		AcademicYear: academicYear,
+		Department:   f.Department,
+		Regulation:   regulation,
+		ReleasedAt:   rel.ReleasedAt,
+		BlockHash:    rel.BlockHash.String,
+		Columns:      cols,
+		Rows:         lines,
+		GeneratedAt:  time.Now().UTC(),
+	}
+	if reg.Rows == nil {
+		reg.Rows = []Row{}
+	}
+	return reg, nil
+}
@@ -0,0 +1,110 @@
+package valuation
+
+import (
+	"fmt"
+	"math"
+)
+
+// Outcome states of a script under double valuation.
+const (
+	StatusAwaiting      = "awaiting_valuation"
+	StatusThirdRequired = "third_valuation_required"
+	StatusFinal         = "final"
+)
+
+// Valuation is one evaluator's marks for a script.
+type Valuation struct {
+	Round       int    `json:"round"`
+	EvaluatorID string `json:"evaluator_id"`
+	Score       int    `json:"score"`
+	MarksScored []int  `json:"marks_scored"`
+	BlockHash   string `json:"block_hash"`
+}
+
+// Outcome is the result of applying a policy to the valuations received so far.
+type Outcome struct {
+	Status      string  `json:"status"`
+	Discrepancy int     `json:"discrepancy"`
+	Threshold   float64 `json:"threshold"`
+	FinalScore  int     `json:"final_score,omitempty"`
+	MarksScored []int   `json:"marks_scored,omitempty"`
+	Rounds      []int   `json:"rounds_used,omitempty"`
+}
+
+// Decide applies p to the valuations of a script (ordered by round). Two
+// valuations within the threshold are aggregated; otherwise a third valuation
+// is required, and once present it is aggregated with whichever of the first
+// two is closer to it.
+func Decide(p *Policy, vals []Valuation, totalMarks int) (Outcome, error) {
+	out := Outcome{Status: StatusAwaiting, Threshold: p.Threshold() * float64(totalMarks) / 100}
+	if len(vals) < p.Rounds() {
+		return out, nil
+	}
+	if !p.Double() {
+		out.Status = StatusFinal
+		out.FinalScore = vals[0].Score
+		out.MarksScored = vals[0].MarksScored
+		out.Rounds = []int{vals[0].Round}
+		return out, nil
+	}
+
+	a, b := vals[0], vals[1]
+	out.Discrepancy = abs(a.Score - b.Score)
+	if len(vals) == 2 {
+		if float64(out.Discrepancy) > out.Threshold {
+			out.Status = StatusThirdRequired
+			return out, nil
+		}
+	} else {
+		third := vals[2]
+		// the nearer valuation wins; on a tie the higher one benefits the student
+		near := a
+		da, db := abs(third.Score-a.Score), abs(third.Score-b.Score)
+		if db < da || (db == da && b.Score > a.Score) {
+			near = b
+		}
+		a, b = near, third
+	}
+
+	score, marks, err := aggregate(p.Aggregation, a, b)
+	if err != nil {
+		return out, err
+	}
+	out.Status = StatusFinal
+	out.FinalScore = score
+	out.MarksScored = marks
+	out.Rounds = []int{a.Round, b.Round}
+	return out, nil
+}
+
+// aggregate combines two valuations. Averages round half up, per question
+// and for the total.
+func aggregate(mode string, a, b Valuation) (int, []int, error) {
+	switch mode {
+	case AggregateMax:
+		if b.Score > a.Score {
+			return b.Score, b.MarksScored, nil
+		}
+		return a.Score, a.MarksScored, nil
+	case AggregateAverage:
+		if len(a.MarksScored) != len(b.MarksScored) {
+			return 0, nil, fmt.Errorf("valuations %d and %d have different question counts", a.Round, b.Round)
+		}
+		marks := make([]int, len(a.MarksScored))
+		for i := range marks {
+			marks[i] = halfUp(float64(a.MarksScored[i]+b.MarksScored[i]) / 2)
+		}
+		return halfUp(float64(a.Score+b.Score) / 2), marks, nil
+	default:
+		return 0, nil, fmt.Errorf("unknown aggregation %q", mode)
+	}
+}
+
+func halfUp(v float64) int { return int(math.Floor(v + 0.5)) }
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,128 @@
+package valuation
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler exposes valuation policy and third valuation endpoints.
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GET /api/v1/admin/valuation/policies
+func (h *Handler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := h.svc.List(r.Context())
+	if err != nil {
+		http.Error(w, "failed to load policies", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, policies, http.StatusOK)
+}
+
+// POST /api/v1/admin/valuation/policies
+// Creates the policy or replaces the one stored for the same course + semester.
+func (h *Handler) SavePolicy(w http.ResponseWriter, r *http.Request) {
+	var p Policy
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	p.Normalize()
+	if err := p.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	id, err := h.svc.Save(r.Context(), &p)
+	if err != nil {
+		http.Error(w, "save failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"id": id}, http.StatusOK)
+}
+
+// DELETE /api/v1/admin/valuation/policies/{id}
+func (h *Handler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		http.Error(w, "delete failed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "deleted"}, http.StatusOK)
+}
+
+// GET /api/v1/admin/valuation/resolve?course_id=...&semester=...
+// Shows which policy applies (stored or single-valuation default).
+func (h *Handler) ResolvePolicy(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	writeJSON(w, h.svc.Resolve(r.Context(), q.Get("course_id"), q.Get("semester")), http.StatusOK)
+}
+
+// GET /api/v1/authority/valuations/pending-third
+// Scripts whose valuations disagreed but could not be given a third evaluator automatically.
+func (h *Handler) PendingThird(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.svc.PendingThird(r.Context())
+	if err != nil {
+		http.Error(w, "failed to load pending third valuations", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rows, http.StatusOK)
+}
+
+// GET /api/v1/authority/valuations/{script_id}
+func (h *Handler) ScriptValuations(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.svc.Valuations(r.Context(), mux.Vars(r)["script_id"])
+	if err != nil {
+		http.Error(w, "failed to load valuations", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rows, http.StatusOK)
+}
+
+// POST /api/v1/authority/valuations/{script_id}/third
+// body: {"evaluator_id": "..."} (optional; least loaded course evaluator when empty)
+func (h *Handler) AssignThird(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		EvaluatorID string `json:"evaluator_id"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+	}
+	evaluatorID, err := h.svc.AssignThird(r.Context(), mux.Vars(r)["script_id"], body.EvaluatorID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	writeJSON(w, map[string]string{"evaluator_id": evaluatorID, "status": "assigned"}, http.StatusOK)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func RegisterValuationRoutes(r *mux.Router, svc *Service) {
+	h := NewHandler(svc)
+	r.HandleFunc("/admin/valuation/policies", h.ListPolicies).Methods("GET")
+	r.HandleFunc("/admin/valuation/policies", h.SavePolicy).Methods("POST")
+	r.HandleFunc("/admin/valuation/policies/{id}", h.DeletePolicy).Methods("DELETE")
+	r.HandleFunc("/admin/valuation/resolve", h.ResolvePolicy).Methods("GET")
+	r.HandleFunc("/authority/valuations/pending-third", h.PendingThird).Methods("GET")
+	r.HandleFunc("/authority/valuations/{script_id}", h.ScriptValuations).Methods("GET")
+	r.HandleFunc("/authority/valuations/{script_id}/third", h.AssignThird).Methods("POST")
+}
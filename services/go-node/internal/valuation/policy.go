@@ -0,0 +1,100 @@
+package valuation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Valuation modes.
+const (
+	ModeSingle = "single"
+	ModeDouble = "double"
+)
+
+// Ways of deriving the final marks from two valuations.
+const (
+	AggregateAverage = "average"
+	AggregateMax     = "max"
+)
+
+// DefaultThresholdPercent is the discrepancy (in % of total marks) above which
+// a third valuation is triggered when a policy does not set one.
+const DefaultThresholdPercent = 15
+
+// Policy decides how many independent valuations a script needs and how the
+// final marks are derived. CourseID and Semester scope the policy; empty
+// values match any course / semester. ThresholdPercent is a pointer so an
+// explicit 0 (any discrepancy triggers a third valuation) differs from unset.
+type Policy struct {
+	ID               int64    `json:"id,omitempty"`
+	CourseID         string   `json:"course_id"`
+	Semester         string   `json:"semester"`
+	Mode             string   `json:"mode"`
+	Aggregation      string   `json:"aggregation"`
+	ThresholdPercent *float64 `json:"threshold_percent"`
+}
+
+// Default is the policy used when nothing is configured: one valuation.
+func Default() *Policy {
+	t := float64(DefaultThresholdPercent)
+	return &Policy{Mode: ModeSingle, Aggregation: AggregateAverage, ThresholdPercent: &t}
+}
+
+// Normalize trims the scope and fills defaults.
+func (p *Policy) Normalize() {
+	p.CourseID = strings.ToUpper(strings.TrimSpace(p.CourseID))
+	p.Semester = strings.TrimSpace(p.Semester)
+	p.Mode = strings.ToLower(strings.TrimSpace(p.Mode))
+	p.Aggregation = strings.ToLower(strings.TrimSpace(p.Aggregation))
+	if p.Mode == "" {
+		p.Mode = ModeSingle
+	}
+	if p.Aggregation == "" {
+		p.Aggregation = AggregateAverage
+	}
+	if p.ThresholdPercent == nil {
+		t := float64(DefaultThresholdPercent)
+		p.ThresholdPercent = &t
+	}
+}
+
+// Validate checks mode, aggregation and threshold.
+func (p *Policy) Validate() error {
+	switch p.Mode {
+	case ModeSingle, ModeDouble:
+	default:
+		return fmt.Errorf("mode must be %q or %q", ModeSingle, ModeDouble)
+	}
+	switch p.Aggregation {
+	case AggregateAverage, AggregateMax:
+	default:
+		return fmt.Errorf("aggregation must be %q or %q", AggregateAverage, AggregateMax)
+	}
+	if t := p.Threshold(); t < 0 || t > 100 {
+		return fmt.Errorf("threshold_percent must be between 0 and 100")
+	}
+	return nil
+}
+
+// Threshold is the discrepancy threshold in % of total marks, the default
+// when unset.
+func (p *Policy) Threshold() float64 {
+	if p.ThresholdPercent == nil {
+		return DefaultThresholdPercent
+	}
+	return *p.ThresholdPercent
+}
+
+// Double reports whether scripts need two independent valuations.
+func (p *Policy) Double() bool {
+	return p != nil && p.Mode == ModeDouble
+}
+
+// Rounds is the number of valuations assigned up front (third valuations are
+// only assigned on a discrepancy).
+func (p *Policy) Rounds() int {
+	if p.Double() {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,213 @@
+package valuation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"digital-eval-system/services/go-node/internal/conflict"
+	"digital-eval-system/services/go-node/internal/db"
+	"digital-eval-system/services/go-node/internal/deadline"
+)
+
+// Service stores valuation policies and manages multi-valuation assignments.
+type Service struct {
+	pg        *db.PostgresDB
+	deadlines *deadline.Service
+	conflicts *conflict.Service
+}
+
+func NewService(pg *db.PostgresDB, deadlineSvc *deadline.Service, conflictSvc *conflict.Service) *Service {
+	return &Service{pg: pg, deadlines: deadlineSvc, conflicts: conflictSvc}
+}
+
+// Resolve returns the policy for a course and semester, falling back to
+// single valuation when nothing is stored (or the lookup fails).
+func (s *Service) Resolve(ctx context.Context, courseID, semester string) *Policy {
+	if s == nil || s.pg == nil {
+		return Default()
+	}
+	row, err := s.pg.FindValuationPolicy(ctx, strings.ToUpper(strings.TrimSpace(courseID)), strings.TrimSpace(semester))
+	if err != nil {
+		logrus.Warnf("valuation policy lookup failed (course=%q semester=%q): %v", courseID, semester, err)
+		return Default()
+	}
+	if row == nil {
+		return Default()
+	}
+	return fromRow(*row)
+}
+
+// List returns every stored policy.
+func (s *Service) List(ctx context.Context) ([]*Policy, error) {
+	rows, err := s.pg.ListValuationPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*Policy, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, fromRow(r))
+	}
+	return out, nil
+}
+
+// Save validates and stores a policy, replacing the one with the same scope.
+func (s *Service) Save(ctx context.Context, p *Policy) (int64, error) {
+	p.Normalize()
+	if err := p.Validate(); err != nil {
+		return 0, err
+	}
+	return s.pg.UpsertValuationPolicy(ctx, db.ValuationPolicyRow{
+		CourseID:         p.CourseID,
+		Semester:         p.Semester,
+		Mode:             p.Mode,
+		Aggregation:      p.Aggregation,
+		ThresholdPercent: p.Threshold(),
+	})
+}
+
+// Delete removes a stored policy.
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	return s.pg.DeleteValuationPolicy(ctx, id)
+}
+
+// Valuations returns every valuation of a script.
+func (s *Service) Valuations(ctx context.Context, scriptID string) ([]db.ValuationRow, error) {
+	return s.pg.ListValuationsByScript(ctx, scriptID)
+}
+
+// Outcome loads the valuations of a script and applies the course policy.
+func (s *Service) Outcome(ctx context.Context, p *Policy, scriptID string, totalMarks int) ([]Valuation, Outcome, error) {
+	rows, err := s.pg.ListValuationsByScript(ctx, scriptID)
+	if err != nil {
+		return nil, Outcome{}, err
+	}
+	vals := make([]Valuation, 0, len(rows))
+	for _, r := range rows {
+		var m struct {
+			MarksScored []int `json:"marks_scored"`
+		}
+		_ = json.Unmarshal(r.Marks, &m)
+		vals = append(vals, Valuation{
+			Round:       r.ValuationRound,
+			EvaluatorID: r.EvaluatorID,
+			Score:       r.Score,
+			MarksScored: m.MarksScored,
+			BlockHash:   r.BlockHash,
+		})
+	}
+	out, err := Decide(p, vals, totalMarks)
+	return vals, out, err
+}
+
+// PendingThird lists scripts waiting for a third valuation evaluator.
+func (s *Service) PendingThird(ctx context.Context) ([]db.PendingThirdValuationRow, error) {
+	return s.pg.ListPendingThirdValuations(ctx)
+}
+
+// AssignThird assigns the third valuation of a script. With an empty
+// evaluatorID the least loaded evaluator of the course who has not valued the
+// script yet and has no conflict of interest with it is picked. The script's
+// valuations must show a discrepancy beyond the policy threshold. Returns the
+// assigned evaluator.
+func (s *Service) AssignThird(ctx context.Context, scriptID, evaluatorID string) (string, error) {
+	if err := s.requireThird(ctx, scriptID); err != nil {
+		return "", err
+	}
+	active, err := s.pg.ListActiveAssignmentsByScript(ctx, scriptID)
+	if err != nil {
+		return "", err
+	}
+	if len(active) == 0 {
+		return "", fmt.Errorf("script %s has no valuation assignments", scriptID)
+	}
+	first := active[0]
+	for _, a := range active {
+		if a.ValuationRound == 3 {
+			return "", fmt.Errorf("script %s already has a third valuation assigned to %s", scriptID, a.Evaluator)
+		}
+		if a.Evaluator == evaluatorID {
+			return "", fmt.Errorf("evaluator %s already valued script %s", evaluatorID, scriptID)
+		}
+	}
+
+	if evaluatorID == "" {
+		evaluatorID, err = s.conflicts.PickEvaluator(ctx, first.CourseID, first.Semester, scriptID)
+		if err != nil {
+			return "", err
+		}
+		if evaluatorID == "" {
+			return "", fmt.Errorf("no independent evaluator available for course %s", first.CourseID)
+		}
+	} else if e, err := s.conflicts.Conflict(ctx, evaluatorID, scriptID); err != nil {
+		return "", err
+	} else if e != nil {
+		return "", fmt.Errorf("evaluator %s cannot value script %s: %s", evaluatorID, scriptID, e.Reason)
+	}
+
+	if _, err := s.pg.CreateAssignment(ctx, scriptID, evaluatorID, first.CourseID, first.Semester, first.AcademicYear, first.CourseCredits, 3, s.deadlines.DueAt(0)); err != nil {
+		return "", fmt.Errorf("create third valuation assignment: %w", err)
+	}
+	return evaluatorID, nil
+}
+
+// requireThird returns an error unless the recorded valuations of a script
+// differ by more than its policy allows.
+func (s *Service) requireThird(ctx context.Context, scriptID string) error {
+	rows, err := s.pg.ListValuationsByScript(ctx, scriptID)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("script %s has no recorded valuations", scriptID)
+	}
+	p := s.Resolve(ctx, rows[0].CourseID, rows[0].Semester)
+	_, out, err := s.Outcome(ctx, p, scriptID, rows[0].TotalMarks)
+	if err != nil {
+		return err
+	}
+	if out.Status != StatusThirdRequired {
+		return fmt.Errorf("script %s does not need a third valuation (status %s)", scriptID, out.Status)
+	}
+	return nil
+}
+
+// CloseRounds revokes the open assignments of a script's other valuation
+// rounds, once the script has been finalised without them. Returns the
+// number revoked.
+func (s *Service) CloseRounds(ctx context.Context, scriptID string, keep int64, reason string) (int, error) {
+	active, err := s.pg.ListActiveAssignmentsByScript(ctx, scriptID)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, a := range active {
+		if a.ID == keep || (a.Status != "assigned" && a.Status != "in_progress") {
+			continue
+		}
+		if err := s.pg.RevokeAssignment(ctx, a.ID, reason); err != nil {
+			if err == sql.ErrNoRows {
+				continue // submitted or revoked meanwhile
+			}
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+func fromRow(r db.ValuationPolicyRow) *Policy {
+	threshold := r.ThresholdPercent
+	return &Policy{
+		ID:               r.ID,
+		CourseID:         r.CourseID,
+		Semester:         r.Semester,
+		Mode:             r.Mode,
+		Aggregation:      r.Aggregation,
+		ThresholdPercent: &threshold,
+	}
+}
@@ -31,6 +31,7 @@ USN_REGEX = r"[0-9][A-Z]{2}[0-9]{2}[A-Z]{2}[0-9]{3}"
 CID_REGEX = r"[A-Z]{3}[0-9]{3}"
 SEM_REGEX = r"[1-8]"
 DATE_REGEX = r"\d{1,2}[-/]\d{1,2}[-/]\d{4}"
+DUMMY_REGEX = r"DN[0-9]{8}"  # dummy number stamped on masked copies (masker.py)
 
 # prompts (kept from original)
 META_PROMPT = """
@@ -111,6 +112,21 @@ def image_to_bytes(img, fmt="PNG"):
     pil.save(bio, format=fmt)
     return bio.getvalue()
 
+# Dummy number from the text layer of a masked copy
+def pdf_dummy_number(pdf_path: str) -> str:
+    """
+    Return the dummy number masker.py stamps ("Script DN........") on the
+    first page, "" if the page carries none.
+    """
+    import fitz  # PyMuPDF
+    doc = fitz.open(pdf_path)
+    try:
+        text = doc.load_page(0).get_text().upper()
+    finally:
+        doc.close()
+    found = re.findall(DUMMY_REGEX, text)
+    return found[0] if found else ""
+
 # PDF first page to image
 def pdf_first_page_to_image(pdf_path: str, out_png: str) -> str:
     import fitz  # PyMuPDF
@@ -257,6 +273,10 @@ def process_input_file(input_path: str, pdf_out_dir: str, meta_out_dir: str, upl
             img_path = os.path.join(tmpdir, "first.png")
             pdf_first_page_to_image(str(p), img_path)
             meta = extract_metadata_from_image(img_path)
+            # an evaluator's annotated copy of a masked script carries its dummy number
+            dummy = pdf_dummy_number(str(p))
+            if dummy:
+                meta["DummyNumber"] = dummy
             usn = safe_filename(meta.get("USN", "UNKNOWN_USN"))
             course = meta.get("CourseID", "UNKNOWN")
             # create saved pdf copy (copy original but ensure naming)
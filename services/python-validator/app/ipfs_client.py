@@ -48,3 +48,14 @@ def add_file(filepath: str, timeout: Optional[int] = None) -> str:
         except Exception:
             continue
     raise RuntimeError("IPFS add returned no CID")
+
+def cat_file(cid: str, timeout: Optional[int] = None) -> bytes:
+    """
+    Fetch the content of a CID via IPFS HTTP API `/cat`.
+    Raises requests.HTTPError on failure.
+    """
+    url = IPFS_API.rstrip("/") + "/cat"
+    to = timeout or IPFS_TIMEOUT
+    resp = requests.post(url, params={"arg": cid}, timeout=to)
+    resp.raise_for_status()
+    return resp.content
@@ -2,6 +2,7 @@
 FastAPI service exposing:
 - POST /extract  : accepts JSON {file_path: "..."} returns extracted metadata + CID
 - POST /validate : accepts metadata to validate against schema
+- POST /mask     : accepts JSON {cid, label} returns the CID of a copy without student identifiers
 """
 
 import os
@@ -11,8 +12,9 @@ from fastapi.responses import JSONResponse
 from pydantic import ValidationError
 from datetime import datetime, timezone
 
-from schema import ExtractRequest, ExtractResponse, ValidateRequest, ValidateResponse, Metadata
+from schema import ExtractRequest, ExtractResponse, ValidateRequest, ValidateResponse, Metadata, MaskRequest, MaskResponse
 from extractor import process_input_file
+from masker import mask_pdf
 from validator import normalize_meta, validate_meta
 import uvicorn
 
@@ -54,5 +56,17 @@ async def validate(req: ValidateRequest):
     valid, errors = validate_meta(norm)
     return {"status": "ok" if valid else "error", "valid": valid, "errors": errors}
 
+@app.post("/mask", response_model=MaskResponse)
+async def mask(req: MaskRequest):
+    if not req.cid:
+        raise HTTPException(status_code=400, detail="cid is required")
+    try:
+        masked = mask_pdf(req.cid, req.label, req.header_fraction)
+    except ValueError as e:
+        raise HTTPException(status_code=400, detail=str(e))
+    except Exception as e:
+        raise HTTPException(status_code=500, detail=str(e))
+    return {"status": "success", "masked_cid": masked}
+
 if __name__ == "__main__":
     uvicorn.run(app, host="127.0.0.1", port=8081)
\ No newline at end of file
@@ -0,0 +1,60 @@
+"""
+masker.py
+Blind evaluation: produce a copy of an answer script PDF with the student
+identifiers removed, so evaluators mark it knowing only its dummy number.
+"""
+
+import os
+import shutil
+import tempfile
+from typing import Optional
+
+from ipfs_client import add_file, cat_file
+
+# the header band the extractor reads USN / institute from (see crop_header_regions)
+DEFAULT_HEADER_FRACTION = 0.28
+
+
+def mask_pdf_bytes(data: bytes, label: Optional[str] = None, header_fraction: Optional[float] = None) -> bytes:
+    """
+    Redact the header band of the first page (text and image pixels), stamp
+    the dummy number in its place and drop document metadata.
+    """
+    import fitz  # PyMuPDF
+
+    frac = header_fraction or DEFAULT_HEADER_FRACTION
+    if not 0 < frac <= 1:
+        raise ValueError("header_fraction must be in (0, 1]")
+
+    doc = fitz.open(stream=data, filetype="pdf")
+    try:
+        page = doc.load_page(0)
+        r = page.rect
+        band = fitz.Rect(r.x0, r.y0, r.x1, r.y0 + r.height * frac)
+        page.add_redact_annot(band, fill=(1, 1, 1))
+        page.apply_redactions(images=fitz.PDF_REDACT_IMAGE_PIXELS)
+        if label:
+            page.insert_text((r.x0 + 36, r.y0 + 36), f"Script {label}", fontsize=14)
+        # author / title / producer fields may carry the original file name
+        doc.set_metadata({})
+        doc.del_xml_metadata()
+        return doc.tobytes(garbage=4, deflate=True)
+    finally:
+        doc.close()
+
+
+def mask_pdf(cid: str, label: Optional[str] = None, header_fraction: Optional[float] = None) -> str:
+    """
+    Fetch a script PDF from IPFS, mask it and add the masked copy.
+    Returns the CID of the masked copy.
+    """
+    masked = mask_pdf_bytes(cat_file(cid), label, header_fraction)
+    tmpdir = tempfile.mkdtemp(prefix="mask_")
+    try:
+        # the file name must not reveal the student either
+        path = os.path.join(tmpdir, f"{label or 'script'}.pdf")
+        with open(path, "wb") as fh:
+            fh.write(masked)
+        return add_file(path)
+    finally:
+        shutil.rmtree(tmpdir, ignore_errors=True)
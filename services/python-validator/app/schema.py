@@ -13,6 +13,7 @@ class Metadata(BaseModel):
     CourseName: Optional[str] = Field(None, description="Course name (optional)")
     Date: Optional[str] = Field(None, description="Exam or script date")
     Institute: Optional[str] = Field(None, description="Institute name")
+    DummyNumber: Optional[str] = Field(None, description="Dummy number stamped on a masked copy")
     # allow extra fields as meta
     extra: Optional[Dict[str, str]] = None
 
@@ -36,3 +37,12 @@ class ValidateResponse(BaseModel):
     status: str
     valid: bool
     errors: Optional[Dict[str, str]] = None
+
+class MaskRequest(BaseModel):
+    cid: str
+    label: Optional[str] = Field(None, description="Dummy number stamped on the masked copy")
+    header_fraction: Optional[float] = Field(None, description="Share of the first page to redact")
+
+class MaskResponse(BaseModel):
+    status: str
+    masked_cid: str
@@ -33,6 +33,8 @@ def normalize_meta(raw: dict) -> dict:
             out["Date"] = val
         elif key.lower() in ("institute", "college"):
             out["Institute"] = val
+        elif key.lower() in ("dummynumber", "dummy_number"):
+            out["DummyNumber"] = val.upper()
         else:
             # keep other keys under meta.extra
             out.setdefault("extra", {})[key] = val
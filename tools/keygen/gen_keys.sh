@@ -1,5 +1,5 @@
 #!/usr/bin/env bash
-# gen_keys.sh - generate local TLS cert and RSA keypair for JWT (development only)
+# gen_keys.sh - generate local TLS cert and RSA keypairs for JWT and block signing (development only)
 # Usage: ./gen_keys.sh <output-dir>
 set -euo pipefail
 
@@ -43,6 +43,21 @@ else
   echo "Generated JWT keys: ${JWT_PRIV}, ${JWT_PUB}"
 fi
 
+# RSA key the node signs blocks with (block.priv_key_path), separate from the JWT key
+CHAIN_PRIV="${OUT_DIR}/chain_private.pem"
+CHAIN_PUB="${OUT_DIR}/chain_public.pem"
+
+if [ -f "${CHAIN_PRIV}" ] || [ -f "${CHAIN_PUB}" ]; then
+  echo "Chain keypair already exist, skipping generation."
+else
+  echo "Generating RSA keypair for block signing..."
+  openssl genpkey -algorithm RSA -out "${CHAIN_PRIV}" -pkeyopt rsa_keygen_bits:4096
+  openssl rsa -pubout -in "${CHAIN_PRIV}" -out "${CHAIN_PUB}"
+  chmod 600 "${CHAIN_PRIV}"
+  chmod 644 "${CHAIN_PUB}"
+  echo "Generated chain keys: ${CHAIN_PRIV}, ${CHAIN_PUB}"
+fi
+
 # Create .gitignore reminder
 GITIGNORE="${OUT_DIR}/.gitignore"
 if [ ! -f "${GITIGNORE}" ]; then